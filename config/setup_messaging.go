@@ -26,18 +26,20 @@ func InitializeRabbitMQ() {
 		log.Fatalf("An error occurred while opening the RabbitMQ channel: %s", err)
 	}
 
-	// Declare the queue
-	_, err = channel.QueueDeclare(
-		"user_deleted",
-		true,  // Durable
-		false, // Auto Delete
-		false, // Exclusive
-		false, // No Wait
-		nil,   // Arguments
-	)
-
-	if err != nil {
-		log.Fatalf("An error occurred while declaring the queue: %s", err)
+	// Declare the queues
+	for _, queue := range []string{"user_deleted", "user_email_changed", "user_password_changed", "send_email"} {
+		_, err = channel.QueueDeclare(
+			queue,
+			true,  // Durable
+			false, // Auto Delete
+			false, // Exclusive
+			false, // No Wait
+			nil,   // Arguments
+		)
+
+		if err != nil {
+			log.Fatalf("An error occurred while declaring the queue: %s", err)
+		}
 	}
 
 	RabbitMQClient = &RabbitMQ{
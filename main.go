@@ -9,7 +9,15 @@ import (
 	"flyhorizons-userservice/services"
 	"flyhorizons-userservice/services/authentication"
 	"flyhorizons-userservice/services/converter"
+	"flyhorizons-userservice/services/interfaces"
+	"flyhorizons-userservice/services/mailing"
+	"flyhorizons-userservice/services/messaging"
+	"flyhorizons-userservice/services/ratelimit"
 	"flyhorizons-userservice/services/validation"
+	"flyhorizons-userservice/utils"
+	"log"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -40,24 +48,140 @@ func main() {
 	// --- Metrics setup ---
 	metrics.RegisterMetricsRoutes(router, dbCheck, rabbitMQCheck)
 
+	// --- Client IP setup ---
+	// Proxy headers are only believed when they were added by one of the TRUSTED_PROXIES
+	ipResolver, err := utils.LoadIPResolverFromEnv()
+	if err != nil {
+		log.Fatalf("An error occurred while parsing TRUSTED_PROXIES: %s", err)
+	}
+	router.Use(ipResolver.ClientIPMiddleware())
+
+	// --- Rate limiting setup ---
+	// Buckets are kept in memory, a shared store is needed to enforce the limits across replicas
+	rateLimitStore := ratelimit.NewMemoryStore()
+	rateLimitStore.StartCleanupSchedule(10 * time.Minute)
+	rateLimiter := ratelimit.NewRateLimiter(rateLimitStore, ratelimit.LoadConfigFromEnv())
+	router.Use(rateLimiter.RateLimitMiddleware())
+
 	// --- Microservice setup ---
 	userRepo := repositories.NewUserRepository(baseRepo)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(baseRepo)
+	revocationRepo := repositories.NewTokenRevocationRepository(baseRepo)
+	clientRepo := repositories.NewOAuthClientRepository(baseRepo)
+	signingKeyRepo := repositories.NewSigningKeyRepository(baseRepo)
+	authorizationCodeRepo := repositories.NewAuthorizationCodeRepository(baseRepo)
+	mfaRepo := repositories.NewMFARepository(baseRepo)
+	webAuthnRepo := repositories.NewWebAuthnRepository(baseRepo)
+	passwordResetRepo := repositories.NewPasswordResetRepository(baseRepo)
+	emailVerificationRepo := repositories.NewEmailVerificationRepository(baseRepo)
+	emailChangeRepo := repositories.NewEmailChangeRepository(baseRepo)
+	lockoutRepo := repositories.NewAccountLockoutRepository(baseRepo)
+	passwordHistoryRepo := repositories.NewPasswordHistoryRepository(baseRepo)
 
 	// Initialize services
 	userConverter := converter.UserConverter{}
-	passwordValidator := validation.PasswordValidator{}
-	accountHashing := authentication.NewAccountHashing()
-	jwtSigner := authentication.NewJwtTokenSigner()
+	passwordValidator, err := validation.LoadPasswordValidatorFromEnv()
+	if err != nil {
+		log.Fatalf("An error occurred while loading the password policy: %s", err)
+	}
+	hashingPolicy, err := authentication.LoadHashingPolicyFromEnv()
+	if err != nil {
+		log.Fatalf("An error occurred while loading the password hashing policy: %s", err)
+	}
+	accountHashing := authentication.NewAccountHashing(hashingPolicy)
+	keyRing, err := authentication.LoadKeyRingFromEnv()
+	if err != nil {
+		log.Fatalf("An error occurred while loading the JWT signing keys: %s", err)
+	}
+	// Generated keys are shared through the database, so every instance signs and verifies with the same keys.
+	// They are encrypted with a secret that is kept out of the database.
+	if !keyRing.Active().IsSymmetric() {
+		signingKeyCipher, err := authentication.LoadSigningKeyCipherFromEnv()
+		if err != nil {
+			log.Fatalf("An error occurred while loading the JWT key encryption secret: %s", err)
+		}
+		if err := keyRing.UseStore(signingKeyRepo, signingKeyCipher); err != nil {
+			log.Fatalf("An error occurred while loading the shared JWT signing keys: %s", err)
+		}
+		keyRing.StartSyncSchedule(time.Minute)
+	}
+	if interval := os.Getenv("JWT_KEY_ROTATION_INTERVAL"); interval != "" {
+		rotationInterval, err := time.ParseDuration(interval)
+		if err != nil {
+			log.Fatalf("An error occurred while parsing JWT_KEY_ROTATION_INTERVAL: %s", err)
+		}
+		if err := keyRing.StartRotationSchedule(rotationInterval); err != nil {
+			log.Fatalf("An error occurred while scheduling the JWT key rotation: %s", err)
+		}
+	}
+	jwtSigner := authentication.NewJwtTokenSigner(keyRing)
 	oauthSigner := services.NewOAuthTokenSigner(jwtSigner)
 
+	revocationService := services.NewTokenRevocationService(revocationRepo, refreshTokenRepo)
+	revocationService.StartCleanupSchedule(time.Hour)
+
+	tokenValidator := authentication.NewJwtTokenValidator(jwtSigner)
+	introspectionService := services.NewIntrospectionService(tokenValidator, revocationService)
+	clientService := services.NewOAuthClientService(clientRepo, accountHashing, oauthSigner)
+
+	// Public URL of this service, used as issuer of ID tokens and in the discovery document
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		issuer = "http://localhost:8081"
+	}
+	// Frontend page where users sign in and approve clients of the authorization code flow
+	oauthConsentURL := os.Getenv("OAUTH_CONSENT_URL")
+	if oauthConsentURL == "" {
+		oauthConsentURL = "http://localhost:3000/oauth/consent"
+	}
+	authorizationService := services.NewAuthorizationService(userRepo, clientRepo, authorizationCodeRepo, revocationRepo, clientService, userConverter, oauthSigner, jwtSigner, issuer, oauthConsentURL)
+
 	// Authentication middlware
-	gatewayAuthMiddleware := authentication.NewGatewayAuthMiddleware()
-	loginService := services.NewLoginService(userRepo, userConverter, oauthSigner)
-	userService := services.NewUserService(userRepo, accountHashing, passwordValidator, userConverter)
+	gatewayAuthMiddleware := authentication.NewGatewayAuthMiddleware(jwtSigner, revocationService)
+	mfaService := services.NewMFAService(mfaRepo, userRepo, services.LoadMFAPolicyFromEnv())
+	webAuthnService := services.NewWebAuthnService(webAuthnRepo, userRepo, services.LoadWebAuthnConfigFromEnv())
+	lockoutService := services.NewAccountLockoutService(lockoutRepo, userRepo, services.LoadLockoutPolicyFromEnv())
+	loginService := services.NewLoginService(userRepo, refreshTokenRepo, accountHashing, mfaService, webAuthnService, lockoutService, userConverter, oauthSigner, services.LoadEmailVerificationPolicyFromEnv())
+
+	// Emails are handed to the notification service, MAILER=log writes them to the log for local development
+	var mailer interfaces.Mailer = mailing.NewRabbitMQMailer()
+	if os.Getenv("MAILER") == "log" {
+		mailer = mailing.NewLogMailer()
+	}
+	passwordResetURL := os.Getenv("PASSWORD_RESET_URL")
+	if passwordResetURL == "" {
+		passwordResetURL = "http://localhost:3000/reset-password"
+	}
+	passwordHistoryService := services.NewPasswordHistoryService(passwordHistoryRepo, accountHashing)
+	passwordResetService := services.NewPasswordResetService(userRepo, passwordResetRepo, revocationService, mailer, accountHashing, *passwordValidator, passwordHistoryService, passwordResetURL)
+	passwordResetService.StartResetQueue()
+	passwordCheckService := services.NewPasswordCheckService(*passwordValidator, validation.NewStrengthEstimator())
+	// Verification links point straight at this service
+	emailVerificationURL := os.Getenv("EMAIL_VERIFICATION_URL")
+	if emailVerificationURL == "" {
+		emailVerificationURL = issuer + "/users/verify"
+	}
+	emailVerificationService := services.NewEmailVerificationService(userRepo, emailVerificationRepo, mailer, emailVerificationURL)
+	userService := services.NewUserService(userRepo, accountHashing, *passwordValidator, userConverter, emailVerificationService)
+	emailChangeService := services.NewEmailChangeService(userRepo, emailChangeRepo, revocationService, mailer, messaging.NewRabbitMQEventPublisher(), issuer+"/users/email/confirm", issuer+"/users/email/revert")
+	passwordChangeService := services.NewPasswordChangeService(userRepo, revocationService, lockoutService, messaging.NewRabbitMQEventPublisher(), accountHashing, *passwordValidator, passwordHistoryService)
 
 	// Register routes
 	routes.RegisterUserRoutes(router, userService, gatewayAuthMiddleware)
 	routes.RegisterAuthRoutes(router, loginService)
+	routes.RegisterPasswordRoutes(router, passwordResetService, passwordCheckService)
+	routes.RegisterEmailVerificationRoutes(router, emailVerificationService)
+	routes.RegisterEmailChangeRoutes(router, emailChangeService, gatewayAuthMiddleware)
+	routes.RegisterPasswordChangeRoutes(router, passwordChangeService, gatewayAuthMiddleware)
+	routes.RegisterLockoutRoutes(router, lockoutService, gatewayAuthMiddleware)
+	routes.RegisterMFARoutes(router, mfaService, gatewayAuthMiddleware)
+	routes.RegisterPasskeyRoutes(router, webAuthnService, gatewayAuthMiddleware)
+	routes.RegisterJWKSRoutes(router, jwtSigner)
+	routes.RegisterKeyRoutes(router, jwtSigner, gatewayAuthMiddleware)
+	routes.RegisterSessionRoutes(router, revocationService, gatewayAuthMiddleware)
+	routes.RegisterOAuthRoutes(router, introspectionService, clientService, authorizationService, gatewayAuthMiddleware)
+	routes.RegisterOIDCRoutes(router, authorizationService, gatewayAuthMiddleware)
+	routes.RegisterInternalRoutes(router, userService, gatewayAuthMiddleware)
 
 	// Run the microservice
 	router.Run(":8081")
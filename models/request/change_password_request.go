@@ -0,0 +1,7 @@
+package request
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	RefreshToken    string `json:"refresh_token"` // Optional, keeps the session of this refresh token signed in
+}
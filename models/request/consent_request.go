@@ -0,0 +1,7 @@
+package request
+
+// The authorize request the consent page received, posted back with the decision of the user
+type ConsentRequest struct {
+	AuthorizeRequest
+	Approved bool `form:"approved"`
+}
@@ -0,0 +1,8 @@
+package request
+
+// Registration only creates regular users, the ID and account type are never taken from the request
+type CreateUserRequest struct {
+	FullName string `json:"full_name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
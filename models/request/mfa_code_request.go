@@ -0,0 +1,5 @@
+package request
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
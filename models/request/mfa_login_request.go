@@ -0,0 +1,7 @@
+package request
+
+// Second step of the login, the MFA token is returned by /login after the password was verified
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
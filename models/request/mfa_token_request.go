@@ -0,0 +1,5 @@
+package request
+
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
@@ -0,0 +1,15 @@
+package request
+
+// Assertion of the browser (PublicKeyCredential.toJSON), binary fields are base64url encoded
+type PasskeyLoginRequest struct {
+	ID       string                   `json:"id" binding:"required"`
+	Type     string                   `json:"type" binding:"required"`
+	Response PasskeyAssertionResponse `json:"response" binding:"required"`
+}
+
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+	AuthenticatorData string `json:"authenticatorData" binding:"required"`
+	Signature         string `json:"signature" binding:"required"`
+	UserHandle        string `json:"userHandle"`
+}
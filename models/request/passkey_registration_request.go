@@ -0,0 +1,14 @@
+package request
+
+// Registration response of the browser (PublicKeyCredential.toJSON), binary fields are base64url encoded
+type PasskeyRegistrationRequest struct {
+	ID       string                     `json:"id" binding:"required"`
+	Type     string                     `json:"type" binding:"required"`
+	Response PasskeyAttestationResponse `json:"response" binding:"required"`
+	Name     string                     `json:"name" binding:"max=100"` // Label shown in the list of passkeys
+}
+
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+	AttestationObject string `json:"attestationObject" binding:"required"`
+}
@@ -0,0 +1,13 @@
+package request
+
+import "flyhorizons-userservice/models/enums"
+
+// The name and email address are optional, passwords containing them are rated weaker.
+// Without an account type the password is checked against the policy of regular users.
+// Longer passwords are refused, the strength estimate grows quadratically with the length.
+type PasswordCheckRequest struct {
+	Password    string             `json:"password" binding:"required,max=256"`
+	FullName    string             `json:"full_name"`
+	Email       string             `json:"email"`
+	AccountType *enums.AccountType `json:"account_type"`
+}
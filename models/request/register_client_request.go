@@ -0,0 +1,10 @@
+package request
+
+type RegisterClientRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes"`
+	// Required for the authorization code flow
+	RedirectURIs []string `json:"redirect_uris"`
+	// Public clients do not receive a secret and authenticate with PKCE only
+	Public bool `json:"public"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// The token is taken from the link in the password reset email
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// Only the profile fields, the email address and password have their own flows and the account type is not up to the user
+type UpdateUserRequest struct {
+	ID       int    `json:"id"`
+	FullName string `json:"full_name" binding:"required"`
+}
@@ -0,0 +1,16 @@
+package response
+
+import (
+	"flyhorizons-userservice/models/enums"
+	"time"
+)
+
+// Account as listed to admins, with the account metadata the profile leaves out
+type AdminUserResponse struct {
+	ID            int               `json:"id"`
+	FullName      string            `json:"full_name"`
+	Email         string            `json:"email"`
+	EmailVerified bool              `json:"email_verified"`
+	AccountType   enums.AccountType `json:"account_type"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
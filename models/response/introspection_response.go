@@ -0,0 +1,17 @@
+package response
+
+// Token introspection response as described in RFC 7662
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Revoked   bool   `json:"revoked,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Expiry    int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	JTI       string `json:"jti,omitempty"`
+}
@@ -1,5 +1,14 @@
 package response
 
+// Accounts protected by MFA receive an MFA token instead of the access and refresh tokens,
+// accounts with an unverified email address may only receive an access token
 type LoginResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken               string `json:"access_token,omitempty"`
+	TokenType                 string `json:"token_type,omitempty"`
+	ExpiresIn                 int    `json:"expires_in,omitempty"`
+	RefreshToken              string `json:"refresh_token,omitempty"`
+	MFARequired               bool   `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired     bool   `json:"mfa_enrollment_required,omitempty"`
+	MFAToken                  string `json:"mfa_token,omitempty"`
+	EmailVerificationRequired bool   `json:"email_verification_required,omitempty"`
 }
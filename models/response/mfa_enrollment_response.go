@@ -0,0 +1,8 @@
+package response
+
+// The secret and recovery codes are only shown during enrolment, MFA is enabled once a code generated from the secret is verified
+type MFAEnrollmentResponse struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURI    string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,6 @@
+package response
+
+// Recovery codes are only shown once, every code can be used a single time instead of an authenticator code
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,9 @@
+package response
+
+// Options for navigator.credentials.get() (PublicKeyCredentialRequestOptions), the passkey is discovered by the browser
+type PasskeyLoginOptionsResponse struct {
+	Challenge        string `json:"challenge"`
+	RelyingPartyID   string `json:"rpId"`
+	Timeout          int    `json:"timeout"`
+	UserVerification string `json:"userVerification"`
+}
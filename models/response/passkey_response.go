@@ -0,0 +1,10 @@
+package response
+
+import "time"
+
+type PasskeyResponse struct {
+	CredentialID string     `json:"credential_id"`
+	Name         string     `json:"name"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+}
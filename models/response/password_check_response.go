@@ -0,0 +1,11 @@
+package response
+
+// Score runs from 0 (very weak) to 4 (very strong), violations are the rules of the password policy
+// the password breaks and must be empty before the password is accepted
+type PasswordCheckResponse struct {
+	Score       int      `json:"score"`
+	Entropy     float64  `json:"entropy"`
+	Valid       bool     `json:"valid"`
+	Violations  []string `json:"violations"`
+	Suggestions []string `json:"suggestions"`
+}
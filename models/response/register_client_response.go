@@ -0,0 +1,11 @@
+package response
+
+// The client secret is only returned once, at registration
+type RegisterClientResponse struct {
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Name         string   `json:"name"`
+	Scopes       []string `json:"scopes"`
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+	Public       bool     `json:"public"`
+}
@@ -0,0 +1,10 @@
+package response
+
+import "time"
+
+type SigningKeyResponse struct {
+	KeyID           string     `json:"kid"`
+	Algorithm       string     `json:"alg"`
+	Status          string     `json:"status"`
+	VerifiableUntil *time.Time `json:"verifiable_until,omitempty"`
+}
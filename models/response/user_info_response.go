@@ -0,0 +1,10 @@
+package response
+
+// OpenID Connect UserInfo response, claims are only included when their scope was granted
+type UserInfoResponse struct {
+	Subject       string `json:"sub"`
+	Name          string `json:"name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"` // Pointer, so an unverified address is sent as false
+
+}
@@ -0,0 +1,14 @@
+package response
+
+import "flyhorizons-userservice/models/enums"
+
+// Profile of an account as shown to its owner, the password hash never leaves the service
+type UserProfileResponse struct {
+	ID            int               `json:"id"`
+	FullName      string            `json:"full_name"`
+	Email         string            `json:"email"`
+	EmailVerified bool              `json:"email_verified"`
+	AccountType   enums.AccountType `json:"account_type"`
+	// Sent as the ETag header instead of in the body
+	Version int `json:"-"`
+}
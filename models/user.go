@@ -3,9 +3,10 @@ package models
 import "flyhorizons-userservice/models/enums"
 
 type User struct {
-	ID          int               `json:"id"`
-	FullName    string            `json:"full_name"`
-	Email       string            `json:"email"`
-	AccountType enums.AccountType `json:"account_type"`
-	Password    string            `json:"password"`
+	ID            int               `json:"id"`
+	FullName      string            `json:"full_name"`
+	Email         string            `json:"email"`
+	EmailVerified bool              `json:"email_verified"`
+	AccountType   enums.AccountType `json:"account_type"`
+	Password      string            `json:"-"` // Hash, only used inside the service
 }
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountLockoutRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.AccountLockoutRepository = (*AccountLockoutRepository)(nil)
+
+func NewAccountLockoutRepository(baseRepo *BaseRepository) *AccountLockoutRepository {
+	return &AccountLockoutRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *AccountLockoutRepository) GetByUserID(userID int) entities.AccountLockoutEntity {
+	db, _ := repo.CreateConnection()
+
+	var lockout entities.AccountLockoutEntity
+	db.Where("UserID = ?", userID).First(&lockout)
+
+	return lockout
+}
+
+// Counts a failed login in the database itself, so concurrent attempts are all counted
+func (repo *AccountLockoutRepository) IncrementFailedAttempts(userID int) entities.AccountLockoutEntity {
+	db, _ := repo.CreateConnection()
+
+	// The first failure creates the row
+	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.AccountLockoutEntity{UserID: userID})
+
+	db.Model(&entities.AccountLockoutEntity{}).
+		Where("UserID = ?", userID).
+		Updates(map[string]interface{}{
+			"FailedAttempts": gorm.Expr("FailedAttempts + 1"),
+			"LastFailedAt":   time.Now(),
+		})
+
+	return repo.GetByUserID(userID)
+}
+
+// Locks the account and starts counting again, the attempts after the lock expires are counted from zero
+func (repo *AccountLockoutRepository) Lock(userID int, lockedUntil time.Time) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.AccountLockoutEntity{}).
+		Where("UserID = ?", userID).
+		Updates(map[string]interface{}{
+			"FailedAttempts": 0,
+			"LockedUntil":    lockedUntil,
+		})
+}
+
+func (repo *AccountLockoutRepository) Reset(userID int) {
+	db, _ := repo.CreateConnection()
+
+	db.Where("UserID = ?", userID).Delete(&entities.AccountLockoutEntity{})
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+)
+
+type AuthorizationCodeRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.AuthorizationCodeRepository = (*AuthorizationCodeRepository)(nil)
+
+func NewAuthorizationCodeRepository(baseRepo *BaseRepository) *AuthorizationCodeRepository {
+	return &AuthorizationCodeRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *AuthorizationCodeRepository) Create(codeEntity entities.AuthorizationCodeEntity) entities.AuthorizationCodeEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&codeEntity)
+
+	return codeEntity
+}
+
+func (repo *AuthorizationCodeRepository) GetByCodeHash(codeHash string) entities.AuthorizationCodeEntity {
+	db, _ := repo.CreateConnection()
+
+	var code entities.AuthorizationCodeEntity
+	db.Where("CodeHash = ?", codeHash).First(&code)
+
+	return code
+}
+
+// Only marks the code when it has not been used yet, so a code can never be exchanged twice
+func (repo *AuthorizationCodeRepository) MarkAsUsed(id int, accessTokenID string) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.AuthorizationCodeEntity{}).
+		Where("ID = ? AND UsedAt IS NULL", id).
+		Updates(map[string]interface{}{"UsedAt": time.Now(), "AccessTokenID": accessTokenID})
+
+	return result.Error == nil && result.RowsAffected == 1
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+)
+
+type EmailChangeRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.EmailChangeRepository = (*EmailChangeRepository)(nil)
+
+func NewEmailChangeRepository(baseRepo *BaseRepository) *EmailChangeRepository {
+	return &EmailChangeRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *EmailChangeRepository) Create(changeEntity entities.EmailChangeRequestEntity) entities.EmailChangeRequestEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&changeEntity)
+
+	return changeEntity
+}
+
+func (repo *EmailChangeRepository) GetByConfirmTokenHash(tokenHash string) entities.EmailChangeRequestEntity {
+	db, _ := repo.CreateConnection()
+
+	var change entities.EmailChangeRequestEntity
+	db.Where("ConfirmTokenHash = ?", tokenHash).First(&change)
+
+	return change
+}
+
+func (repo *EmailChangeRepository) GetByRevertTokenHash(tokenHash string) entities.EmailChangeRequestEntity {
+	db, _ := repo.CreateConnection()
+
+	var change entities.EmailChangeRequestEntity
+	db.Where("RevertTokenHash = ?", tokenHash).First(&change)
+
+	return change
+}
+
+// Only confirms a change that was neither confirmed nor reverted, so the confirm link works once
+func (repo *EmailChangeRepository) MarkAsConfirmed(id int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.EmailChangeRequestEntity{}).
+		Where("ID = ? AND ConfirmedAt IS NULL AND RevertedAt IS NULL", id).
+		Update("ConfirmedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// Only reverts a change once, a pending change is cancelled and a confirmed one is undone by the caller
+func (repo *EmailChangeRepository) MarkAsReverted(id int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.EmailChangeRequestEntity{}).
+		Where("ID = ? AND RevertedAt IS NULL", id).
+		Update("RevertedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// Cancels the changes of the user that were not confirmed yet, so only the newest request can be confirmed
+func (repo *EmailChangeRepository) CancelPendingForUser(userID int) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.EmailChangeRequestEntity{}).
+		Where("UserID = ? AND ConfirmedAt IS NULL AND RevertedAt IS NULL", userID).
+		Update("RevertedAt", time.Now())
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+)
+
+type EmailVerificationRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.EmailVerificationRepository = (*EmailVerificationRepository)(nil)
+
+func NewEmailVerificationRepository(baseRepo *BaseRepository) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *EmailVerificationRepository) Create(tokenEntity entities.EmailVerificationTokenEntity) entities.EmailVerificationTokenEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&tokenEntity)
+
+	return tokenEntity
+}
+
+func (repo *EmailVerificationRepository) GetByTokenHash(tokenHash string) entities.EmailVerificationTokenEntity {
+	db, _ := repo.CreateConnection()
+
+	var token entities.EmailVerificationTokenEntity
+	db.Where("TokenHash = ?", tokenHash).First(&token)
+
+	return token
+}
+
+// Only marks the token when it has not been used yet, so a verification link works once
+func (repo *EmailVerificationRepository) MarkAsUsed(id int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.EmailVerificationTokenEntity{}).
+		Where("ID = ? AND UsedAt IS NULL", id).
+		Update("UsedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// Marks every unused token of the user as used, so older verification links stop working
+func (repo *EmailVerificationRepository) InvalidateAllForUser(userID int) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.EmailVerificationTokenEntity{}).
+		Where("UserID = ? AND UsedAt IS NULL", userID).
+		Update("UsedAt", time.Now())
+}
+
+// Number of verification emails sent to the user since the given time, used to throttle resends
+func (repo *EmailVerificationRepository) CountCreatedSince(userID int, since time.Time) int {
+	db, _ := repo.CreateConnection()
+
+	var count int64
+	db.Model(&entities.EmailVerificationTokenEntity{}).
+		Where("UserID = ? AND CreatedAt >= ?", userID, since).
+		Count(&count)
+
+	return int(count)
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// Failed login attempts of an account, the row is removed after a successful login
+type AccountLockoutEntity struct {
+	UserID         int        `gorm:"column:UserID;primaryKey;autoIncrement:false"`
+	FailedAttempts int        `gorm:"column:FailedAttempts"`
+	LastFailedAt   *time.Time `gorm:"column:LastFailedAt"`
+	LockedUntil    *time.Time `gorm:"column:LockedUntil"`
+}
+
+// Override the default table name
+func (AccountLockoutEntity) TableName() string {
+	return "AccountLockout"
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+type AuthorizationCodeEntity struct {
+	ID            int        `gorm:"column:ID;primaryKey"`
+	CodeHash      string     `gorm:"column:CodeHash;unique"`
+	ClientID      string     `gorm:"column:ClientID"`
+	UserID        int        `gorm:"column:UserID"`
+	RedirectURI   string     `gorm:"column:RedirectURI"`
+	Scope         string     `gorm:"column:Scope"`
+	CodeChallenge string     `gorm:"column:CodeChallenge"` // S256 PKCE challenge
+	Nonce         string     `gorm:"column:Nonce"`
+	AuthTime      time.Time  `gorm:"column:AuthTime"`
+	ExpiresAt     time.Time  `gorm:"column:ExpiresAt"`
+	UsedAt        *time.Time `gorm:"column:UsedAt"`
+	AccessTokenID string     `gorm:"column:AccessTokenID"` // JTI of the access token issued for the code
+}
+
+// Override the default table name
+func (AuthorizationCodeEntity) TableName() string {
+	return "AuthorizationCode"
+}
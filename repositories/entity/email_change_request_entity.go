@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Pending email change, the new address confirms it and the old address can revert it.
+// Only the SHA-256 hashes of both tokens are stored.
+type EmailChangeRequestEntity struct {
+	ID               int        `gorm:"column:ID;primaryKey"`
+	UserID           int        `gorm:"column:UserID"`
+	OldEmail         string     `gorm:"column:OldEmail"`
+	NewEmail         string     `gorm:"column:NewEmail"`
+	ConfirmTokenHash string     `gorm:"column:ConfirmTokenHash;unique"`
+	RevertTokenHash  string     `gorm:"column:RevertTokenHash;unique"`
+	ExpiresAt        time.Time  `gorm:"column:ExpiresAt"`       // Until when the new address can confirm
+	RevertExpiresAt  time.Time  `gorm:"column:RevertExpiresAt"` // Until when the old address can revert
+	ConfirmedAt      *time.Time `gorm:"column:ConfirmedAt"`
+	RevertedAt       *time.Time `gorm:"column:RevertedAt"`
+	CreatedAt        time.Time  `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (EmailChangeRequestEntity) TableName() string {
+	return "EmailChangeRequest"
+}
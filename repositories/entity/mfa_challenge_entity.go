@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// Issued after a successful password check, exchanged for tokens together with a second factor
+type MFAChallengeEntity struct {
+	ID        int        `gorm:"column:ID;primaryKey"`
+	TokenHash string     `gorm:"column:TokenHash;unique"`
+	UserID    int        `gorm:"column:UserID"`
+	Attempts  int        `gorm:"column:Attempts"`
+	ExpiresAt time.Time  `gorm:"column:ExpiresAt"`
+	UsedAt    *time.Time `gorm:"column:UsedAt"`
+	CreatedAt time.Time  `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (MFAChallengeEntity) TableName() string {
+	return "MFAChallenge"
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// TOTP factor of a user, the factor only protects logins once it is enabled
+type MFAFactorEntity struct {
+	UserID       int        `gorm:"column:UserID;primaryKey;autoIncrement:false"`
+	Secret       string     `gorm:"column:Secret"`
+	Enabled      bool       `gorm:"column:Enabled"`
+	LastUsedStep int64      `gorm:"column:LastUsedStep"` // Time step of the last accepted code, prevents replays
+	CreatedAt    time.Time  `gorm:"column:CreatedAt"`
+	EnabledAt    *time.Time `gorm:"column:EnabledAt"`
+}
+
+// Override the default table name
+func (MFAFactorEntity) TableName() string {
+	return "MFAFactor"
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// Single-use MFA recovery code, only the SHA-256 hash of the code is stored
+type MFARecoveryCodeEntity struct {
+	ID        int        `gorm:"column:ID;primaryKey"`
+	UserID    int        `gorm:"column:UserID"`
+	CodeHash  string     `gorm:"column:CodeHash"`
+	UsedAt    *time.Time `gorm:"column:UsedAt"`
+	CreatedAt time.Time  `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (MFARecoveryCodeEntity) TableName() string {
+	return "MFARecoveryCode"
+}
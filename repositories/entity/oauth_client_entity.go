@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+type OAuthClientEntity struct {
+	ID           int       `gorm:"column:ID;primaryKey"`
+	ClientID     string    `gorm:"column:ClientID;unique"`
+	Name         string    `gorm:"column:Name"`
+	SecretHash   string    `gorm:"column:SecretHash"`   // Empty for public clients
+	Scopes       string    `gorm:"column:Scopes"`       // Space separated list of allowed scopes
+	RedirectURIs string    `gorm:"column:RedirectURIs"` // Space separated list of registered redirect URIs
+	Public       bool      `gorm:"column:Public"`       // Public clients (mobile and browser apps) cannot keep a secret
+	CreatedAt    time.Time `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (OAuthClientEntity) TableName() string {
+	return "OAuthClient"
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// Hash of a password the account used before, kept to prevent reuse
+type PasswordHistoryEntity struct {
+	ID           int       `gorm:"column:ID;primaryKey"`
+	UserID       int       `gorm:"column:UserID"`
+	PasswordHash string    `gorm:"column:PasswordHash"`
+	CreatedAt    time.Time `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (PasswordHistoryEntity) TableName() string {
+	return "PasswordHistory"
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// Single-use password reset token, only the SHA-256 hash of the token is stored
+type PasswordResetTokenEntity struct {
+	ID        int        `gorm:"column:ID;primaryKey"`
+	TokenHash string     `gorm:"column:TokenHash;unique"`
+	UserID    int        `gorm:"column:UserID"`
+	ExpiresAt time.Time  `gorm:"column:ExpiresAt"`
+	UsedAt    *time.Time `gorm:"column:UsedAt"`
+	CreatedAt time.Time  `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (PasswordResetTokenEntity) TableName() string {
+	return "PasswordResetToken"
+}
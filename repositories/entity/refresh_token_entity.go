@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+type RefreshTokenEntity struct {
+	ID          int        `gorm:"column:ID;primaryKey"`
+	UserID      int        `gorm:"column:UserID"`
+	FamilyID    string     `gorm:"column:FamilyID"`
+	TokenHash   string     `gorm:"column:TokenHash;unique"`
+	AuthMethods string     `gorm:"column:AuthMethods"` // Space separated amr values of the login that started the family
+	ExpiresAt   time.Time  `gorm:"column:ExpiresAt"`
+	UsedAt      *time.Time `gorm:"column:UsedAt"`
+	RevokedAt   *time.Time `gorm:"column:RevokedAt"`
+	CreatedAt   time.Time  `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (RefreshTokenEntity) TableName() string {
+	return "RefreshToken"
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+type RevokedTokenEntity struct {
+	JTI       string    `gorm:"column:JTI;primaryKey"`
+	UserID    int       `gorm:"column:UserID"`
+	ExpiresAt time.Time `gorm:"column:ExpiresAt"`
+	RevokedAt time.Time `gorm:"column:RevokedAt"`
+}
+
+// Override the default table name
+func (RevokedTokenEntity) TableName() string {
+	return "RevokedToken"
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// Every token of the user issued before RevokedBefore is considered revoked
+type SessionRevocationEntity struct {
+	UserID        int       `gorm:"column:UserID;primaryKey;autoIncrement:false"`
+	RevokedBefore time.Time `gorm:"column:RevokedBefore"`
+	ExpiresAt     time.Time `gorm:"column:ExpiresAt"`
+}
+
+// Override the default table name
+func (SessionRevocationEntity) TableName() string {
+	return "SessionRevocation"
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+type SigningKeyEntity struct {
+	KeyID       string    `gorm:"column:KeyID;primaryKey"`
+	Algorithm   string    `gorm:"column:Algorithm"`
+	PrivateKey  string    `gorm:"column:PrivateKey"` // PKCS #8 PEM, encrypted with AES-256-GCM
+	ActivatedAt time.Time `gorm:"column:ActivatedAt"`
+}
+
+// Override the default table name
+func (SigningKeyEntity) TableName() string {
+	return "SigningKey"
+}
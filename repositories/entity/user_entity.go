@@ -3,12 +3,14 @@ package entities
 import "time"
 
 type UserEntity struct {
-	ID          int       `gorm:"column:ID;primaryKey"`
-	FullName    string    `gorm:"column:FullName"`
-	Email       string    `gorm:"column:Email;unique"`
-	AccountType int       `gorm:"column:AccountType"`
-	Password    string    `gorm:"column:Password"`
-	CreatedAt   time.Time `gorm:"column:CreatedAt"`
+	ID            int       `gorm:"column:ID;primaryKey"`
+	FullName      string    `gorm:"column:FullName"`
+	Email         string    `gorm:"column:Email;unique"`
+	EmailVerified bool      `gorm:"column:EmailVerified"`
+	AccountType   int       `gorm:"column:AccountType"`
+	Password      string    `gorm:"column:Password"`
+	CreatedAt     time.Time `gorm:"column:CreatedAt"`
+	Version       int       `gorm:"column:Version"`
 }
 
 // Override the default table name
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// Challenge of a registration or login ceremony, only the SHA-256 hash of the challenge is stored
+type WebAuthnChallengeEntity struct {
+	ID            int        `gorm:"column:ID;primaryKey"`
+	ChallengeHash string     `gorm:"column:ChallengeHash;unique"`
+	UserID        int        `gorm:"column:UserID"`   // 0 for login challenges, the user is only known from the passkey
+	Ceremony      string     `gorm:"column:Ceremony"` // "registration" or "login"
+	ExpiresAt     time.Time  `gorm:"column:ExpiresAt"`
+	UsedAt        *time.Time `gorm:"column:UsedAt"`
+	CreatedAt     time.Time  `gorm:"column:CreatedAt"`
+}
+
+// Override the default table name
+func (WebAuthnChallengeEntity) TableName() string {
+	return "WebAuthnChallenge"
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// Passkey registered by a user, the public key is stored in COSE format as returned by the authenticator
+type WebAuthnCredentialEntity struct {
+	ID           int        `gorm:"column:ID;primaryKey"`
+	UserID       int        `gorm:"column:UserID"`
+	CredentialID string     `gorm:"column:CredentialID;unique"` // Base64url encoded credential ID
+	PublicKey    []byte     `gorm:"column:PublicKey"`
+	SignCount    int64      `gorm:"column:SignCount"` // Last signature counter, a counter that does not increase indicates a cloned authenticator
+	Name         string     `gorm:"column:Name"`
+	CreatedAt    time.Time  `gorm:"column:CreatedAt"`
+	LastUsedAt   *time.Time `gorm:"column:LastUsedAt"`
+}
+
+// Override the default table name
+func (WebAuthnCredentialEntity) TableName() string {
+	return "WebAuthnCredential"
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MFARepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.MFARepository = (*MFARepository)(nil)
+
+func NewMFARepository(baseRepo *BaseRepository) *MFARepository {
+	return &MFARepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *MFARepository) GetFactor(userID int) entities.MFAFactorEntity {
+	db, _ := repo.CreateConnection()
+
+	var factor entities.MFAFactorEntity
+	db.Where("UserID = ?", userID).First(&factor)
+
+	return factor
+}
+
+func (repo *MFARepository) SaveFactor(factorEntity entities.MFAFactorEntity) {
+	db, _ := repo.CreateConnection()
+
+	db.Save(&factorEntity)
+}
+
+// Removes the factor together with its recovery codes
+func (repo *MFARepository) DeleteFactor(userID int) {
+	db, _ := repo.CreateConnection()
+
+	db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("UserID = ?", userID).Delete(&entities.MFARecoveryCodeEntity{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("UserID = ?", userID).Delete(&entities.MFAFactorEntity{}).Error
+	})
+}
+
+// Only accepts time steps after the last used one, so a code cannot be used twice
+func (repo *MFARepository) UseTimeStep(userID int, step int64) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.MFAFactorEntity{}).
+		Where("UserID = ? AND LastUsedStep < ?", userID, step).
+		Update("LastUsedStep", step)
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+func (repo *MFARepository) CreateChallenge(challengeEntity entities.MFAChallengeEntity) entities.MFAChallengeEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&challengeEntity)
+
+	return challengeEntity
+}
+
+func (repo *MFARepository) GetChallengeByTokenHash(tokenHash string) entities.MFAChallengeEntity {
+	db, _ := repo.CreateConnection()
+
+	var challenge entities.MFAChallengeEntity
+	db.Where("TokenHash = ?", tokenHash).First(&challenge)
+
+	return challenge
+}
+
+func (repo *MFARepository) IncrementChallengeAttempts(id int) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.MFAChallengeEntity{}).
+		Where("ID = ?", id).
+		Update("Attempts", gorm.Expr("Attempts + 1"))
+}
+
+// Only marks the challenge when it has not been used yet, so it cannot be exchanged twice
+func (repo *MFARepository) MarkChallengeAsUsed(id int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.MFAChallengeEntity{}).
+		Where("ID = ? AND UsedAt IS NULL", id).
+		Update("UsedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// Failed codes of every challenge of the user created since the given time
+func (repo *MFARepository) CountChallengeFailures(userID int, since time.Time) int {
+	db, _ := repo.CreateConnection()
+
+	var failures int64
+	db.Model(&entities.MFAChallengeEntity{}).
+		Select("COALESCE(SUM(Attempts), 0)").
+		Where("UserID = ? AND CreatedAt >= ?", userID, since).
+		Scan(&failures)
+
+	return int(failures)
+}
+
+// Marks every open challenge of the user as used, so none of them can be exchanged anymore
+func (repo *MFARepository) RevokeChallenges(userID int) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.MFAChallengeEntity{}).
+		Where("UserID = ? AND UsedAt IS NULL", userID).
+		Update("UsedAt", time.Now())
+}
+
+// Invalidates every previous recovery code of the user
+func (repo *MFARepository) ReplaceRecoveryCodes(userID int, recoveryCodes []entities.MFARecoveryCodeEntity) {
+	db, _ := repo.CreateConnection()
+
+	db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("UserID = ?", userID).Delete(&entities.MFARecoveryCodeEntity{}).Error; err != nil {
+			return err
+		}
+		if len(recoveryCodes) == 0 {
+			return nil
+		}
+		return tx.Create(&recoveryCodes).Error
+	})
+}
+
+// Only marks an unused code, so every recovery code works once
+func (repo *MFARepository) UseRecoveryCode(userID int, codeHash string) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.MFARecoveryCodeEntity{}).
+		Where("UserID = ? AND CodeHash = ? AND UsedAt IS NULL", userID, codeHash).
+		Update("UsedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+func (repo *MFARepository) CountRecoveryCodes(userID int) int {
+	db, _ := repo.CreateConnection()
+
+	var count int64
+	db.Model(&entities.MFARecoveryCodeEntity{}).
+		Where("UserID = ? AND UsedAt IS NULL", userID).
+		Count(&count)
+
+	return int(count)
+}
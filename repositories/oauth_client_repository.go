@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+)
+
+type OAuthClientRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.OAuthClientRepository = (*OAuthClientRepository)(nil)
+
+func NewOAuthClientRepository(baseRepo *BaseRepository) *OAuthClientRepository {
+	return &OAuthClientRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *OAuthClientRepository) GetByClientID(clientID string) entities.OAuthClientEntity {
+	db, _ := repo.CreateConnection()
+
+	var client entities.OAuthClientEntity
+	db.Where("ClientID = ?", clientID).First(&client)
+
+	return client
+}
+
+func (repo *OAuthClientRepository) Create(clientEntity entities.OAuthClientEntity) entities.OAuthClientEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&clientEntity)
+
+	return clientEntity
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+)
+
+type PasswordHistoryRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.PasswordHistoryRepository = (*PasswordHistoryRepository)(nil)
+
+func NewPasswordHistoryRepository(baseRepo *BaseRepository) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *PasswordHistoryRepository) Create(historyEntity entities.PasswordHistoryEntity) entities.PasswordHistoryEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&historyEntity)
+
+	return historyEntity
+}
+
+// Newest first
+func (repo *PasswordHistoryRepository) GetRecentByUserID(userID int, limit int) []entities.PasswordHistoryEntity {
+	var history []entities.PasswordHistoryEntity
+	if limit <= 0 {
+		return history
+	}
+
+	db, _ := repo.CreateConnection()
+	db.Where("UserID = ?", userID).Order("CreatedAt DESC, ID DESC").Limit(limit).Find(&history)
+
+	return history
+}
+
+// Forgets every hash of the user except for the most recent ones
+func (repo *PasswordHistoryRepository) DeleteAllButRecent(userID int, keep int) {
+	db, _ := repo.CreateConnection()
+
+	var keptIDs []int
+	for _, historyEntity := range repo.GetRecentByUserID(userID, keep) {
+		keptIDs = append(keptIDs, historyEntity.ID)
+	}
+
+	query := db.Where("UserID = ?", userID)
+	if len(keptIDs) > 0 {
+		query = query.Where("ID NOT IN ?", keptIDs)
+	}
+	query.Delete(&entities.PasswordHistoryEntity{})
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+)
+
+type PasswordResetRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.PasswordResetRepository = (*PasswordResetRepository)(nil)
+
+func NewPasswordResetRepository(baseRepo *BaseRepository) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *PasswordResetRepository) Create(tokenEntity entities.PasswordResetTokenEntity) entities.PasswordResetTokenEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&tokenEntity)
+
+	return tokenEntity
+}
+
+func (repo *PasswordResetRepository) GetByTokenHash(tokenHash string) entities.PasswordResetTokenEntity {
+	db, _ := repo.CreateConnection()
+
+	var token entities.PasswordResetTokenEntity
+	db.Where("TokenHash = ?", tokenHash).First(&token)
+
+	return token
+}
+
+// Only marks the token when it has not been used yet, so a reset link works once
+func (repo *PasswordResetRepository) MarkAsUsed(id int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.PasswordResetTokenEntity{}).
+		Where("ID = ? AND UsedAt IS NULL", id).
+		Update("UsedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// Marks every unused token of the user as used, so older reset links stop working
+func (repo *PasswordResetRepository) InvalidateAllForUser(userID int) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.PasswordResetTokenEntity{}).
+		Where("UserID = ? AND UsedAt IS NULL", userID).
+		Update("UsedAt", time.Now())
+}
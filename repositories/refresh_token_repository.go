@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+)
+
+type RefreshTokenRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
+
+func NewRefreshTokenRepository(baseRepo *BaseRepository) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *RefreshTokenRepository) Create(refreshTokenEntity entities.RefreshTokenEntity) entities.RefreshTokenEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&refreshTokenEntity)
+
+	return refreshTokenEntity
+}
+
+func (repo *RefreshTokenRepository) GetByTokenHash(tokenHash string) entities.RefreshTokenEntity {
+	db, _ := repo.CreateConnection()
+
+	var refreshToken entities.RefreshTokenEntity
+	db.Where("TokenHash = ?", tokenHash).First(&refreshToken)
+
+	return refreshToken
+}
+
+// Only marks the token when it has not been used yet, so two concurrent refreshes cannot both succeed
+func (repo *RefreshTokenRepository) MarkAsUsed(id int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.RefreshTokenEntity{}).
+		Where("ID = ? AND UsedAt IS NULL", id).
+		Update("UsedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+func (repo *RefreshTokenRepository) RevokeFamily(familyID string) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.RefreshTokenEntity{}).
+		Where("FamilyID = ? AND RevokedAt IS NULL", familyID).
+		Update("RevokedAt", time.Now())
+}
+
+func (repo *RefreshTokenRepository) RevokeAllForUser(userID int) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.RefreshTokenEntity{}).
+		Where("UserID = ? AND RevokedAt IS NULL", userID).
+		Update("RevokedAt", time.Now())
+}
+
+func (repo *RefreshTokenRepository) RevokeAllForUserExceptFamily(userID int, familyID string) {
+	db, _ := repo.CreateConnection()
+
+	db.Model(&entities.RefreshTokenEntity{}).
+		Where("UserID = ? AND FamilyID <> ? AND RevokedAt IS NULL", userID, familyID).
+		Update("RevokedAt", time.Now())
+}
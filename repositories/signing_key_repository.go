@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+)
+
+type SigningKeyRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.SigningKeyRepository = (*SigningKeyRepository)(nil)
+
+func NewSigningKeyRepository(baseRepo *BaseRepository) *SigningKeyRepository {
+	return &SigningKeyRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+// Unlike most creates the error is returned, a key that was not stored must not be used for signing
+func (repo *SigningKeyRepository) Create(keyEntity entities.SigningKeyEntity) error {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	return db.Create(&keyEntity).Error
+}
+
+// Oldest first, the last key is the active one
+func (repo *SigningKeyRepository) GetAll() []entities.SigningKeyEntity {
+	db, _ := repo.CreateConnection()
+
+	var keys []entities.SigningKeyEntity
+	db.Order("ActivatedAt ASC").Find(&keys)
+
+	return keys
+}
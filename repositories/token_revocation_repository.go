@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+type TokenRevocationRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.TokenRevocationRepository = (*TokenRevocationRepository)(nil)
+
+func NewTokenRevocationRepository(baseRepo *BaseRepository) *TokenRevocationRepository {
+	return &TokenRevocationRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *TokenRevocationRepository) RevokeToken(revokedTokenEntity entities.RevokedTokenEntity) {
+	db, _ := repo.CreateConnection()
+
+	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&revokedTokenEntity)
+}
+
+func (repo *TokenRevocationRepository) IsTokenRevoked(jti string) bool {
+	db, _ := repo.CreateConnection()
+
+	var count int64
+	db.Model(&entities.RevokedTokenEntity{}).
+		Where("JTI = ? AND ExpiresAt > ?", jti, time.Now()).
+		Count(&count)
+
+	return count > 0
+}
+
+func (repo *TokenRevocationRepository) RevokeSessions(sessionRevocationEntity entities.SessionRevocationEntity) {
+	db, _ := repo.CreateConnection()
+
+	db.Save(&sessionRevocationEntity)
+}
+
+func (repo *TokenRevocationRepository) GetSessionRevocation(userID int) entities.SessionRevocationEntity {
+	db, _ := repo.CreateConnection()
+
+	var sessionRevocation entities.SessionRevocationEntity
+	db.Where("UserID = ? AND ExpiresAt > ?", userID, time.Now()).First(&sessionRevocation)
+
+	return sessionRevocation
+}
+
+// Entries are only needed until the revoked tokens would have expired on their own
+func (repo *TokenRevocationRepository) DeleteExpired() {
+	db, _ := repo.CreateConnection()
+
+	db.Where("ExpiresAt <= ?", time.Now()).Delete(&entities.RevokedTokenEntity{})
+	db.Where("ExpiresAt <= ?", time.Now()).Delete(&entities.SessionRevocationEntity{})
+}
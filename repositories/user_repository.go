@@ -4,6 +4,8 @@ import (
 	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/services/interfaces"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type UserRepository struct {
@@ -53,10 +55,11 @@ func (repo *UserRepository) Create(userEntity entities.UserEntity) entities.User
 	return userEntity
 }
 
-func (repo *UserRepository) DeleteByID(id int) bool {
+// Only deletes the account when it still has the given version
+func (repo *UserRepository) DeleteByID(id int, version int) bool {
 	db, _ := repo.CreateConnection()
 
-	result := db.Delete(&entities.UserEntity{}, id)
+	result := db.Where("Version = ?", version).Delete(&entities.UserEntity{}, id)
 
 	if result.Error != nil || result.RowsAffected == 0 {
 		return false
@@ -65,12 +68,21 @@ func (repo *UserRepository) DeleteByID(id int) bool {
 	return true
 }
 
-func (repo *UserRepository) Update(userEntity entities.UserEntity) entities.UserEntity {
+// Only writes the given columns, so fields the caller did not change keep their stored value.
+// Nothing is written when the account no longer has the given version, otherwise the version is raised.
+func (repo *UserRepository) UpdateColumns(userID int, version int, columns map[string]interface{}) bool {
 	db, _ := repo.CreateConnection()
 
-	db.Save(&userEntity)
+	versionedColumns := map[string]interface{}{"Version": gorm.Expr("Version + 1")}
+	for column, value := range columns {
+		versionedColumns[column] = value
+	}
 
-	return userEntity
+	result := db.Model(&entities.UserEntity{}).
+		Where("id = ? AND Version = ?", userID, version).
+		Updates(versionedColumns)
+
+	return result.Error == nil && result.RowsAffected == 1
 }
 
 func (repo *UserRepository) SaveLastLoginTime(userID int) {
@@ -80,3 +92,37 @@ func (repo *UserRepository) SaveLastLoginTime(userID int) {
 		Where("id = ?", userID).
 		Update("LastLogin", time.Now())
 }
+
+// Only updates the password column, so concurrent profile updates are not overwritten. The version is
+// bumped so clients holding the old version can't delete or update the account afterwards
+func (repo *UserRepository) UpdatePassword(userID int, passwordHash string) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.UserEntity{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{"Password": passwordHash, "Version": gorm.Expr("Version + 1")})
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// Raises the version as well, so cached profiles with the old verification state are stale
+func (repo *UserRepository) MarkEmailVerified(userID int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.UserEntity{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{"EmailVerified": true, "Version": gorm.Expr("Version + 1")})
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// Only updates the email columns, a confirmed address counts as verified and the version is raised
+func (repo *UserRepository) UpdateEmail(userID int, email string) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.UserEntity{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{"Email": email, "EmailVerified": true, "Version": gorm.Expr("Version + 1")})
+
+	return result.Error == nil && result.RowsAffected == 1
+}
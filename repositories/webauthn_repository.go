@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+)
+
+type WebAuthnRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.WebAuthnRepository = (*WebAuthnRepository)(nil)
+
+func NewWebAuthnRepository(baseRepo *BaseRepository) *WebAuthnRepository {
+	return &WebAuthnRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *WebAuthnRepository) CreateCredential(credentialEntity entities.WebAuthnCredentialEntity) entities.WebAuthnCredentialEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&credentialEntity)
+
+	return credentialEntity
+}
+
+func (repo *WebAuthnRepository) GetCredentialByCredentialID(credentialID string) entities.WebAuthnCredentialEntity {
+	db, _ := repo.CreateConnection()
+
+	var credential entities.WebAuthnCredentialEntity
+	db.Where("CredentialID = ?", credentialID).First(&credential)
+
+	return credential
+}
+
+func (repo *WebAuthnRepository) GetCredentialsByUserID(userID int) []entities.WebAuthnCredentialEntity {
+	db, _ := repo.CreateConnection()
+
+	var credentials []entities.WebAuthnCredentialEntity
+	db.Where("UserID = ?", userID).Order("CreatedAt").Find(&credentials)
+
+	return credentials
+}
+
+// Only updates the counter when it was not changed by a concurrent login with the same passkey
+func (repo *WebAuthnRepository) UpdateSignCount(id int, previousSignCount int64, signCount int64) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.WebAuthnCredentialEntity{}).
+		Where("ID = ? AND SignCount = ?", id, previousSignCount).
+		Updates(map[string]interface{}{"SignCount": signCount, "LastUsedAt": time.Now()})
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+func (repo *WebAuthnRepository) DeleteCredential(userID int, credentialID string) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Where("UserID = ? AND CredentialID = ?", userID, credentialID).Delete(&entities.WebAuthnCredentialEntity{})
+
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+func (repo *WebAuthnRepository) CreateChallenge(challengeEntity entities.WebAuthnChallengeEntity) entities.WebAuthnChallengeEntity {
+	db, _ := repo.CreateConnection()
+
+	db.Create(&challengeEntity)
+
+	return challengeEntity
+}
+
+func (repo *WebAuthnRepository) GetChallengeByHash(challengeHash string) entities.WebAuthnChallengeEntity {
+	db, _ := repo.CreateConnection()
+
+	var challenge entities.WebAuthnChallengeEntity
+	db.Where("ChallengeHash = ?", challengeHash).First(&challenge)
+
+	return challenge
+}
+
+// Only marks the challenge when it has not been used yet, so a ceremony cannot be replayed
+func (repo *WebAuthnRepository) MarkChallengeAsUsed(id int) bool {
+	db, _ := repo.CreateConnection()
+
+	result := db.Model(&entities.WebAuthnChallengeEntity{}).
+		Where("ID = ? AND UsedAt IS NULL", id).
+		Update("UsedAt", time.Now())
+
+	return result.Error == nil && result.RowsAffected == 1
+}
@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterEmailChangeRoutes(router *gin.Engine, emailChangeService interfaces.EmailChangeService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	// Public routes
+	// Opened from the link sent to the new address
+	router.GET("/users/email/confirm", func(ctx *gin.Context) {
+		token := ctx.Query("token")
+		if token == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+			return
+		}
+
+		if err := emailChangeService.Confirm(token); err != nil {
+			handleEmailChangeError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Email address has been changed"})
+	})
+
+	// Opened from the link sent to the old address
+	router.GET("/users/email/revert", func(ctx *gin.Context) {
+		token := ctx.Query("token")
+		if token == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+			return
+		}
+
+		if err := emailChangeService.Revert(token); err != nil {
+			handleEmailChangeError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Email change has been reverted"})
+	})
+
+	userGroup := router.Group("/users")
+	userGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Only accessible by users with the matching ID
+	userGroup.POST("/:userID/email", func(ctx *gin.Context) {
+		userID, err := strconv.Atoi(ctx.Param("userID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID"})
+			return
+		}
+
+		if ctx.GetInt("user_id") != userID {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: cannot change the email of another user"})
+			return
+		}
+
+		var changeRequest request.ChangeEmailRequest
+		if err := ctx.ShouldBindJSON(&changeRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := emailChangeService.RequestChange(userID, changeRequest); err != nil {
+			handleEmailChangeError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, gin.H{"message": "A confirmation link has been sent to the new email address"})
+	})
+}
+
+func handleEmailChangeError(ctx *gin.Context, err error) {
+	switch err.(type) {
+	case *errors.InvalidEmailChangeTokenError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case *errors.EmailInUseError:
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case *errors.UserNotFoundError:
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterEmailVerificationRoutes(router *gin.Engine, emailVerificationService interfaces.EmailVerificationService) {
+	// Public routes
+	// Opened from the link in the verification email
+	router.GET("/users/verify", func(ctx *gin.Context) {
+		token := ctx.Query("token")
+		if token == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+			return
+		}
+
+		if err := emailVerificationService.Verify(token); err != nil {
+			if _, ok := err.(*errors.InvalidEmailVerificationTokenError); ok {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Email address has been verified"})
+	})
+
+	// The response is the same for unknown, verified and throttled email addresses
+	router.POST("/users/verify/resend", func(ctx *gin.Context) {
+		var resendRequest request.ResendVerificationRequest
+		if err := ctx.ShouldBindJSON(&resendRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		emailVerificationService.Resend(resendRequest)
+		ctx.JSON(http.StatusAccepted, gin.H{"message": "If this email address belongs to an unverified account, a new verification link has been sent"})
+	})
+}
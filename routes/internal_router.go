@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterInternalRoutes(router *gin.Engine, userService interfaces.UserService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	internalGroup := router.Group("/internal")
+	internalGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Only accessible by services with the users:read scope
+	internalGroup.GET("/users/:userID", requireScope("users:read"), func(ctx *gin.Context) {
+		userID, err := strconv.Atoi(ctx.Param("userID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID"})
+			return
+		}
+
+		user, err := userService.GetByID(userID)
+		if err != nil {
+			if _, ok := err.(*errors.UserNotFoundError); ok {
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, user)
+	})
+}
+
+// Only lets through client credentials tokens that were granted the given scope
+func requireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role, exists := ctx.Get("role")
+
+		if !exists || role != "service" || !slices.Contains(strings.Fields(ctx.GetString("scope")), scope) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "unauthorized: service access with scope " + scope + " required"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
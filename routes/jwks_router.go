@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterJWKSRoutes(router *gin.Engine, keyProvider interfaces.TokenKeyProvider) {
+	// Public route, other services fetch the public keys to verify tokens themselves
+	router.GET("/.well-known/jwks.json", func(ctx *gin.Context) {
+		ctx.Header("Cache-Control", "public, max-age=300")
+		ctx.JSON(http.StatusOK, keyProvider.JWKS())
+	})
+}
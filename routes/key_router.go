@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterKeyRoutes(router *gin.Engine, keyRotator interfaces.KeyRotator, authMiddleware interfaces.GatewayAuthMiddleware) {
+	keyGroup := router.Group("/admin/keys")
+	keyGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Only accessible by admins
+	keyGroup.GET("/", func(ctx *gin.Context) {
+		role, exists := ctx.Get("role")
+
+		if !exists || role != "admin" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: admin access required"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, keyRotator.Keys())
+	})
+
+	// Only accessible by admins
+	keyGroup.POST("/rotate", func(ctx *gin.Context) {
+		role, exists := ctx.Get("role")
+
+		if !exists || role != "admin" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: admin access required"})
+			return
+		}
+
+		signingKey, err := keyRotator.Rotate()
+		if err != nil {
+			if _, ok := err.(*errors.KeyRotationNotSupportedError); ok {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, signingKey)
+	})
+}
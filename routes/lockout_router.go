@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLockoutRoutes(router *gin.Engine, lockoutService interfaces.AccountLockoutService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	userGroup := router.Group("/users")
+	userGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Only accessible by admins
+	userGroup.POST("/:userID/unlock", func(ctx *gin.Context) {
+		role, exists := ctx.Get("role")
+
+		if !exists || role != "admin" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: admin access required"})
+			return
+		}
+
+		userID, err := strconv.Atoi(ctx.Param("userID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID"})
+			return
+		}
+
+		if err := lockoutService.Unlock(userID); err != nil {
+			if _, ok := err.(*errors.UserNotFoundError); ok {
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "account unlocked successfully"})
+	})
+}
@@ -2,9 +2,11 @@ package routes
 
 import (
 	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
 	"flyhorizons-userservice/services/interfaces"
 	"flyhorizons-userservice/utils"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,10 +27,90 @@ func RegisterAuthRoutes(router *gin.Engine, loginService interfaces.LoginService
 		// Call the login service with email, password, and IP
 		loginResponse, err := loginService.Login(loginRequest, ipAddress)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			handleLoginError(c, err)
 			return
 		}
 
 		c.JSON(http.StatusCreated, loginResponse)
 	})
+
+	router.POST("/login/mfa", func(c *gin.Context) {
+		var mfaRequest request.MFALoginRequest
+
+		// Bind the JSON request body to the mfaRequest struct
+		if err := c.ShouldBindJSON(&mfaRequest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Get the client IP address
+		ipAddress := utils.GetIPAddress(c.Request)
+
+		// Exchange the MFA token and the second factor for the access and refresh tokens
+		loginResponse, err := loginService.VerifyMFA(mfaRequest, ipAddress)
+		if err != nil {
+			handleLoginError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, loginResponse)
+	})
+
+	router.POST("/login/passkey", func(c *gin.Context) {
+		var passkeyRequest request.PasskeyLoginRequest
+
+		// Bind the JSON request body to the passkeyRequest struct
+		if err := c.ShouldBindJSON(&passkeyRequest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Get the client IP address
+		ipAddress := utils.GetIPAddress(c.Request)
+
+		// Verify the passkey assertion and issue the access and refresh tokens
+		loginResponse, err := loginService.LoginWithPasskey(passkeyRequest, ipAddress)
+		if err != nil {
+			handleLoginError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, loginResponse)
+	})
+
+	router.POST("/token/refresh", func(c *gin.Context) {
+		var refreshRequest request.RefreshTokenRequest
+
+		// Bind the JSON request body to the refreshRequest struct
+		if err := c.ShouldBindJSON(&refreshRequest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Get the client IP address
+		ipAddress := utils.GetIPAddress(c.Request)
+
+		// Rotate the refresh token and issue a new access token
+		loginResponse, err := loginService.Refresh(refreshRequest, ipAddress)
+		if err != nil {
+			handleLoginError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, loginResponse)
+	})
+}
+
+// Locked accounts get 423 with a Retry-After header, unverified accounts are known and
+// authenticated so they get 403 instead of 401
+func handleLoginError(ctx *gin.Context, err error) {
+	switch typedErr := err.(type) {
+	case *errors.AccountLockedError:
+		ctx.Header("Retry-After", strconv.Itoa(typedErr.RetryAfterSeconds()))
+		ctx.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case *errors.EmailNotVerifiedError:
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	}
 }
@@ -0,0 +1,136 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterMFARoutes(router *gin.Engine, mfaService interfaces.MFAService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	// Accounts that are forced into MFA enrol with the MFA token returned by /login
+	router.POST("/login/mfa/enroll", func(ctx *gin.Context) {
+		var tokenRequest request.MFATokenRequest
+		if err := ctx.ShouldBindJSON(&tokenRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		enrollment, err := mfaService.EnrollWithChallenge(tokenRequest.MFAToken)
+		if err != nil {
+			handleMFAError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, enrollment)
+	})
+
+	mfaGroup := router.Group("/mfa")
+	mfaGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Only accessible by signed in users, for their own account
+	mfaGroup.GET("/", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, mfaService.Status(userID.(int)))
+	})
+
+	mfaGroup.POST("/enroll", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		enrollment, err := mfaService.Enroll(userID.(int))
+		if err != nil {
+			handleMFAError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, enrollment)
+	})
+
+	mfaGroup.POST("/activate", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		var codeRequest request.MFACodeRequest
+		if err := ctx.ShouldBindJSON(&codeRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := mfaService.Activate(userID.(int), codeRequest.Code); err != nil {
+			handleMFAError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Multi-factor authentication enabled"})
+	})
+
+	mfaGroup.POST("/disable", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		var codeRequest request.MFACodeRequest
+		if err := ctx.ShouldBindJSON(&codeRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := mfaService.Disable(userID.(int), codeRequest.Code); err != nil {
+			handleMFAError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Multi-factor authentication disabled"})
+	})
+
+	mfaGroup.POST("/recovery-codes", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		var codeRequest request.MFACodeRequest
+		if err := ctx.ShouldBindJSON(&codeRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		recoveryCodes, err := mfaService.RegenerateRecoveryCodes(userID.(int), codeRequest.Code)
+		if err != nil {
+			handleMFAError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, recoveryCodes)
+	})
+}
+
+func handleMFAError(ctx *gin.Context, err error) {
+	switch err.(type) {
+	case *errors.InvalidMFATokenError:
+		ctx.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	case *errors.InvalidMFACodeError, *errors.MFANotEnabledError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case *errors.MFAAlreadyEnabledError:
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case *errors.MFARequiredByPolicyError:
+		ctx.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case *errors.UserNotFoundError:
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
@@ -0,0 +1,117 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOAuthRoutes(router *gin.Engine, introspectionService interfaces.IntrospectionService, clientService interfaces.OAuthClientService, authorizationService interfaces.AuthorizationService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	// Only accessible by registered clients
+	router.POST("/oauth/token", func(ctx *gin.Context) {
+		var tokenResponse *response.OAuthTokenResponse
+		var err error
+
+		switch ctx.PostForm("grant_type") {
+		case "client_credentials":
+			clientID, clientSecret := clientCredentials(ctx)
+			tokenResponse, err = clientService.IssueClientCredentialsToken(clientID, clientSecret, ctx.PostForm("scope"))
+		case "authorization_code":
+			var codeRequest request.AuthorizationCodeRequest
+			if err := ctx.ShouldBind(&codeRequest); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+				return
+			}
+			codeRequest.ClientID, codeRequest.ClientSecret = clientCredentials(ctx)
+			tokenResponse, err = authorizationService.ExchangeAuthorizationCode(codeRequest)
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+			return
+		}
+
+		if err != nil {
+			if _, ok := err.(*errors.InvalidClientError); ok {
+				ctx.Header("WWW-Authenticate", `Basic realm="flyhorizons-user-service"`)
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": err.Error()})
+				return
+			}
+			if _, ok := err.(*errors.InvalidScopeError); ok {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope", "error_description": err.Error()})
+				return
+			}
+			if _, ok := err.(*errors.InvalidGrantError); ok {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		// Token responses must never be cached
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(http.StatusOK, tokenResponse)
+	})
+
+	// Only accessible by registered clients with the introspect scope
+	router.POST("/oauth/introspect", func(ctx *gin.Context) {
+		clientID, clientSecret := clientCredentials(ctx)
+		if err := clientService.AuthorizeIntrospection(clientID, clientSecret); err != nil {
+			if _, ok := err.(*errors.InsufficientScopeError); ok {
+				ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient_scope", "error_description": err.Error()})
+				return
+			}
+			ctx.Header("WWW-Authenticate", `Basic realm="flyhorizons-user-service"`)
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+			return
+		}
+
+		token := ctx.PostForm("token")
+		if token == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, introspectionService.Introspect(token))
+	})
+
+	clientGroup := router.Group("/admin/oauth/clients")
+	clientGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Only accessible by admins
+	clientGroup.POST("/", func(ctx *gin.Context) {
+		role, exists := ctx.Get("role")
+
+		if !exists || role != "admin" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: admin access required"})
+			return
+		}
+
+		var registerRequest request.RegisterClientRequest
+		if err := ctx.ShouldBindJSON(&registerRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		client, err := clientService.Register(registerRequest)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, client)
+	})
+}
+
+// Clients authenticate with HTTP Basic authentication or with client_id and client_secret form parameters,
+// public clients only send their client_id
+func clientCredentials(ctx *gin.Context) (string, string) {
+	clientID, clientSecret, ok := ctx.Request.BasicAuth()
+	if !ok {
+		clientID = ctx.PostForm("client_id")
+		clientSecret = ctx.PostForm("client_secret")
+	}
+	return clientID, clientSecret
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOIDCRoutes(router *gin.Engine, authorizationService interfaces.AuthorizationService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	router.GET("/.well-known/openid-configuration", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, authorizationService.Discovery())
+	})
+
+	// Clients send the user agent here, it is redirected to the FlyHorizons consent page where the user signs in
+	// and approves the client
+	router.GET("/oauth/authorize", func(ctx *gin.Context) {
+		var authorizeRequest request.AuthorizeRequest
+		if err := ctx.ShouldBindQuery(&authorizeRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+			return
+		}
+
+		redirectURL, err := authorizationService.Authorize(authorizeRequest)
+		if err != nil {
+			authorizationError(ctx, err)
+			return
+		}
+
+		ctx.Redirect(http.StatusFound, redirectURL)
+	})
+
+	// Protected routes
+	// Only accessible by users signed in to FlyHorizons, tokens issued to other clients cannot authorize new clients.
+	// The consent page posts the decision of the user and sends the user agent to the returned redirect.
+	router.POST("/oauth/authorize/consent", authMiddleware.GatewayAuthMiddleware(), func(ctx *gin.Context) {
+		role, exists := ctx.Get("role")
+		_, delegated := ctx.Get("scope")
+
+		if !exists || (role != "user" && role != "admin") || delegated {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user sign-in required"})
+			return
+		}
+
+		var consentRequest request.ConsentRequest
+		if err := ctx.ShouldBind(&consentRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+			return
+		}
+
+		redirectURL, err := authorizationService.GrantConsent(consentRequest, ctx.GetInt("user_id"))
+		if err != nil {
+			authorizationError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"redirect_to": redirectURL})
+	})
+
+	// Accessible by every signed in user and by clients holding a token issued through the authorization code flow
+	router.GET("/userinfo", authMiddleware.DelegatedAuthMiddleware(), func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		userInfo, err := authorizationService.UserInfo(userID.(int), ctx.GetString("scope"))
+		if err != nil {
+			if _, ok := err.(*errors.UserNotFoundError); ok {
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, userInfo)
+	})
+}
+
+// Without a valid client and redirect URI the error cannot be sent back to the client
+func authorizationError(ctx *gin.Context, err error) {
+	if _, ok := err.(*errors.InvalidClientError); ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_client", "error_description": err.Error()})
+		return
+	}
+	if _, ok := err.(*errors.InvalidRedirectURIError); ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+}
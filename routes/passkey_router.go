@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPasskeyRoutes(router *gin.Engine, webAuthnService interfaces.WebAuthnService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	// First step of the passkey login, the assertion is sent to /login/passkey
+	router.POST("/login/passkey/options", func(ctx *gin.Context) {
+		options, err := webAuthnService.BeginLogin()
+		if err != nil {
+			handlePasskeyError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, options)
+	})
+
+	passkeyGroup := router.Group("/passkeys")
+	passkeyGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Only accessible by signed in users, for their own account
+	passkeyGroup.GET("/", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, webAuthnService.Credentials(userID.(int)))
+	})
+
+	passkeyGroup.POST("/options", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		options, err := webAuthnService.BeginRegistration(userID.(int))
+		if err != nil {
+			handlePasskeyError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, options)
+	})
+
+	passkeyGroup.POST("/", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		var registrationRequest request.PasskeyRegistrationRequest
+		if err := ctx.ShouldBindJSON(&registrationRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		passkey, err := webAuthnService.FinishRegistration(userID.(int), registrationRequest)
+		if err != nil {
+			handlePasskeyError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, passkey)
+	})
+
+	passkeyGroup.DELETE("/:credentialID", func(ctx *gin.Context) {
+		userID, exists := ctx.Get("user_id")
+		if !exists {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: user token required"})
+			return
+		}
+
+		if err := webAuthnService.DeleteCredential(userID.(int), ctx.Param("credentialID")); err != nil {
+			handlePasskeyError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Passkey removed"})
+	})
+}
+
+func handlePasskeyError(ctx *gin.Context, err error) {
+	switch err.(type) {
+	case *errors.InvalidPasskeyError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case *errors.PasskeyNotFoundError, *errors.UserNotFoundError:
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPasswordChangeRoutes(router *gin.Engine, passwordChangeService interfaces.PasswordChangeService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	userGroup := router.Group("/users")
+	userGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected route
+	// Only accessible by users with the matching ID
+	userGroup.POST("/:userID/password", func(ctx *gin.Context) {
+		userID, err := strconv.Atoi(ctx.Param("userID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID"})
+			return
+		}
+
+		if ctx.GetInt("user_id") != userID {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: cannot change the password of another user"})
+			return
+		}
+
+		var changeRequest request.ChangePasswordRequest
+		if err := ctx.ShouldBindJSON(&changeRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := passwordChangeService.ChangePassword(userID, changeRequest); err != nil {
+			switch typedErr := err.(type) {
+			case *errors.InvalidCredentialsError:
+				ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case *errors.AccountLockedError:
+				ctx.Header("Retry-After", strconv.Itoa(typedErr.RetryAfterSeconds()))
+				ctx.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+			case *errors.UserNotFoundError:
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			case *errors.InsufficientPasswordLengthError, *errors.InsufficientPasswordComplexityError, *errors.CommonPasswordError, *errors.PasswordReusedError:
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Password has been changed, other sessions have been signed out"})
+	})
+}
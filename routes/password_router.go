@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPasswordRoutes(router *gin.Engine, passwordResetService interfaces.PasswordResetService, passwordCheckService interfaces.PasswordCheckService) {
+	// Public routes
+	// The response is the same whether or not the email belongs to an account
+	router.POST("/password/forgot", func(ctx *gin.Context) {
+		var forgotRequest request.ForgotPasswordRequest
+		if err := ctx.ShouldBindJSON(&forgotRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		passwordResetService.Forgot(forgotRequest)
+		ctx.JSON(http.StatusAccepted, gin.H{"message": "If an account exists for this email address, a password reset link has been sent"})
+	})
+
+	router.POST("/password/reset", func(ctx *gin.Context) {
+		var resetRequest request.ResetPasswordRequest
+		if err := ctx.ShouldBindJSON(&resetRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := passwordResetService.Reset(resetRequest); err != nil {
+			switch err.(type) {
+			case *errors.InvalidPasswordResetTokenError, *errors.InsufficientPasswordLengthError, *errors.InsufficientPasswordComplexityError, *errors.CommonPasswordError, *errors.PasswordReusedError:
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Password has been reset"})
+	})
+
+	// Lets the registration form rate a password before it is submitted
+	router.POST("/password/check", func(ctx *gin.Context) {
+		var checkRequest request.PasswordCheckRequest
+		if err := ctx.ShouldBindJSON(&checkRequest); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, passwordCheckService.Check(checkRequest))
+	})
+}
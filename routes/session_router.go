@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/interfaces"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSessionRoutes(router *gin.Engine, revocationService interfaces.TokenRevocationService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	// Protected route
+	// Revokes the token used to call this endpoint
+	router.POST("/logout", authMiddleware.GatewayAuthMiddleware(), func(ctx *gin.Context) {
+		var logoutRequest request.LogoutRequest
+
+		// The request body is optional
+		if ctx.Request.ContentLength > 0 {
+			if err := ctx.ShouldBindJSON(&logoutRequest); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		expiresAt, _ := ctx.Get("exp")
+		expiration, ok := expiresAt.(time.Time)
+		if !ok {
+			expiration = time.Now()
+		}
+
+		revocationService.Logout(logoutRequest, ctx.GetString("jti"), ctx.GetInt("sub"), expiration)
+		ctx.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
+	})
+
+	adminGroup := router.Group("/admin/users")
+	adminGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Only accessible by admins
+	adminGroup.POST("/:userID/sessions/revoke", func(ctx *gin.Context) {
+		role, exists := ctx.Get("role")
+
+		if !exists || role != "admin" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: admin access required"})
+			return
+		}
+
+		userID, err := strconv.Atoi(ctx.Param("userID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID"})
+			return
+		}
+
+		revocationService.RevokeAllSessions(userID)
+		ctx.JSON(http.StatusOK, gin.H{"message": "all sessions revoked successfully"})
+	})
+}
@@ -1,9 +1,12 @@
 package routes
 
 import (
-	"flyhorizons-userservice/models"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
 	"flyhorizons-userservice/services/errors"
 	"flyhorizons-userservice/services/interfaces"
+	"flyhorizons-userservice/utils"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -13,15 +16,15 @@ import (
 func RegisterUserRoutes(router *gin.Engine, userService interfaces.UserService, authMiddleware interfaces.GatewayAuthMiddleware) {
 	// Public route
 	router.POST("/users", func(ctx *gin.Context) {
-		var user models.User
-		if err := ctx.ShouldBindJSON(&user); err != nil {
+		var createRequest request.CreateUserRequest
+		if err := ctx.ShouldBindJSON(&createRequest); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		postUser, err := userService.Create(user)
+		postUser, err := userService.Create(createRequest)
 		if err != nil {
-			if _, ok := err.(*errors.UserExistsError); ok {
+			if _, ok := err.(*errors.EmailInUseError); ok {
 				ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
 				return
 			}
@@ -60,7 +63,7 @@ func RegisterUserRoutes(router *gin.Engine, userService interfaces.UserService,
 		ctx.JSON(http.StatusOK, users)
 	})
 
-	// Only accessible by users with the matching ID
+	// Only accessible by admins and users with the matching ID, admins need the ETag to patch other accounts
 	userGroup.GET("/:userID", func(ctx *gin.Context) {
 		userIDString := ctx.Param("userID")
 		userID, err := strconv.Atoi(userIDString)
@@ -71,7 +74,7 @@ func RegisterUserRoutes(router *gin.Engine, userService interfaces.UserService,
 
 		tokenUserID := ctx.GetInt("user_id")
 
-		if tokenUserID != userID {
+		if ctx.GetString("role") != "admin" && tokenUserID != userID {
 			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: cannot access the account belonging to another user"})
 			return
 		}
@@ -85,6 +88,7 @@ func RegisterUserRoutes(router *gin.Engine, userService interfaces.UserService,
 			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
+		ctx.Header("ETag", utils.FormatETag(user.Version))
 		ctx.JSON(http.StatusOK, user)
 	})
 
@@ -104,12 +108,21 @@ func RegisterUserRoutes(router *gin.Engine, userService interfaces.UserService,
 			return
 		}
 
-		success, err := userService.DeleteByID(userID)
+		version, ok := ifMatchVersion(ctx)
+		if !ok {
+			return
+		}
+
+		success, err := userService.DeleteByID(userID, version)
 		if err != nil {
 			if _, ok := err.(*errors.UserNotFoundError); ok {
 				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 				return
 			}
+			if _, ok := err.(*errors.ConcurrentModificationError); ok {
+				ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
@@ -123,40 +136,117 @@ func RegisterUserRoutes(router *gin.Engine, userService interfaces.UserService,
 
 	// Only accessible by users with the matching ID
 	userGroup.PUT("/", func(ctx *gin.Context) {
-		var user models.User
-		if err := ctx.ShouldBindJSON(&user); err != nil {
+		var updateRequest request.UpdateUserRequest
+		if err := ctx.ShouldBindJSON(&updateRequest); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
 		tokenUserID := ctx.GetInt("sub")
 
-		if user.ID != tokenUserID {
+		if updateRequest.ID != tokenUserID {
 			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: cannot update the account belonging to another user"})
 			return
 		}
 
-		putUser, err := userService.Update(user)
+		version, ok := ifMatchVersion(ctx)
+		if !ok {
+			return
+		}
+
+		putUser, err := userService.Update(updateRequest, version)
 		if err != nil {
 			if _, ok := err.(*errors.UserNotFoundError); ok {
 				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 				return
 			}
-			if _, ok := err.(*errors.InsufficientPasswordLengthError); ok { // Other 2 types of exceptions to throw this type of custom exception
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-			if _, ok := err.(*errors.InsufficientPasswordComplexityError); ok {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-			if _, ok := err.(*errors.CommonPasswordError); ok {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			if _, ok := err.(*errors.ConcurrentModificationError); ok {
+				ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
 				return
 			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
+		ctx.Header("ETag", utils.FormatETag(putUser.Version))
 		ctx.JSON(http.StatusOK, putUser)
 	})
+
+	// Accessible by users with the matching ID and by admins, the fields that can be changed depend on the role
+	userGroup.PATCH("/:userID", func(ctx *gin.Context) {
+		userID, err := strconv.Atoi(ctx.Param("userID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID"})
+			return
+		}
+
+		role := ctx.GetString("role")
+
+		if role != "admin" && ctx.GetInt("user_id") != userID {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: cannot update the account belonging to another user"})
+			return
+		}
+
+		version, ok := ifMatchVersion(ctx)
+		if !ok {
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var changes map[string]json.RawMessage
+		switch ctx.ContentType() {
+		case utils.MergePatchContentType, "application/json":
+			changes, err = utils.ParseMergePatch(body)
+		case utils.JSONPatchContentType:
+			changes, err = utils.ParseJSONPatch(body)
+		default:
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "use " + utils.MergePatchContentType + " or " + utils.JSONPatchContentType})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		patchedUser, err := userService.Patch(userID, changes, role, version)
+		if err != nil {
+			switch err.(type) {
+			case *errors.UserNotFoundError:
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			case *errors.ConcurrentModificationError:
+				ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			case *errors.FieldNotPatchableError:
+				ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case *errors.InvalidPatchError:
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			}
+			return
+		}
+		ctx.Header("ETag", utils.FormatETag(patchedUser.Version))
+		ctx.JSON(http.StatusOK, patchedUser)
+	})
+}
+
+// Reads the version the caller last read from the If-Match header, updates and deletes without one
+// are rejected so they cannot overwrite changes the caller has not seen
+func ifMatchVersion(ctx *gin.Context) (int, bool) {
+	header := ctx.GetHeader("If-Match")
+	if header == "" {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "the If-Match header with the ETag of the user is required"})
+		return 0, false
+	}
+
+	version, err := utils.ParseIfMatch(header)
+	if err != nil {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	return version, true
 }
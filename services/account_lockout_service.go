@@ -0,0 +1,130 @@
+package services
+
+import (
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+type LockoutPolicy struct {
+	DelayThreshold int           // Failed attempts before the back-off starts
+	BaseDelay      time.Duration // Delay after DelayThreshold failures, doubled after every further failure
+	MaxDelay       time.Duration
+	LockThreshold  int // Failed attempts before the account is locked
+	LockDuration   time.Duration
+}
+
+// Every setting can be overridden through the environment, invalid values keep the default
+func LoadLockoutPolicyFromEnv() LockoutPolicy {
+	policy := LockoutPolicy{
+		DelayThreshold: 3,
+		BaseDelay:      time.Second,
+		MaxDelay:       time.Minute,
+		LockThreshold:  10,
+		LockDuration:   15 * time.Minute,
+	}
+
+	if threshold, err := strconv.Atoi(os.Getenv("LOCKOUT_DELAY_THRESHOLD")); err == nil && threshold > 0 {
+		policy.DelayThreshold = threshold
+	}
+	if delay, err := time.ParseDuration(os.Getenv("LOCKOUT_BASE_DELAY")); err == nil && delay > 0 {
+		policy.BaseDelay = delay
+	}
+	if delay, err := time.ParseDuration(os.Getenv("LOCKOUT_MAX_DELAY")); err == nil && delay > 0 {
+		policy.MaxDelay = delay
+	}
+	if threshold, err := strconv.Atoi(os.Getenv("LOCKOUT_THRESHOLD")); err == nil && threshold > 0 {
+		policy.LockThreshold = threshold
+	}
+	if duration, err := time.ParseDuration(os.Getenv("LOCKOUT_DURATION")); err == nil && duration > 0 {
+		policy.LockDuration = duration
+	}
+
+	return policy
+}
+
+// How long the next attempt has to wait after the given number of failed attempts
+func (policy LockoutPolicy) Delay(failedAttempts int) time.Duration {
+	if failedAttempts < policy.DelayThreshold {
+		return 0
+	}
+
+	delay := policy.BaseDelay
+	for i := policy.DelayThreshold; i < failedAttempts && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, policy.MaxDelay)
+}
+
+type AccountLockoutService struct {
+	lockoutRepo interfaces.AccountLockoutRepository
+	userRepo    interfaces.UserRepository
+	policy      LockoutPolicy
+}
+
+var _ interfaces.AccountLockoutService = (*AccountLockoutService)(nil)
+
+func NewAccountLockoutService(lockoutRepo interfaces.AccountLockoutRepository, userRepo interfaces.UserRepository, policy LockoutPolicy) *AccountLockoutService {
+	return &AccountLockoutService{
+		lockoutRepo: lockoutRepo,
+		userRepo:    userRepo,
+		policy:      policy,
+	}
+}
+
+// Refuses a login attempt while the account is locked or the back-off of the last failure has not passed
+func (service *AccountLockoutService) Check(userID int) error {
+	lockout := service.lockoutRepo.GetByUserID(userID)
+	now := time.Now()
+
+	if lockout.LockedUntil != nil && now.Before(*lockout.LockedUntil) {
+		return errors.NewAccountLockedError(lockout.LockedUntil.Sub(now), 423)
+	}
+
+	if lockout.LastFailedAt != nil {
+		retryAt := lockout.LastFailedAt.Add(service.policy.Delay(lockout.FailedAttempts))
+		if now.Before(retryAt) {
+			return errors.NewAccountLockedError(retryAt.Sub(now), 423)
+		}
+	}
+
+	return nil
+}
+
+func (service *AccountLockoutService) RecordFailure(userID int) {
+	lockout := service.lockoutRepo.IncrementFailedAttempts(userID)
+	if lockout.FailedAttempts < service.policy.LockThreshold {
+		return
+	}
+
+	service.lockoutRepo.Lock(userID, time.Now().Add(service.policy.LockDuration))
+	log.Printf(
+		"Account locked after too many failed login attempts:\n  User ID: %v\n  Locked For: %s\n  Timestamp: %s",
+		userID,
+		service.policy.LockDuration,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+func (service *AccountLockoutService) RecordSuccess(userID int) {
+	service.lockoutRepo.Reset(userID)
+}
+
+func (service *AccountLockoutService) Unlock(userID int) error {
+	if service.userRepo.GetByID(userID).ID == 0 {
+		return errors.NewUserNotFoundError(userID, 404)
+	}
+
+	service.lockoutRepo.Reset(userID)
+
+	log.Printf(
+		"Account unlocked by an admin:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
@@ -2,22 +2,156 @@ package authentication
 
 import (
 	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"os"
+	"strconv"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-type AccountHashing struct{}
+const (
+	HashingAlgorithmArgon2id = "argon2id"
+	HashingAlgorithmBcrypt   = "bcrypt"
+)
+
+type HashingPolicy struct {
+	Algorithm  string // Used for new hashes, hashes of the other algorithm are still verified
+	BcryptCost int
+	Argon2id   Argon2idParams
+	// Optional, hashes created with another or without pepper are migrated on login
+	Pepper         *PasswordPepper
+	RetiredPeppers []*PasswordPepper
+}
+
+// Argon2id with the minimum parameters recommended by OWASP
+func DefaultHashingPolicy() HashingPolicy {
+	return HashingPolicy{
+		Algorithm:  HashingAlgorithmArgon2id,
+		BcryptCost: bcrypt.DefaultCost,
+		Argon2id: Argon2idParams{
+			Memory:      19 * 1024,
+			Iterations:  2,
+			Parallelism: 1,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+	}
+}
+
+// Every setting can be overridden through the environment, invalid values keep the default except
+// for the peppers, which must be valid
+func LoadHashingPolicyFromEnv() (HashingPolicy, error) {
+	policy := DefaultHashingPolicy()
+
+	if algorithm := strings.ToLower(os.Getenv("PASSWORD_HASH_ALGORITHM")); algorithm == HashingAlgorithmBcrypt {
+		policy.Algorithm = algorithm
+	}
+	if cost, err := strconv.Atoi(os.Getenv("BCRYPT_COST")); err == nil && cost >= bcrypt.MinCost && cost <= bcrypt.MaxCost {
+		policy.BcryptCost = cost
+	}
+	if memory, err := strconv.ParseUint(os.Getenv("ARGON2ID_MEMORY"), 10, 32); err == nil && memory > 0 {
+		policy.Argon2id.Memory = uint32(memory)
+	}
+	if iterations, err := strconv.ParseUint(os.Getenv("ARGON2ID_ITERATIONS"), 10, 32); err == nil && iterations > 0 {
+		policy.Argon2id.Iterations = uint32(iterations)
+	}
+	if parallelism, err := strconv.ParseUint(os.Getenv("ARGON2ID_PARALLELISM"), 10, 8); err == nil && parallelism > 0 {
+		policy.Argon2id.Parallelism = uint8(parallelism)
+	}
+
+	var err error
+	if policy.Pepper, policy.RetiredPeppers, err = LoadPeppersFromEnv(); err != nil {
+		return HashingPolicy{}, err
+	}
+
+	return policy, nil
+}
+
+type AccountHashing struct {
+	preferred interfaces.PasswordHasher
+	hashers   []interfaces.PasswordHasher
+	pepper    *PasswordPepper
+	peppers   map[string]*PasswordPepper // Current and retired peppers by version
+}
+
+func NewAccountHashing(policy HashingPolicy) *AccountHashing {
+	argon2idHasher := NewArgon2idHasher(policy.Argon2id)
+	bcryptHasher := NewBcryptHasher(policy.BcryptCost)
+
+	var preferred interfaces.PasswordHasher = argon2idHasher
+	if policy.Algorithm == HashingAlgorithmBcrypt {
+		preferred = bcryptHasher
+	}
+
+	peppers := make(map[string]*PasswordPepper)
+	for _, pepper := range policy.RetiredPeppers {
+		peppers[pepper.Version] = pepper
+	}
+	if policy.Pepper != nil {
+		peppers[policy.Pepper.Version] = policy.Pepper
+	}
 
-func NewAccountHashing() *AccountHashing {
-	return &AccountHashing{}
+	return &AccountHashing{
+		preferred: preferred,
+		hashers:   []interfaces.PasswordHasher{argon2idHasher, bcryptHasher},
+		pepper:    policy.Pepper,
+		peppers:   peppers,
+	}
 }
 
 func (service *AccountHashing) HashPassword(password string) (string, error) {
-	// Hash the password using bcrypt
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if service.pepper != nil {
+		password = service.pepper.Apply(password)
+	}
+
+	// Hash the password using the configured algorithm
+	hashedPassword, err := service.preferred.Hash(password)
 	if err != nil {
 		// Return the custom hashing error
 		return "", errors.NewHashingPasswordError(100)
 	}
-	return string(hashedPassword), nil
+
+	// The version tells which pepper to use when the password is compared
+	if service.pepper != nil {
+		return pepperPrefix + service.pepper.Version + hashedPassword, nil
+	}
+	return hashedPassword, nil
+}
+
+func (service *AccountHashing) ComparePassword(encodedPassword string, rawPassword string) bool {
+	version, hash := splitPepperVersion(encodedPassword)
+	if version != "" {
+		pepper, exists := service.peppers[version]
+		if !exists {
+			return false
+		}
+		rawPassword = pepper.Apply(rawPassword)
+	}
+
+	hasher := service.hasherFor(hash)
+	return hasher != nil && hasher.Matches(hash, rawPassword)
+}
+
+// Hashes of another algorithm, with outdated parameters or another pepper are replaced after the next successful login
+func (service *AccountHashing) NeedsRehash(encodedPassword string) bool {
+	currentVersion := ""
+	if service.pepper != nil {
+		currentVersion = service.pepper.Version
+	}
+
+	version, hash := splitPepperVersion(encodedPassword)
+	if version != currentVersion {
+		return true
+	}
+	return !service.preferred.Recognizes(hash) || service.preferred.NeedsRehash(hash)
+}
+
+func (service *AccountHashing) hasherFor(encodedPassword string) interfaces.PasswordHasher {
+	for _, hasher := range service.hashers {
+		if hasher.Recognizes(encodedPassword) {
+			return hasher
+		}
+	}
+	return nil
 }
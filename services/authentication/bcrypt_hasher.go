@@ -0,0 +1,46 @@
+package authentication
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type BcryptHasher struct {
+	cost int
+}
+
+var _ interfaces.PasswordHasher = (*BcryptHasher)(nil)
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{
+		cost: cost,
+	}
+}
+
+func (hasher *BcryptHasher) Hash(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), hasher.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+func (hasher *BcryptHasher) Matches(encodedPassword string, rawPassword string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encodedPassword), []byte(rawPassword)) == nil
+}
+
+func (hasher *BcryptHasher) Recognizes(encodedPassword string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(encodedPassword, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (hasher *BcryptHasher) NeedsRehash(encodedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(encodedPassword))
+	return err != nil || cost != hasher.cost
+}
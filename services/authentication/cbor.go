@@ -0,0 +1,131 @@
+package authentication
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Maximum nesting of CBOR arrays and maps, WebAuthn structures are only a few levels deep
+const cborMaxDepth = 16
+
+// Decodes the subset of CBOR (RFC 8949) used by WebAuthn: integers, byte and text strings, arrays,
+// maps and simple values. Returns the decoded value and the bytes that follow it, since the
+// credential public key in the authenticator data is not length prefixed.
+//
+// Values are decoded to int64, []byte, string, []interface{}, map[interface{}]interface{}, bool or nil.
+func decodeCBOR(data []byte) (interface{}, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > cborMaxDepth {
+		return nil, nil, fmt.Errorf("cbor: maximum nesting depth exceeded")
+	}
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("cbor: unexpected end of data")
+	}
+
+	majorType := data[0] >> 5
+	additional := data[0] & 0x1f
+
+	// Simple values do not carry an argument
+	if majorType == 7 {
+		switch additional {
+		case 20:
+			return false, data[1:], nil
+		case 21:
+			return true, data[1:], nil
+		case 22, 23:
+			return nil, data[1:], nil
+		default:
+			return nil, nil, fmt.Errorf("cbor: unsupported simple value %d", additional)
+		}
+	}
+
+	argument, rest, err := decodeCBORArgument(additional, data[1:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch majorType {
+	case 0:
+		if argument > math.MaxInt64 {
+			return nil, nil, fmt.Errorf("cbor: integer overflow")
+		}
+		return int64(argument), rest, nil
+	case 1:
+		if argument > math.MaxInt64 {
+			return nil, nil, fmt.Errorf("cbor: integer overflow")
+		}
+		return -1 - int64(argument), rest, nil
+	case 2, 3:
+		if argument > uint64(len(rest)) {
+			return nil, nil, fmt.Errorf("cbor: unexpected end of data")
+		}
+		if majorType == 3 {
+			return string(rest[:argument]), rest[argument:], nil
+		}
+		return append([]byte{}, rest[:argument]...), rest[argument:], nil
+	case 4:
+		// Every item takes at least one byte, which bounds the allocation
+		if argument > uint64(len(rest)) {
+			return nil, nil, fmt.Errorf("cbor: unexpected end of data")
+		}
+		items := make([]interface{}, 0, argument)
+		for i := uint64(0); i < argument; i++ {
+			var item interface{}
+			item, rest, err = decodeCBORItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case 5:
+		if argument > uint64(len(rest)) {
+			return nil, nil, fmt.Errorf("cbor: unexpected end of data")
+		}
+		entries := make(map[interface{}]interface{}, argument)
+		for i := uint64(0); i < argument; i++ {
+			var key, value interface{}
+			key, rest, err = decodeCBORItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			value, rest, err = decodeCBORItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			entries[key] = value
+		}
+		return entries, rest, nil
+	default:
+		return nil, nil, fmt.Errorf("cbor: unsupported major type %d", majorType)
+	}
+}
+
+// Indefinite lengths are not used by WebAuthn authenticators and are rejected
+func decodeCBORArgument(additional byte, data []byte) (uint64, []byte, error) {
+	switch {
+	case additional < 24:
+		return uint64(additional), data, nil
+	case additional == 24 && len(data) >= 1:
+		return uint64(data[0]), data[1:], nil
+	case additional == 25 && len(data) >= 2:
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case additional == 26 && len(data) >= 4:
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case additional == 27 && len(data) >= 8:
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	case additional >= 28:
+		return 0, nil, fmt.Errorf("cbor: unsupported additional information %d", additional)
+	default:
+		return 0, nil, fmt.Errorf("cbor: unexpected end of data")
+	}
+}
@@ -1,28 +1,32 @@
 package authentication
 
 import (
+	"flyhorizons-userservice/services/interfaces"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
-	"github.com/joho/godotenv"
 )
 
-type GatewayAuthMiddlewareHandler struct{}
+type GatewayAuthMiddlewareHandler struct {
+	tokenValidator    interfaces.TokenValidator
+	revocationService interfaces.TokenRevocationService
+}
 
+// Accepts first-party tokens, tokens issued to other clients through the authorization code flow are refused
 func (g *GatewayAuthMiddlewareHandler) GatewayAuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Load .env file
-		if err := godotenv.Load(); err != nil {
-			log.Println("No .env file found, relying on environment variables")
-		}
+	return g.authenticate(false)
+}
 
-		JwtSecret := []byte(os.Getenv("JWT_SECRET"))
+// Also accepts tokens issued to other clients, only for routes that check the granted scope themselves
+func (g *GatewayAuthMiddlewareHandler) DelegatedAuthMiddleware() gin.HandlerFunc {
+	return g.authenticate(true)
+}
 
+func (g *GatewayAuthMiddlewareHandler) authenticate(allowDelegated bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		// Get JWT token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 
@@ -33,38 +37,45 @@ func (g *GatewayAuthMiddlewareHandler) GatewayAuthMiddleware() gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Parse the JWT token
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return JwtSecret, nil
-		})
-
-		if err != nil || !token.Valid {
+		// Parse and verify the JWT token
+		claims, err := g.tokenValidator.Validate(tokenStr)
+		if err != nil {
 			fmt.Println("JWT parsing failed:", err)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid JWT token"})
 			return
 		}
 
-		// Extract claims
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid JWT claims"})
+		// Reject tokens revoked through logout or session revocation
+		jti, _ := claims["jti"].(string)
+		sub, _ := claims["sub"].(float64)
+		iat, _ := claims["iat"].(float64)
+		if g.revocationService.IsRevoked(jti, int(sub), time.Unix(int64(iat), 0)) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "JWT token has been revoked"})
 			return
 		}
 
-		// TODO: Delete the logging after implementing the refreshing authentication tokens
-		fmt.Println("--- Extracted JWT Claims ---")
-		for k, v := range claims {
-			fmt.Printf("%s: %v\n", k, v)
+		// Tokens with an authorized party act on behalf of the user for another client
+		if _, delegated := claims["azp"]; delegated && !allowDelegated {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden: delegated tokens are not accepted on this route"})
+			return
 		}
 
 		// Set claims
+		c.Set("jti", jti)
+		if exp, ok := claims["exp"].(float64); ok {
+			c.Set("exp", time.Unix(int64(exp), 0))
+		}
 		if sub, ok := claims["sub"].(float64); ok {
 			c.Set("user_id", int(sub))
 			c.Set("sub", int(sub))
 		}
+		// Machine identities use their client ID as subject
+		if clientID, ok := claims["client_id"].(string); ok {
+			c.Set("client_id", clientID)
+		}
+		if scope, ok := claims["scope"].(string); ok {
+			c.Set("scope", scope)
+		}
 		if role, ok := claims["role"].(string); ok {
 			c.Set("role", role)
 		}
@@ -76,6 +87,9 @@ func (g *GatewayAuthMiddlewareHandler) GatewayAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
-func NewGatewayAuthMiddleware() *GatewayAuthMiddlewareHandler {
-	return &GatewayAuthMiddlewareHandler{}
+func NewGatewayAuthMiddleware(keyProvider interfaces.TokenKeyProvider, revocationService interfaces.TokenRevocationService) *GatewayAuthMiddlewareHandler {
+	return &GatewayAuthMiddlewareHandler{
+		tokenValidator:    NewJwtTokenValidator(keyProvider),
+		revocationService: revocationService,
+	}
 }
@@ -1,25 +1,67 @@
 package authentication
 
 import (
+	"flyhorizons-userservice/models/response"
 	"fmt"
-	"os"
 
 	"github.com/golang-jwt/jwt"
 )
 
-type JwtTokenSigner struct{}
+type JwtTokenSigner struct {
+	keyRing *KeyRing
+}
 
-func NewJwtTokenSigner() *JwtTokenSigner {
-	return &JwtTokenSigner{}
+func NewJwtTokenSigner(keyRing *KeyRing) *JwtTokenSigner {
+	return &JwtTokenSigner{
+		keyRing: keyRing,
+	}
 }
 
 func (s *JwtTokenSigner) SignToken(claims jwt.Claims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secretKey := []byte(os.Getenv("JWT_SECRET"))
-	fmt.Println("Secret Key", secretKey)
-	signedToken, err := token.SignedString(secretKey)
+	signingKey := s.keyRing.Active()
+	signingMethod := jwt.GetSigningMethod(signingKey.Algorithm)
+	if signingMethod == nil {
+		return "", fmt.Errorf("unsupported signing algorithm: %s", signingKey.Algorithm)
+	}
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	// The key ID lets verifiers pick the matching public key from the JWKS
+	token.Header["kid"] = signingKey.ID
+
+	signedToken, err := token.SignedString(signingKey.PrivateKey)
 	if err != nil {
 		return "", err
 	}
 	return signedToken, nil
 }
+
+func (s *JwtTokenSigner) VerificationKey(kid string) (string, interface{}, error) {
+	signingKey, err := s.keyRing.Lookup(kid)
+	if err != nil {
+		return "", nil, err
+	}
+	return signingKey.Algorithm, signingKey.PublicKey, nil
+}
+
+// Publishes the active and retired public keys, so tokens signed before a rotation stay verifiable downstream
+func (s *JwtTokenSigner) JWKS() response.JSONWebKeySet {
+	keySet := response.JSONWebKeySet{Keys: []response.JSONWebKey{}}
+	for _, signingKey := range s.keyRing.verifiableKeys() {
+		if jwk, ok := signingKey.JWK(); ok {
+			keySet.Keys = append(keySet.Keys, jwk)
+		}
+	}
+	return keySet
+}
+
+func (s *JwtTokenSigner) Rotate() (*response.SigningKeyResponse, error) {
+	signingKey, err := s.keyRing.RotateGenerated()
+	if err != nil {
+		return nil, err
+	}
+	return &response.SigningKeyResponse{KeyID: signingKey.ID, Algorithm: signingKey.Algorithm, Status: "active"}, nil
+}
+
+func (s *JwtTokenSigner) Keys() []response.SigningKeyResponse {
+	return s.keyRing.Keys()
+}
@@ -0,0 +1,463 @@
+package authentication
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultKeyOverlap = 24 * time.Hour
+
+// Tokens signed with an unknown key ID reload the shared keys at most this often
+const storeReloadThrottle = 10 * time.Second
+
+type retiredKey struct {
+	key             *SigningKey
+	verifiableUntil time.Time
+}
+
+// Holds the active signing key and the retired keys that can still verify tokens until their window closes.
+// Generated keys are shared with the other instances through the store, so every instance signs and
+// verifies with the same keys.
+type KeyRing struct {
+	mutex       sync.RWMutex
+	active      *SigningKey
+	activatedAt time.Time
+	retired     []retiredKey
+	overlap     time.Duration
+	store       interfaces.SigningKeyRepository
+	cipher      *SigningKeyCipher
+	lastReload  time.Time
+}
+
+func NewKeyRing(active *SigningKey, overlap time.Duration) *KeyRing {
+	return &KeyRing{
+		active:  active,
+		overlap: overlap,
+	}
+}
+
+// Builds the key ring from the environment:
+// JWT_RETIRED_KEY_PATHS (comma separated PEM files) or JWT_RETIRED_SECRETS (comma separated kid:secret pairs)
+// stay verifiable for JWT_KEY_OVERLAP after startup, which allows rotating keys through a rolling restart.
+func LoadKeyRingFromEnv() (*KeyRing, error) {
+	active, err := LoadSigningKeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	overlap := defaultKeyOverlap
+	if value := os.Getenv("JWT_KEY_OVERLAP"); value != "" {
+		overlap, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_KEY_OVERLAP: %w", err)
+		}
+	}
+
+	keyRing := NewKeyRing(active, overlap)
+	verifiableUntil := time.Now().Add(overlap)
+
+	// The configured key counts as activated when its PEM file was written, so a newly configured key takes over
+	// from keys generated before it while a restart with the same file keeps the newer generated keys
+	keyRing.activatedAt = time.Now()
+	if !active.IsSymmetric() {
+		if info, err := os.Stat(os.Getenv("JWT_PRIVATE_KEY_PATH")); err == nil {
+			keyRing.activatedAt = info.ModTime()
+		}
+	}
+
+	for _, path := range splitList(os.Getenv("JWT_RETIRED_KEY_PATHS")) {
+		retired, err := LoadSigningKey(active.Algorithm, "", path)
+		if err != nil {
+			return nil, err
+		}
+		keyRing.AddRetiredKey(retired, verifiableUntil)
+	}
+
+	for _, pair := range splitList(os.Getenv("JWT_RETIRED_SECRETS")) {
+		keyID, secret, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid JWT_RETIRED_SECRETS entry, expected kid:secret")
+		}
+		retired, err := NewHMACSigningKey(keyID, []byte(secret))
+		if err != nil {
+			return nil, err
+		}
+		keyRing.AddRetiredKey(retired, verifiableUntil)
+	}
+
+	return keyRing, nil
+}
+
+func (ring *KeyRing) Active() *SigningKey {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	return ring.active
+}
+
+// Returns the active or a still verifiable retired key, tokens without a kid are verified with the active key.
+// An unknown kid may belong to a key another instance just rotated to, so the shared keys are reloaded once.
+func (ring *KeyRing) Lookup(kid string) (*SigningKey, error) {
+	if key := ring.lookup(kid); key != nil {
+		return key, nil
+	}
+
+	if ring.reloadForUnknownKey() {
+		if key := ring.lookup(kid); key != nil {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown or expired signing key: %s", kid)
+}
+
+func (ring *KeyRing) lookup(kid string) *SigningKey {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	if kid == "" || kid == ring.active.ID {
+		return ring.active
+	}
+
+	for _, retired := range ring.retired {
+		if retired.key.ID == kid && time.Now().Before(retired.verifiableUntil) {
+			return retired.key
+		}
+	}
+
+	return nil
+}
+
+func (ring *KeyRing) reloadForUnknownKey() bool {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	if ring.store == nil || time.Since(ring.lastReload) < storeReloadThrottle {
+		return false
+	}
+	ring.lastReload = time.Now()
+
+	if err := ring.loadStoredKeys(); err != nil {
+		log.Printf("An error occurred while reloading the signing keys: %v", err)
+		return false
+	}
+	return true
+}
+
+func (ring *KeyRing) AddRetiredKey(key *SigningKey, verifiableUntil time.Time) {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	ring.retired = append(ring.retired, retiredKey{key: key, verifiableUntil: verifiableUntil})
+}
+
+// Makes the given key active, the previous key keeps verifying tokens for the overlap window
+func (ring *KeyRing) Rotate(newKey *SigningKey) {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	ring.rotate(newKey, time.Now())
+}
+
+func (ring *KeyRing) rotate(newKey *SigningKey, activatedAt time.Time) {
+	ring.pruneExpired()
+	ring.retired = append(ring.retired, retiredKey{key: ring.active, verifiableUntil: activatedAt.Add(ring.overlap)})
+	ring.active = newKey
+	ring.activatedAt = activatedAt
+
+	log.Printf(
+		"Signing key rotated:\n  Active Key ID: %s\n  Timestamp: %s",
+		newKey.ID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+// Generates a new key using the algorithm of the active key, stores it for the other instances and rotates to it.
+// Symmetric keys are refused, downstream services verify them with the configured secret and a generated
+// secret would never reach them. When the key can't be stored the active key is kept, otherwise this
+// instance would sign with a key the other instances never see.
+func (ring *KeyRing) RotateGenerated() (*SigningKey, error) {
+	algorithm := ring.Active().Algorithm
+	if algorithm == AlgorithmHS256 {
+		return nil, errors.NewKeyRotationNotSupportedError(algorithm, 409)
+	}
+
+	newKey, err := GenerateSigningKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	activatedAt := time.Now()
+	if ring.store != nil {
+		privateKey, err := encodePrivateKey(newKey, ring.cipher)
+		if err != nil {
+			return nil, err
+		}
+		err = ring.store.Create(entities.SigningKeyEntity{
+			KeyID:       newKey.ID,
+			Algorithm:   newKey.Algorithm,
+			PrivateKey:  privateKey,
+			ActivatedAt: activatedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store signing key: %w", err)
+		}
+	}
+
+	ring.rotate(newKey, activatedAt)
+	return newKey, nil
+}
+
+// Shares generated keys through the store and adopts the keys the other instances already generated,
+// private keys are only stored encrypted with the cipher
+func (ring *KeyRing) UseStore(store interfaces.SigningKeyRepository, cipher *SigningKeyCipher) error {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	ring.store = store
+	ring.cipher = cipher
+	return ring.loadStoredKeys()
+}
+
+// Picks up keys rotated by the other instances
+func (ring *KeyRing) Reload() error {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	if ring.store == nil {
+		return nil
+	}
+	return ring.loadStoredKeys()
+}
+
+// The newest stored key becomes active when it is newer than the active key, older stored keys stay
+// verifiable until the overlap window after their successor was activated closes. A newer configured key
+// is the successor of the newest stored key.
+func (ring *KeyRing) loadStoredKeys() error {
+	var storedKeys []entities.SigningKeyEntity
+	for _, keyEntity := range ring.store.GetAll() {
+		if keyEntity.Algorithm == ring.active.Algorithm {
+			storedKeys = append(storedKeys, keyEntity)
+		}
+	}
+
+	for i, keyEntity := range storedKeys {
+		var verifiableUntil time.Time
+		if i == len(storedKeys)-1 {
+			if keyEntity.KeyID != ring.active.ID && keyEntity.ActivatedAt.After(ring.activatedAt) {
+				signingKey, err := decodeSigningKey(keyEntity, ring.cipher)
+				if err != nil {
+					return err
+				}
+				ring.rotate(signingKey, keyEntity.ActivatedAt)
+				continue
+			}
+			verifiableUntil = ring.activatedAt.Add(ring.overlap)
+		} else {
+			verifiableUntil = storedKeys[i+1].ActivatedAt.Add(ring.overlap)
+		}
+
+		if keyEntity.KeyID == ring.active.ID || ring.isRetired(keyEntity.KeyID) || !time.Now().Before(verifiableUntil) {
+			continue
+		}
+
+		signingKey, err := decodeSigningKey(keyEntity, ring.cipher)
+		if err != nil {
+			return err
+		}
+		ring.retired = append(ring.retired, retiredKey{key: signingKey, verifiableUntil: verifiableUntil})
+	}
+
+	return nil
+}
+
+func (ring *KeyRing) isRetired(kid string) bool {
+	for _, retired := range ring.retired {
+		if retired.key.ID == kid {
+			return true
+		}
+	}
+	return false
+}
+
+// Rotates the keys on a fixed interval, only asymmetric keys can be generated. An instance skips its
+// rotation when another instance already rotated within the interval.
+func (ring *KeyRing) StartRotationSchedule(interval time.Duration) error {
+	if algorithm := ring.Active().Algorithm; algorithm == AlgorithmHS256 {
+		return errors.NewKeyRotationNotSupportedError(algorithm, 409)
+	}
+
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			if err := ring.Reload(); err != nil {
+				log.Printf("An error occurred while reloading the signing keys: %v", err)
+			}
+			if time.Since(ring.activeSince()) < interval {
+				continue
+			}
+
+			if _, err := ring.RotateGenerated(); err != nil {
+				log.Printf("An error occurred while rotating the signing key: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Reloads the shared keys on a fixed interval, so tokens are signed with the newest key everywhere
+func (ring *KeyRing) StartSyncSchedule(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			if err := ring.Reload(); err != nil {
+				log.Printf("An error occurred while reloading the signing keys: %v", err)
+			}
+		}
+	}()
+}
+
+func (ring *KeyRing) activeSince() time.Time {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	return ring.activatedAt
+}
+
+func (ring *KeyRing) Keys() []response.SigningKeyResponse {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	keys := []response.SigningKeyResponse{
+		{KeyID: ring.active.ID, Algorithm: ring.active.Algorithm, Status: "active"},
+	}
+	for _, retired := range ring.retired {
+		if time.Now().Before(retired.verifiableUntil) {
+			verifiableUntil := retired.verifiableUntil
+			keys = append(keys, response.SigningKeyResponse{
+				KeyID:           retired.key.ID,
+				Algorithm:       retired.key.Algorithm,
+				Status:          "retired",
+				VerifiableUntil: &verifiableUntil,
+			})
+		}
+	}
+
+	return keys
+}
+
+func (ring *KeyRing) verifiableKeys() []*SigningKey {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	keys := []*SigningKey{ring.active}
+	for _, retired := range ring.retired {
+		if time.Now().Before(retired.verifiableUntil) {
+			keys = append(keys, retired.key)
+		}
+	}
+
+	return keys
+}
+
+func (ring *KeyRing) pruneExpired() {
+	var stillVerifiable []retiredKey
+	for _, retired := range ring.retired {
+		if time.Now().Before(retired.verifiableUntil) {
+			stillVerifiable = append(stillVerifiable, retired)
+		}
+	}
+	ring.retired = stillVerifiable
+}
+
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	switch algorithm {
+	case AlgorithmHS256:
+		keyID, err := GenerateOpaqueToken()
+		if err != nil {
+			return nil, err
+		}
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		return NewHMACSigningKey(keyID[:16], secret)
+	case AlgorithmRS256:
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		return NewSigningKey(algorithm, "", privateKey)
+	case AlgorithmES256:
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return NewSigningKey(algorithm, "", privateKey)
+	case AlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return NewSigningKey(algorithm, "", privateKey)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+}
+
+func encodePrivateKey(key *SigningKey, cipher *SigningKeyCipher) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode private key: %w", err)
+	}
+	return cipher.Encrypt(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), key.ID)
+}
+
+func decodeSigningKey(keyEntity entities.SigningKeyEntity, cipher *SigningKeyCipher) (*SigningKey, error) {
+	pemBytes, err := cipher.Decrypt(keyEntity.PrivateKey, keyEntity.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block of signing key %s", keyEntity.KeyID)
+	}
+
+	privateKey, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSigningKey(keyEntity.Algorithm, keyEntity.KeyID, privateKey)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
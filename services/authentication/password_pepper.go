@@ -0,0 +1,83 @@
+package authentication
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const pepperPrefix = "$pepper$"
+
+// Secret mixed into every password before hashing, it is never stored in the database so a
+// dump of the Account table alone can't be cracked offline
+type PasswordPepper struct {
+	Version string
+	secret  []byte
+}
+
+func NewPasswordPepper(version string, secret []byte) (*PasswordPepper, error) {
+	if version == "" || strings.ContainsAny(version, "$:,") {
+		return nil, fmt.Errorf("invalid pepper version %q", version)
+	}
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("the secret of pepper %s must be at least 32 bytes", version)
+	}
+
+	return &PasswordPepper{
+		Version: version,
+		secret:  secret,
+	}, nil
+}
+
+// PASSWORD_PEPPER holds the version:secret used for new hashes, PASSWORD_RETIRED_PEPPERS a comma separated
+// list of version:secret pairs that are still accepted until every hash has been migrated on login
+func LoadPeppersFromEnv() (*PasswordPepper, []*PasswordPepper, error) {
+	var pepper *PasswordPepper
+	if value := os.Getenv("PASSWORD_PEPPER"); value != "" {
+		var err error
+		if pepper, err = parsePepper(value); err != nil {
+			return nil, nil, fmt.Errorf("invalid PASSWORD_PEPPER: %w", err)
+		}
+	}
+
+	var retired []*PasswordPepper
+	for _, pair := range splitList(os.Getenv("PASSWORD_RETIRED_PEPPERS")) {
+		retiredPepper, err := parsePepper(pair)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid PASSWORD_RETIRED_PEPPERS entry: %w", err)
+		}
+		retired = append(retired, retiredPepper)
+	}
+
+	return pepper, retired, nil
+}
+
+func parsePepper(pair string) (*PasswordPepper, error) {
+	version, secret, found := strings.Cut(pair, ":")
+	if !found {
+		return nil, fmt.Errorf("expected version:secret")
+	}
+	return NewPasswordPepper(version, []byte(secret))
+}
+
+// The HMAC is encoded so it stays within the 72 byte input limit of bcrypt
+func (pepper *PasswordPepper) Apply(password string) string {
+	mac := hmac.New(sha256.New, pepper.secret)
+	mac.Write([]byte(password))
+	return base64.RawStdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Peppered hashes look like $pepper$<version>$argon2id$..., other hashes have no version
+func splitPepperVersion(encodedPassword string) (string, string) {
+	if !strings.HasPrefix(encodedPassword, pepperPrefix) {
+		return "", encodedPassword
+	}
+	version, hash, found := strings.Cut(strings.TrimPrefix(encodedPassword, pepperPrefix), "$")
+	if !found {
+		return "", ""
+	}
+	return version, "$" + hash
+}
@@ -0,0 +1,23 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// Generates a recovery code with 80 bits of entropy, formatted as four groups for readability
+func GenerateRecoveryCode() (string, error) {
+	bytes := make([]byte, 10)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(totpEncoding.EncodeToString(bytes))
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16], nil
+}
+
+// Recovery codes are accepted regardless of case, dashes and spaces
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return HashOpaqueToken(normalized)
+}
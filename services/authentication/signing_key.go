@@ -0,0 +1,174 @@
+package authentication
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"flyhorizons-userservice/models/response"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey interface{}
+	PublicKey  interface{}
+}
+
+// Loads the signing key configured through the environment, falling back to HS256 with JWT_SECRET
+func LoadSigningKeyFromEnv() (*SigningKey, error) {
+	algorithm := os.Getenv("JWT_SIGNING_ALGORITHM")
+	if algorithm == "" {
+		algorithm = AlgorithmHS256
+	}
+
+	if algorithm == AlgorithmHS256 {
+		return NewHMACSigningKey(os.Getenv("JWT_KEY_ID"), []byte(os.Getenv("JWT_SECRET")))
+	}
+
+	return LoadSigningKey(algorithm, os.Getenv("JWT_KEY_ID"), os.Getenv("JWT_PRIVATE_KEY_PATH"))
+}
+
+func NewHMACSigningKey(keyID string, secret []byte) (*SigningKey, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("no secret provided for the %s signing key", AlgorithmHS256)
+	}
+	if keyID == "" {
+		keyID = "default"
+	}
+
+	return &SigningKey{
+		ID:         keyID,
+		Algorithm:  AlgorithmHS256,
+		PrivateKey: secret,
+		PublicKey:  secret,
+	}, nil
+}
+
+// Loads an asymmetric private key from a PEM file, the key ID defaults to the thumbprint of the public key
+func LoadSigningKey(algorithm string, keyID string, privateKeyPath string) (*SigningKey, error) {
+	pemBytes, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block in %s", privateKeyPath)
+	}
+
+	privateKey, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSigningKey(algorithm, keyID, privateKey)
+}
+
+func NewSigningKey(algorithm string, keyID string, privateKey interface{}) (*SigningKey, error) {
+	var publicKey interface{}
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if algorithm != AlgorithmRS256 {
+			return nil, fmt.Errorf("an RSA key cannot be used with the %s algorithm", algorithm)
+		}
+		publicKey = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		if algorithm != AlgorithmES256 || key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("an ECDSA key can only be used with %s on the P-256 curve", AlgorithmES256)
+		}
+		publicKey = &key.PublicKey
+	case ed25519.PrivateKey:
+		if algorithm != AlgorithmEdDSA {
+			return nil, fmt.Errorf("an Ed25519 key cannot be used with the %s algorithm", algorithm)
+		}
+		publicKey = key.Public()
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+
+	if keyID == "" {
+		thumbprint, err := publicKeyThumbprint(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		keyID = thumbprint
+	}
+
+	return &SigningKey{
+		ID:         keyID,
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
+func (key *SigningKey) IsSymmetric() bool {
+	return key.Algorithm == AlgorithmHS256
+}
+
+// Converts the public key to a JSON Web Key, symmetric keys are never published
+func (key *SigningKey) JWK() (response.JSONWebKey, bool) {
+	jwk := response.JSONWebKey{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Algorithm,
+	}
+
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		jwk.KeyType = "EC"
+		jwk.Curve = publicKey.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, 32)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, 32)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	default:
+		return response.JSONWebKey{}, false
+	}
+
+	return jwk, true
+}
+
+func parsePrivateKey(block *pem.Block) (interface{}, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+func publicKeyThumbprint(publicKey interface{}) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	hash := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(hash[:16]), nil
+}
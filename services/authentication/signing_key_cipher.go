@@ -0,0 +1,70 @@
+package authentication
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// Encrypts generated private keys before they are shared through the database. The secret is never stored
+// in the database, so a dump of the SigningKey table alone can't be used to sign tokens.
+type SigningKeyCipher struct {
+	aead cipher.AEAD
+}
+
+func NewSigningKeyCipher(secret []byte) (*SigningKeyCipher, error) {
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("the signing key encryption secret must be at least 32 bytes")
+	}
+
+	// AES-256-GCM with a key derived from the secret
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKeyCipher{aead: aead}, nil
+}
+
+// JWT_KEY_ENCRYPTION_SECRET holds the secret, every instance sharing the keys needs the same one
+func LoadSigningKeyCipherFromEnv() (*SigningKeyCipher, error) {
+	signingKeyCipher, err := NewSigningKeyCipher([]byte(os.Getenv("JWT_KEY_ENCRYPTION_SECRET")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEY_ENCRYPTION_SECRET: %w", err)
+	}
+	return signingKeyCipher, nil
+}
+
+// The key ID is authenticated along with the key, so an encrypted key can't be moved to another row
+func (signingKeyCipher *SigningKeyCipher) Encrypt(plaintext []byte, keyID string) (string, error) {
+	nonce := make([]byte, signingKeyCipher.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := signingKeyCipher.aead.Seal(nonce, nonce, plaintext, []byte(keyID))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (signingKeyCipher *SigningKeyCipher) Decrypt(ciphertext string, keyID string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < signingKeyCipher.aead.NonceSize() {
+		return nil, fmt.Errorf("failed to decode encrypted signing key %s", keyID)
+	}
+
+	nonceSize := signingKeyCipher.aead.NonceSize()
+	plaintext, err := signingKeyCipher.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signing key %s: %w", keyID, err)
+	}
+	return plaintext, nil
+}
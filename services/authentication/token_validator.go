@@ -0,0 +1,59 @@
+package authentication
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Audience of every access token issued by this service
+const AccessTokenAudience = "flyhorizons-api"
+
+type JwtTokenValidator struct {
+	keyProvider interfaces.TokenKeyProvider
+}
+
+var _ interfaces.TokenValidator = (*JwtTokenValidator)(nil)
+
+func NewJwtTokenValidator(keyProvider interfaces.TokenKeyProvider) *JwtTokenValidator {
+	return &JwtTokenValidator{
+		keyProvider: keyProvider,
+	}
+}
+
+// Verifies the signature and expiry of a token, revocation is checked separately
+func (v *JwtTokenValidator) Validate(tokenString string) (jwt.MapClaims, error) {
+	// Parse the JWT token, the verification key is selected by the kid header
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		algorithm, key, err := v.keyProvider.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid JWT token")
+	}
+
+	// Extract claims
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("invalid JWT claims")
+	}
+
+	// ID tokens are issued to clients and must not be usable as access tokens
+	if !claims.VerifyAudience(AccessTokenAudience, false) {
+		return nil, fmt.Errorf("invalid JWT audience")
+	}
+
+	return claims, nil
+}
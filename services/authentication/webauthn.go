@@ -0,0 +1,283 @@
+package authentication
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithms accepted for passkeys (RFC 9053), the same list is sent in the registration options
+const (
+	COSEAlgorithmES256 int64 = -7
+	COSEAlgorithmRS256 int64 = -257
+)
+
+// Authenticator data flags (WebAuthn Level 2, section 6.1)
+const (
+	authenticatorFlagUserPresent            = 0x01
+	authenticatorFlagUserVerified           = 0x04
+	authenticatorFlagAttestedCredentialData = 0x40
+	authenticatorFlagExtensionData          = 0x80
+)
+
+// Client data as collected by the browser, the signature of the authenticator covers its hash
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+func ParseClientData(clientDataJSON []byte) (ClientData, error) {
+	var clientData ClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return ClientData{}, fmt.Errorf("webauthn: invalid client data: %w", err)
+	}
+	return clientData, nil
+}
+
+type AuthenticatorData struct {
+	RPIDHash  []byte
+	Flags     byte
+	SignCount uint32
+	// Only present in the authenticator data of a registration
+	CredentialID        []byte
+	CredentialPublicKey []byte
+}
+
+func (authData AuthenticatorData) UserPresent() bool {
+	return authData.Flags&authenticatorFlagUserPresent != 0
+}
+
+func (authData AuthenticatorData) UserVerified() bool {
+	return authData.Flags&authenticatorFlagUserVerified != 0
+}
+
+// Layout: rpIdHash (32) | flags (1) | signCount (4) | attested credential data | extensions
+func ParseAuthenticatorData(data []byte) (AuthenticatorData, error) {
+	if len(data) < 37 {
+		return AuthenticatorData{}, fmt.Errorf("webauthn: authenticator data is too short")
+	}
+
+	authData := AuthenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if authData.Flags&authenticatorFlagAttestedCredentialData != 0 {
+		// aaguid (16) | credentialIdLength (2) | credentialId | credentialPublicKey
+		if len(rest) < 18 {
+			return AuthenticatorData{}, fmt.Errorf("webauthn: attested credential data is too short")
+		}
+		credentialIDLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if len(rest) < credentialIDLength {
+			return AuthenticatorData{}, fmt.Errorf("webauthn: attested credential data is too short")
+		}
+		authData.CredentialID = rest[:credentialIDLength]
+		rest = rest[credentialIDLength:]
+
+		// The public key is a CBOR map without a length prefix, decoding it tells where it ends
+		_, afterKey, err := decodeCBOR(rest)
+		if err != nil {
+			return AuthenticatorData{}, fmt.Errorf("webauthn: invalid credential public key: %w", err)
+		}
+		authData.CredentialPublicKey = rest[:len(rest)-len(afterKey)]
+		rest = afterKey
+	}
+
+	if authData.Flags&authenticatorFlagExtensionData != 0 {
+		_, afterExtensions, err := decodeCBOR(rest)
+		if err != nil {
+			return AuthenticatorData{}, fmt.Errorf("webauthn: invalid extension data: %w", err)
+		}
+		rest = afterExtensions
+	}
+
+	if len(rest) != 0 {
+		return AuthenticatorData{}, fmt.Errorf("webauthn: unexpected trailing authenticator data")
+	}
+
+	return authData, nil
+}
+
+type AttestationObject struct {
+	Format      string
+	Statement   map[interface{}]interface{}
+	RawAuthData []byte
+	AuthData    AuthenticatorData
+}
+
+func ParseAttestationObject(data []byte) (AttestationObject, error) {
+	decoded, rest, err := decodeCBOR(data)
+	if err != nil || len(rest) != 0 {
+		return AttestationObject{}, fmt.Errorf("webauthn: invalid attestation object")
+	}
+
+	entries, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return AttestationObject{}, fmt.Errorf("webauthn: invalid attestation object")
+	}
+	format, _ := entries["fmt"].(string)
+	statement, _ := entries["attStmt"].(map[interface{}]interface{})
+	rawAuthData, _ := entries["authData"].([]byte)
+	if format == "" || statement == nil || rawAuthData == nil {
+		return AttestationObject{}, fmt.Errorf("webauthn: incomplete attestation object")
+	}
+
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return AttestationObject{}, err
+	}
+	if authData.CredentialID == nil {
+		return AttestationObject{}, fmt.Errorf("webauthn: attestation object does not contain a credential")
+	}
+
+	return AttestationObject{
+		Format:      format,
+		Statement:   statement,
+		RawAuthData: rawAuthData,
+		AuthData:    authData,
+	}, nil
+}
+
+// Checks the attestation statement signature. Passengers register consumer authenticators, so the
+// attestation is not checked against trusted roots and only the "none" and "packed" formats are accepted.
+func (attestation AttestationObject) Verify(clientDataHash []byte) error {
+	switch attestation.Format {
+	case "none":
+		if len(attestation.Statement) != 0 {
+			return fmt.Errorf("webauthn: none attestation must have an empty statement")
+		}
+		return nil
+	case "packed":
+		algorithm, _ := attestation.Statement["alg"].(int64)
+		signature, _ := attestation.Statement["sig"].([]byte)
+		if signature == nil {
+			return fmt.Errorf("webauthn: packed attestation without signature")
+		}
+		signedData := append(append([]byte{}, attestation.RawAuthData...), clientDataHash...)
+
+		// Full attestation is signed by the attestation certificate, self attestation by the credential itself
+		if certificates, ok := attestation.Statement["x5c"].([]interface{}); ok && len(certificates) > 0 {
+			rawCertificate, _ := certificates[0].([]byte)
+			certificate, err := x509.ParseCertificate(rawCertificate)
+			if err != nil {
+				return fmt.Errorf("webauthn: invalid attestation certificate: %w", err)
+			}
+			return verifyCOSESignature(algorithm, certificate.PublicKey, signedData, signature)
+		}
+
+		credentialAlgorithm, publicKey, err := ParseCOSEPublicKey(attestation.AuthData.CredentialPublicKey)
+		if err != nil {
+			return err
+		}
+		if algorithm != credentialAlgorithm {
+			return fmt.Errorf("webauthn: self attestation algorithm does not match the credential")
+		}
+		return verifyCOSESignature(algorithm, publicKey, signedData, signature)
+	default:
+		return fmt.Errorf("webauthn: unsupported attestation format %q", attestation.Format)
+	}
+}
+
+// Returns the algorithm and public key of a COSE_Key, only ES256 and RS256 keys are supported
+func ParseCOSEPublicKey(coseKey []byte) (int64, crypto.PublicKey, error) {
+	decoded, rest, err := decodeCBOR(coseKey)
+	if err != nil || len(rest) != 0 {
+		return 0, nil, fmt.Errorf("webauthn: invalid COSE key")
+	}
+	entries, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return 0, nil, fmt.Errorf("webauthn: invalid COSE key")
+	}
+
+	keyType, _ := entries[int64(1)].(int64)
+	algorithm, _ := entries[int64(3)].(int64)
+
+	switch {
+	case keyType == 2 && algorithm == COSEAlgorithmES256:
+		curve, _ := entries[int64(-1)].(int64)
+		x, _ := entries[int64(-2)].([]byte)
+		y, _ := entries[int64(-3)].([]byte)
+		if curve != 1 || len(x) != 32 || len(y) != 32 {
+			return 0, nil, fmt.Errorf("webauthn: invalid P-256 key")
+		}
+
+		// Rejects points that are not on the curve
+		uncompressed := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(uncompressed); err != nil {
+			return 0, nil, fmt.Errorf("webauthn: invalid P-256 key")
+		}
+		return algorithm, &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case keyType == 3 && algorithm == COSEAlgorithmRS256:
+		modulus, _ := entries[int64(-1)].([]byte)
+		exponent, _ := entries[int64(-2)].([]byte)
+		if len(modulus) < 256 || len(exponent) == 0 || len(exponent) > 4 {
+			return 0, nil, fmt.Errorf("webauthn: invalid RSA key")
+		}
+		return algorithm, &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}, nil
+	default:
+		return 0, nil, fmt.Errorf("webauthn: unsupported COSE key type %d with algorithm %d", keyType, algorithm)
+	}
+}
+
+// Verifies an assertion signature, which covers the authenticator data and the hash of the client data
+func VerifyAssertionSignature(coseKey []byte, authenticatorData []byte, clientDataHash []byte, signature []byte) error {
+	algorithm, publicKey, err := ParseCOSEPublicKey(coseKey)
+	if err != nil {
+		return err
+	}
+
+	signedData := append(append([]byte{}, authenticatorData...), clientDataHash...)
+	return verifyCOSESignature(algorithm, publicKey, signedData, signature)
+}
+
+// Hash of the relying party ID, compared with the hash in the authenticator data
+func RPIDHash(rpID string) []byte {
+	hash := sha256.Sum256([]byte(rpID))
+	return hash[:]
+}
+
+func MatchesRPIDHash(authData AuthenticatorData, rpID string) bool {
+	return bytes.Equal(authData.RPIDHash, RPIDHash(rpID))
+}
+
+func verifyCOSESignature(algorithm int64, publicKey crypto.PublicKey, data []byte, signature []byte) error {
+	hash := sha256.Sum256(data)
+
+	switch algorithm {
+	case COSEAlgorithmES256:
+		key, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok || !ecdsa.VerifyASN1(key, hash[:], signature) {
+			return fmt.Errorf("webauthn: invalid signature")
+		}
+		return nil
+	case COSEAlgorithmRS256:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) != nil {
+			return fmt.Errorf("webauthn: invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("webauthn: unsupported algorithm %d", algorithm)
+	}
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/converter"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const authorizationCodeLifetime = 5 * time.Minute
+
+// Scopes a user can grant to a client in the authorization code flow
+var openIDScopes = []string{"openid", "profile", "email"}
+
+type AuthorizationService struct {
+	userRepo            interfaces.UserRepository
+	clientRepo          interfaces.OAuthClientRepository
+	codeRepo            interfaces.AuthorizationCodeRepository
+	revocationRepo      interfaces.TokenRevocationRepository
+	clientAuthenticator interfaces.ClientAuthenticator
+	userConverter       converter.UserConverter
+	tokenSigner         interfaces.TokenSigner
+	keyProvider         interfaces.TokenKeyProvider
+	issuer              string
+	consentURL          string
+}
+
+var _ interfaces.AuthorizationService = (*AuthorizationService)(nil)
+
+func NewAuthorizationService(userRepo interfaces.UserRepository, clientRepo interfaces.OAuthClientRepository, codeRepo interfaces.AuthorizationCodeRepository, revocationRepo interfaces.TokenRevocationRepository, clientAuthenticator interfaces.ClientAuthenticator, userConverter converter.UserConverter, tokenSigner interfaces.TokenSigner, keyProvider interfaces.TokenKeyProvider, issuer string, consentURL string) *AuthorizationService {
+	return &AuthorizationService{
+		userRepo:            userRepo,
+		clientRepo:          clientRepo,
+		codeRepo:            codeRepo,
+		revocationRepo:      revocationRepo,
+		clientAuthenticator: clientAuthenticator,
+		userConverter:       userConverter,
+		tokenSigner:         tokenSigner,
+		keyProvider:         keyProvider,
+		issuer:              strings.TrimSuffix(issuer, "/"),
+		consentURL:          consentURL,
+	}
+}
+
+// Returns the URL the user agent is redirected to, the consent page where the user signs in and approves
+// the client. Requests with an unknown client or redirect URI fail with an error, every other problem is
+// reported to the client through the redirect.
+func (service *AuthorizationService) Authorize(authorizeRequest request.AuthorizeRequest) (string, error) {
+	client, redirectURL, scopes, errorParams, err := service.validateAuthorizeRequest(authorizeRequest)
+	if err != nil {
+		return "", err
+	}
+	if errorParams != nil {
+		return authorizationRedirect(*redirectURL, errorParams, authorizeRequest.State), nil
+	}
+
+	consentURL, err := url.Parse(service.consentURL)
+	if err != nil {
+		return "", err
+	}
+
+	// The consent page posts the same parameters back to /oauth/authorize/consent with the decision of the user
+	return authorizationRedirect(*consentURL, url.Values{
+		"response_type":         {authorizeRequest.ResponseType},
+		"client_id":             {client.ClientID},
+		"client_name":           {client.Name},
+		"redirect_uri":          {authorizeRequest.RedirectURI},
+		"scope":                 {strings.Join(scopes, " ")},
+		"nonce":                 {authorizeRequest.Nonce},
+		"code_challenge":        {authorizeRequest.CodeChallenge},
+		"code_challenge_method": {authorizeRequest.CodeChallengeMethod},
+	}, authorizeRequest.State), nil
+}
+
+// Returns the URL of the client the user agent is sent back to, with a code when the signed in user approved
+// the client and with an access_denied error otherwise. The request is validated again, the consent page
+// can't be trusted to pass the parameters on unchanged.
+func (service *AuthorizationService) GrantConsent(consentRequest request.ConsentRequest, userID int) (string, error) {
+	authorizeRequest := consentRequest.AuthorizeRequest
+	client, redirectURL, scopes, errorParams, err := service.validateAuthorizeRequest(authorizeRequest)
+	if err != nil {
+		return "", err
+	}
+	if errorParams != nil {
+		return authorizationRedirect(*redirectURL, errorParams, authorizeRequest.State), nil
+	}
+
+	if !consentRequest.Approved {
+		log.Printf(
+			"Authorization denied by user:\n  User ID: %v\n  Client ID: %s\n  Timestamp: %s",
+			userID,
+			client.ClientID,
+			time.Now().Format(time.RFC3339),
+		)
+		return authorizationRedirect(*redirectURL, url.Values{"error": {"access_denied"}}, authorizeRequest.State), nil
+	}
+
+	code, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	// Only the hash is stored, the raw code is handed to the client once
+	service.codeRepo.Create(entities.AuthorizationCodeEntity{
+		CodeHash:      authentication.HashOpaqueToken(code),
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   authorizeRequest.RedirectURI,
+		Scope:         strings.Join(scopes, " "),
+		CodeChallenge: authorizeRequest.CodeChallenge,
+		Nonce:         authorizeRequest.Nonce,
+		AuthTime:      time.Now(),
+		ExpiresAt:     time.Now().Add(authorizationCodeLifetime),
+	})
+
+	log.Printf(
+		"Successful authorization:\n  User ID: %v\n  Client ID: %s\n  Timestamp: %s",
+		userID,
+		client.ClientID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return authorizationRedirect(*redirectURL, url.Values{"code": {code}}, authorizeRequest.State), nil
+}
+
+// Requests with an unknown client or redirect URI fail with an error. Other problems are returned as the
+// error parameters of the redirect to the client.
+func (service *AuthorizationService) validateAuthorizeRequest(authorizeRequest request.AuthorizeRequest) (entities.OAuthClientEntity, *url.URL, []string, url.Values, error) {
+	client := service.clientRepo.GetByClientID(authorizeRequest.ClientID)
+	if client.ID == 0 {
+		return client, nil, nil, nil, errors.NewInvalidClientError(400)
+	}
+
+	// Redirect URIs are compared exactly, anything looser allows open redirects
+	if !slices.Contains(strings.Fields(client.RedirectURIs), authorizeRequest.RedirectURI) {
+		return client, nil, nil, nil, errors.NewInvalidRedirectURIError(400)
+	}
+	redirectURL, err := url.Parse(authorizeRequest.RedirectURI)
+	if err != nil {
+		return client, nil, nil, nil, errors.NewInvalidRedirectURIError(400)
+	}
+
+	if authorizeRequest.ResponseType != "code" {
+		return client, redirectURL, nil, url.Values{"error": {"unsupported_response_type"}}, nil
+	}
+
+	// PKCE is required for every client and only S256 challenges are accepted
+	if authorizeRequest.CodeChallengeMethod != "S256" || !isValidCodeChallenge(authorizeRequest.CodeChallenge) {
+		return client, redirectURL, nil, url.Values{
+			"error":             {"invalid_request"},
+			"error_description": {"code_challenge with code_challenge_method S256 is required"},
+		}, nil
+	}
+
+	scopes := strings.Fields(authorizeRequest.Scope)
+	if len(scopes) == 0 {
+		scopes = []string{"openid"}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(openIDScopes, scope) {
+			return client, redirectURL, nil, url.Values{"error": {"invalid_scope"}}, nil
+		}
+	}
+
+	return client, redirectURL, scopes, nil, nil
+}
+
+func (service *AuthorizationService) ExchangeAuthorizationCode(codeRequest request.AuthorizationCodeRequest) (*response.OAuthTokenResponse, error) {
+	client := service.clientRepo.GetByClientID(codeRequest.ClientID)
+	if client.ID == 0 {
+		return nil, errors.NewInvalidClientError(401)
+	}
+
+	// Confidential clients authenticate with their secret, public clients rely on PKCE alone
+	if !client.Public && !service.clientAuthenticator.Authenticate(codeRequest.ClientID, codeRequest.ClientSecret) {
+		return nil, errors.NewInvalidClientError(401)
+	}
+
+	code := service.codeRepo.GetByCodeHash(authentication.HashOpaqueToken(codeRequest.Code))
+	if code.ID == 0 || code.ClientID != client.ClientID || code.RedirectURI != codeRequest.RedirectURI || time.Now().After(code.ExpiresAt) {
+		return nil, errors.NewInvalidGrantError(400)
+	}
+	if !matchesCodeChallenge(codeRequest.CodeVerifier, code.CodeChallenge) {
+		return nil, errors.NewInvalidGrantError(400)
+	}
+
+	// A reused code was probably intercepted, so the access token issued for it is revoked as well
+	if code.UsedAt != nil {
+		service.revokeIssuedToken(code)
+		return nil, errors.NewInvalidGrantError(400)
+	}
+
+	accountEntity := service.userRepo.GetByID(code.UserID)
+	if accountEntity.ID == 0 {
+		return nil, errors.NewInvalidGrantError(400)
+	}
+	account := service.userConverter.ConvertUserEntityToUser(accountEntity)
+
+	// The access token identifies the user to the client, limited to the granted scope. It carries no role,
+	// the gateway middleware only accepts tokens of an authorized party on the userinfo endpoint.
+	claims, err := newAccessTokenClaims(account)
+	if err != nil {
+		return nil, err
+	}
+	delete(claims, "role")
+	claims["scope"] = code.Scope
+	claims["azp"] = client.ClientID // Authorized party
+
+	// A code can only be exchanged once, the ID of the access token is stored with it so it can be revoked on reuse
+	if !service.codeRepo.MarkAsUsed(code.ID, claims["jti"].(string)) {
+		service.revokeIssuedToken(service.codeRepo.GetByCodeHash(code.CodeHash))
+		return nil, errors.NewInvalidGrantError(400)
+	}
+
+	accessToken, err := service.tokenSigner.SignToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenResponse := &response.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(accessTokenLifetime.Seconds()),
+		Scope:       code.Scope,
+	}
+
+	if slices.Contains(strings.Fields(code.Scope), "openid") {
+		tokenResponse.IDToken, err = service.generateIDToken(account, code)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tokenResponse, nil
+}
+
+func (service *AuthorizationService) revokeIssuedToken(code entities.AuthorizationCodeEntity) {
+	log.Printf(
+		"Authorization code reuse detected:\n  User ID: %v\n  Client ID: %s\n  Timestamp: %s",
+		code.UserID,
+		code.ClientID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	if code.AccessTokenID == "" {
+		return
+	}
+	service.revocationRepo.RevokeToken(entities.RevokedTokenEntity{
+		JTI:       code.AccessTokenID,
+		UserID:    code.UserID,
+		ExpiresAt: time.Now().Add(accessTokenLifetime),
+		RevokedAt: time.Now(),
+	})
+}
+
+// Returns the claims of the user that the scope of the access token allows, tokens from /login see every claim
+func (service *AuthorizationService) UserInfo(userID int, scope string) (*response.UserInfoResponse, error) {
+	accountEntity := service.userRepo.GetByID(userID)
+	if accountEntity.ID == 0 {
+		return nil, errors.NewUserNotFoundError(userID, 404)
+	}
+	account := service.userConverter.ConvertUserEntityToUser(accountEntity)
+
+	userInfo := &response.UserInfoResponse{Subject: strconv.Itoa(account.ID)}
+	scopes := strings.Fields(scope)
+	if scope == "" || slices.Contains(scopes, "profile") {
+		userInfo.Name = account.FullName
+	}
+	if scope == "" || slices.Contains(scopes, "email") {
+		userInfo.Email = account.Email
+		userInfo.EmailVerified = &account.EmailVerified
+	}
+
+	return userInfo, nil
+}
+
+func (service *AuthorizationService) Discovery() response.OpenIDConfigurationResponse {
+	algorithm, _, _ := service.keyProvider.VerificationKey("")
+
+	return response.OpenIDConfigurationResponse{
+		Issuer:                            service.issuer,
+		AuthorizationEndpoint:             service.issuer + "/oauth/authorize",
+		TokenEndpoint:                     service.issuer + "/oauth/token",
+		UserInfoEndpoint:                  service.issuer + "/userinfo",
+		JWKSURI:                           service.issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             service.issuer + "/oauth/introspect",
+		ScopesSupported:                   openIDScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "client_credentials"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{algorithm},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "azp", "name", "email", "email_verified"},
+	}
+}
+
+func (service *AuthorizationService) generateIDToken(account models.User, code entities.AuthorizationCodeEntity) (string, error) {
+	// OpenID Connect claims, the audience is the client so ID tokens are never accepted as access tokens
+	claims := jwt.MapClaims{
+		"iss":       service.issuer,                             // Issuer
+		"sub":       strconv.Itoa(account.ID),                   // Subject (user ID as string)
+		"aud":       code.ClientID,                              // Audience (client ID)
+		"azp":       code.ClientID,                              // Authorized party
+		"iat":       time.Now().Unix(),                          // Issued at
+		"exp":       time.Now().Add(accessTokenLifetime).Unix(), // Expiration
+		"auth_time": code.AuthTime.Unix(),                       // Time of the authorization
+	}
+	if code.Nonce != "" {
+		claims["nonce"] = code.Nonce
+	}
+
+	scopes := strings.Fields(code.Scope)
+	if slices.Contains(scopes, "profile") {
+		claims["name"] = account.FullName
+	}
+	if slices.Contains(scopes, "email") {
+		claims["email"] = account.Email
+		claims["email_verified"] = account.EmailVerified
+	}
+
+	return service.tokenSigner.SignToken(claims)
+}
+
+func authorizationRedirect(redirectURL url.URL, params url.Values, state string) string {
+	query := redirectURL.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	redirectURL.RawQuery = query.Encode()
+
+	return redirectURL.String()
+}
+
+// An S256 challenge is the unpadded base64url encoded SHA-256 hash of the verifier
+func isValidCodeChallenge(codeChallenge string) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(codeChallenge)
+	return err == nil && len(decoded) == sha256.Size
+}
+
+func matchesCodeChallenge(codeVerifier string, codeChallenge string) bool {
+	// RFC 7636 verifiers are between 43 and 128 characters long
+	if len(codeVerifier) < 43 || len(codeVerifier) > 128 {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(codeVerifier))
+	expected := base64.RawURLEncoding.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(codeChallenge)) == 1
+}
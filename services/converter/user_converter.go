@@ -3,6 +3,8 @@ package converter
 import (
 	"flyhorizons-userservice/models"
 	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
 	entities "flyhorizons-userservice/repositories/entity"
 	"time"
 )
@@ -11,22 +13,64 @@ type UserConverter struct{}
 
 func (userConverter *UserConverter) ConvertUserEntityToUser(entity entities.UserEntity) models.User {
 	return models.User{
-		ID:          entity.ID,
-		FullName:    entity.FullName,
-		Email:       entity.Email,
-		AccountType: enums.AccountTypeFromInt(entity.AccountType),
-		Password:    entity.Password,
+		ID:            entity.ID,
+		FullName:      entity.FullName,
+		Email:         entity.Email,
+		EmailVerified: entity.EmailVerified,
+		AccountType:   enums.AccountTypeFromInt(entity.AccountType),
+		Password:      entity.Password,
 	}
 }
 
 func (userConverter *UserConverter) ConvertUserToUserEntity(user models.User) entities.UserEntity {
 	return entities.UserEntity{
-		ID:          user.ID,
-		FullName:    user.FullName,
-		Email:       user.Email,
-		AccountType: int(user.AccountType),
-		Password:    user.Password,
-		// Set the current time of creation/update
-		CreatedAt: time.Now(),
+		ID:            user.ID,
+		FullName:      user.FullName,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		AccountType:   int(user.AccountType),
+		Password:      user.Password,
+		// CreatedAt is not part of the model, it is set once when the account is created
+	}
+}
+
+// New accounts are unverified regular users, the ID is assigned by the database
+func (userConverter *UserConverter) ConvertCreateUserRequestToUserEntity(createRequest request.CreateUserRequest, passwordHash string) entities.UserEntity {
+	return entities.UserEntity{
+		FullName:      createRequest.FullName,
+		Email:         createRequest.Email,
+		EmailVerified: false,
+		AccountType:   int(enums.User),
+		Password:      passwordHash,
+		CreatedAt:     time.Now(),
+		Version:       1,
+	}
+}
+
+// Applies the profile fields to the stored account, every other column keeps its stored value
+func (userConverter *UserConverter) ConvertUpdateUserRequestToUserEntity(updateRequest request.UpdateUserRequest, storedEntity entities.UserEntity) entities.UserEntity {
+	storedEntity.FullName = updateRequest.FullName
+	return storedEntity
+}
+
+func (userConverter *UserConverter) ConvertUserEntityToUserProfileResponse(entity entities.UserEntity) response.UserProfileResponse {
+	return response.UserProfileResponse{
+		ID:            entity.ID,
+		FullName:      entity.FullName,
+		Email:         entity.Email,
+		EmailVerified: entity.EmailVerified,
+		AccountType:   enums.AccountTypeFromInt(entity.AccountType),
+		Version:       entity.Version,
+	}
+}
+
+func (userConverter *UserConverter) ConvertUserEntityToAdminUserResponse(entity entities.UserEntity) response.AdminUserResponse {
+	return response.AdminUserResponse{
+		ID:            entity.ID,
+		FullName:      entity.FullName,
+		Email:         entity.Email,
+		EmailVerified: entity.EmailVerified,
+		AccountType:   enums.AccountTypeFromInt(entity.AccountType),
+		CreatedAt:     entity.CreatedAt,
 	}
 }
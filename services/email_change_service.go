@@ -0,0 +1,192 @@
+package services
+
+import (
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	emailChangeConfirmLifetime = 24 * time.Hour
+	emailChangeRevertLifetime  = 7 * 24 * time.Hour
+)
+
+// Published on the user_email_changed queue once the account row has been updated
+type emailChangedEvent struct {
+	UserID   int    `json:"userId"`
+	OldEmail string `json:"oldEmail"`
+	NewEmail string `json:"newEmail"`
+	Reverted bool   `json:"reverted"`
+}
+
+type EmailChangeService struct {
+	userRepo          interfaces.UserRepository
+	changeRepo        interfaces.EmailChangeRepository
+	revocationService interfaces.TokenRevocationService
+	mailer            interfaces.Mailer
+	eventPublisher    interfaces.EventPublisher
+	confirmURL        string
+	revertURL         string
+}
+
+var _ interfaces.EmailChangeService = (*EmailChangeService)(nil)
+
+func NewEmailChangeService(userRepo interfaces.UserRepository, changeRepo interfaces.EmailChangeRepository, revocationService interfaces.TokenRevocationService, mailer interfaces.Mailer, eventPublisher interfaces.EventPublisher, confirmURL string, revertURL string) *EmailChangeService {
+	return &EmailChangeService{
+		userRepo:          userRepo,
+		changeRepo:        changeRepo,
+		revocationService: revocationService,
+		mailer:            mailer,
+		eventPublisher:    eventPublisher,
+		confirmURL:        confirmURL,
+		revertURL:         revertURL,
+	}
+}
+
+// Starts a pending change, the account keeps its current address until the new address confirms.
+// The current address is told about the change straight away and can revert it.
+func (service *EmailChangeService) RequestChange(userID int, changeRequest request.ChangeEmailRequest) error {
+	accountEntity := service.userRepo.GetByID(userID)
+	if accountEntity.ID == 0 {
+		return errors.NewUserNotFoundError(userID, 404)
+	}
+
+	newEmail := strings.TrimSpace(changeRequest.NewEmail)
+	if strings.EqualFold(newEmail, accountEntity.Email) || service.userRepo.GetByEmail(newEmail).ID != 0 {
+		return errors.NewEmailInUseError(409)
+	}
+
+	confirmToken, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	revertToken, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	// Only the newest request can be confirmed
+	service.changeRepo.CancelPendingForUser(userID)
+	service.changeRepo.Create(entities.EmailChangeRequestEntity{
+		UserID:           userID,
+		OldEmail:         accountEntity.Email,
+		NewEmail:         newEmail,
+		ConfirmTokenHash: authentication.HashOpaqueToken(confirmToken),
+		RevertTokenHash:  authentication.HashOpaqueToken(revertToken),
+		ExpiresAt:        time.Now().Add(emailChangeConfirmLifetime),
+		RevertExpiresAt:  time.Now().Add(emailChangeRevertLifetime),
+		CreatedAt:        time.Now(),
+	})
+
+	confirmBody := fmt.Sprintf(
+		"Hello %s,\n\nPlease confirm that you want to use this email address for your FlyHorizons account. The link expires in %d hours.\n\n%s\n\nIf you did not request this change, you can ignore this email.",
+		accountEntity.FullName,
+		int(emailChangeConfirmLifetime.Hours()),
+		linkWithToken(service.confirmURL, confirmToken),
+	)
+	if err := service.mailer.Send(newEmail, "Confirm your new FlyHorizons email address", confirmBody); err != nil {
+		return err
+	}
+
+	noticeBody := fmt.Sprintf(
+		"Hello %s,\n\nA request was made to change the email address of your FlyHorizons account to %s.\n\nIf you did not make this request, use the link below to keep this address and sign out everywhere. The link expires in %d days.\n\n%s",
+		accountEntity.FullName,
+		newEmail,
+		int(emailChangeRevertLifetime.Hours()/24),
+		linkWithToken(service.revertURL, revertToken),
+	)
+	if err := service.mailer.Send(accountEntity.Email, "Your FlyHorizons email address is being changed", noticeBody); err != nil {
+		return err
+	}
+
+	log.Printf(
+		"Email change requested:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+func (service *EmailChangeService) Confirm(token string) error {
+	change := service.changeRepo.GetByConfirmTokenHash(authentication.HashOpaqueToken(token))
+
+	// Change is unknown, already confirmed, reverted or expired
+	if change.ID == 0 || change.ConfirmedAt != nil || change.RevertedAt != nil || time.Now().After(change.ExpiresAt) {
+		return errors.NewInvalidEmailChangeTokenError(400)
+	}
+
+	// The address may have been registered by someone else since the request
+	if service.userRepo.GetByEmail(change.NewEmail).ID != 0 {
+		return errors.NewEmailInUseError(409)
+	}
+
+	// A change can only be confirmed once
+	if !service.changeRepo.MarkAsConfirmed(change.ID) {
+		return errors.NewInvalidEmailChangeTokenError(400)
+	}
+
+	if !service.userRepo.UpdateEmail(change.UserID, change.NewEmail) {
+		return errors.NewInvalidEmailChangeTokenError(400)
+	}
+
+	service.publish(emailChangedEvent{UserID: change.UserID, OldEmail: change.OldEmail, NewEmail: change.NewEmail})
+
+	log.Printf(
+		"Successfully changed email address:\n  User ID: %v\n  Timestamp: %s",
+		change.UserID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+// Cancels a pending change, or restores the old address of a confirmed change. A confirmed change
+// the owner did not ask for means the account was taken over, so every session is revoked as well.
+func (service *EmailChangeService) Revert(token string) error {
+	change := service.changeRepo.GetByRevertTokenHash(authentication.HashOpaqueToken(token))
+
+	// Change is unknown, already reverted or the revert period is over
+	if change.ID == 0 || change.RevertedAt != nil || time.Now().After(change.RevertExpiresAt) {
+		return errors.NewInvalidEmailChangeTokenError(400)
+	}
+
+	if change.ConfirmedAt != nil {
+		if owner := service.userRepo.GetByEmail(change.OldEmail); owner.ID != 0 && owner.ID != change.UserID {
+			return errors.NewEmailInUseError(409)
+		}
+	}
+
+	// A change can only be reverted once
+	if !service.changeRepo.MarkAsReverted(change.ID) {
+		return errors.NewInvalidEmailChangeTokenError(400)
+	}
+
+	if change.ConfirmedAt != nil {
+		if !service.userRepo.UpdateEmail(change.UserID, change.OldEmail) {
+			return errors.NewInvalidEmailChangeTokenError(400)
+		}
+		service.revocationService.RevokeAllSessions(change.UserID)
+		service.publish(emailChangedEvent{UserID: change.UserID, OldEmail: change.NewEmail, NewEmail: change.OldEmail, Reverted: true})
+	}
+
+	log.Printf(
+		"Successfully reverted email change:\n  User ID: %v\n  Timestamp: %s",
+		change.UserID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+func (service *EmailChangeService) publish(event emailChangedEvent) {
+	if err := service.eventPublisher.Publish("user_email_changed", event); err != nil {
+		log.Printf("An error occurred while posting the messaging to RabbitMQ %v\n", err)
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	emailVerificationTokenLifetime = 24 * time.Hour
+	// Resends are limited to one per minute and five per hour for each account
+	emailVerificationResendInterval = time.Minute
+	emailVerificationMaxPerHour     = 5
+)
+
+type EmailVerificationService struct {
+	userRepo         interfaces.UserRepository
+	verificationRepo interfaces.EmailVerificationRepository
+	mailer           interfaces.Mailer
+	verifyURL        string
+}
+
+var _ interfaces.EmailVerificationService = (*EmailVerificationService)(nil)
+
+func NewEmailVerificationService(userRepo interfaces.UserRepository, verificationRepo interfaces.EmailVerificationRepository, mailer interfaces.Mailer, verifyURL string) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		mailer:           mailer,
+		verifyURL:        verifyURL,
+	}
+}
+
+// Emails a new verification link, links sent earlier stop working
+func (service *EmailVerificationService) SendVerification(user models.User) error {
+	verificationToken, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	service.verificationRepo.InvalidateAllForUser(user.ID)
+	service.verificationRepo.Create(entities.EmailVerificationTokenEntity{
+		TokenHash: authentication.HashOpaqueToken(verificationToken),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(emailVerificationTokenLifetime),
+		CreatedAt: time.Now(),
+	})
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nPlease confirm your email address using the link below. The link expires in %d hours.\n\n%s\n\nIf you did not create a FlyHorizons account, you can ignore this email.",
+		user.FullName,
+		int(emailVerificationTokenLifetime.Hours()),
+		linkWithToken(service.verifyURL, verificationToken),
+	)
+	if err := service.mailer.Send(user.Email, "Verify your FlyHorizons email address", body); err != nil {
+		return err
+	}
+
+	log.Printf(
+		"Verification email sent:\n  User ID: %v\n  Timestamp: %s",
+		user.ID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+func (service *EmailVerificationService) Verify(token string) error {
+	verificationToken := service.verificationRepo.GetByTokenHash(authentication.HashOpaqueToken(token))
+
+	// Verification token is unknown, used or expired
+	if verificationToken.ID == 0 || verificationToken.UsedAt != nil || time.Now().After(verificationToken.ExpiresAt) {
+		return errors.NewInvalidEmailVerificationTokenError(400)
+	}
+
+	// A token can only be used once
+	if !service.verificationRepo.MarkAsUsed(verificationToken.ID) {
+		return errors.NewInvalidEmailVerificationTokenError(400)
+	}
+
+	if !service.userRepo.MarkEmailVerified(verificationToken.UserID) {
+		return errors.NewInvalidEmailVerificationTokenError(400)
+	}
+
+	log.Printf(
+		"Successfully verified email address:\n  User ID: %v\n  Timestamp: %s",
+		verificationToken.UserID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+// Sends a new link to an unverified account. Like the password reset, nothing is returned to the
+// caller, so unknown, verified and throttled addresses cannot be told apart.
+func (service *EmailVerificationService) Resend(resendRequest request.ResendVerificationRequest) {
+	accountEntity := service.userRepo.GetByEmail(resendRequest.Email)
+	if accountEntity.ID == 0 || accountEntity.EmailVerified {
+		return
+	}
+
+	if service.verificationRepo.CountCreatedSince(accountEntity.ID, time.Now().Add(-emailVerificationResendInterval)) > 0 ||
+		service.verificationRepo.CountCreatedSince(accountEntity.ID, time.Now().Add(-time.Hour)) >= emailVerificationMaxPerHour {
+		log.Printf(
+			"Verification email resend throttled:\n  User ID: %v\n  Timestamp: %s",
+			accountEntity.ID,
+			time.Now().Format(time.RFC3339),
+		)
+		return
+	}
+
+	user := models.User{ID: accountEntity.ID, FullName: accountEntity.FullName, Email: accountEntity.Email}
+	if err := service.SendVerification(user); err != nil {
+		log.Printf("An error occurred while sending the verification email: %v", err)
+	}
+}
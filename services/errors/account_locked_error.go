@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type AccountLockedError struct {
+	RetryAfter time.Duration
+	ErrorCode  int
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("Too many failed login attempts, please try again in %d seconds. Error Code: %d", e.RetryAfterSeconds(), e.ErrorCode)
+}
+
+// Rounded up, so a client that waits this long is no longer refused
+func (e *AccountLockedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+func NewAccountLockedError(retryAfter time.Duration, errorCode int) *AccountLockedError {
+	return &AccountLockedError{RetryAfter: retryAfter, ErrorCode: errorCode}
+}
@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+type ConcurrentModificationError struct {
+	ID        int
+	ErrorCode int
+}
+
+func (e *ConcurrentModificationError) Error() string {
+	return fmt.Sprintf("User with the ID %d was changed by another request, fetch it again and retry. [Error code: %d]", e.ID, e.ErrorCode)
+}
+
+func NewConcurrentModificationError(id int, errorCode int) *ConcurrentModificationError {
+	return &ConcurrentModificationError{ID: id, ErrorCode: errorCode}
+}
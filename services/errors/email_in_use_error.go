@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type EmailInUseError struct {
+	ErrorCode int
+}
+
+func (e *EmailInUseError) Error() string {
+	return fmt.Sprintf("This email address is already in use. Error Code: %d", e.ErrorCode)
+}
+
+func NewEmailInUseError(errorCode int) *EmailInUseError {
+	return &EmailInUseError{ErrorCode: errorCode}
+}
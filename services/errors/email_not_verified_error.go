@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type EmailNotVerifiedError struct {
+	ErrorCode int
+}
+
+func (e *EmailNotVerifiedError) Error() string {
+	return fmt.Sprintf("Please verify your email address before logging in. Error Code: %d", e.ErrorCode)
+}
+
+func NewEmailNotVerifiedError(errorCode int) *EmailNotVerifiedError {
+	return &EmailNotVerifiedError{ErrorCode: errorCode}
+}
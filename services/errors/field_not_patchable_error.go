@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+type FieldNotPatchableError struct {
+	Field     string
+	ErrorCode int
+}
+
+func (e *FieldNotPatchableError) Error() string {
+	return fmt.Sprintf("The field %s cannot be changed by this account. [Error code: %d]", e.Field, e.ErrorCode)
+}
+
+func NewFieldNotPatchableError(field string, errorCode int) *FieldNotPatchableError {
+	return &FieldNotPatchableError{Field: field, ErrorCode: errorCode}
+}
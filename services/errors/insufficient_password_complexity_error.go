@@ -1,15 +1,27 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type InsufficientPasswordComplexityError struct {
-	ErrorCode int
+	RequiredClasses []string
+	ErrorCode       int
 }
 
 func (e *InsufficientPasswordComplexityError) Error() string {
-	return fmt.Sprintf("The complexity given password is insufficient, it should consist of upper and lowercase letters, as well as a number and symbol. [Error code: %d]", e.ErrorCode)
+	return fmt.Sprintf("The complexity given password is insufficient, it should contain %s. [Error code: %d]", joinCharacterClasses(e.RequiredClasses), e.ErrorCode)
 }
 
-func NewInsufficientPasswordComplexityError(errorCode int) *InsufficientPasswordComplexityError {
-	return &InsufficientPasswordComplexityError{ErrorCode: errorCode}
+func NewInsufficientPasswordComplexityError(requiredClasses []string, errorCode int) *InsufficientPasswordComplexityError {
+	return &InsufficientPasswordComplexityError{RequiredClasses: requiredClasses, ErrorCode: errorCode}
+}
+
+// "a, b and c"
+func joinCharacterClasses(classes []string) string {
+	if len(classes) < 2 {
+		return strings.Join(classes, "")
+	}
+	return strings.Join(classes[:len(classes)-1], ", ") + " and " + classes[len(classes)-1]
 }
@@ -3,13 +3,14 @@ package errors
 import "fmt"
 
 type InsufficientPasswordLengthError struct {
+	MinLength int
 	ErrorCode int
 }
 
 func (e *InsufficientPasswordLengthError) Error() string {
-	return fmt.Sprintf("The length of the given password is insufficient, it should be at least 13 characters in length. [Error code: %d]", e.ErrorCode)
+	return fmt.Sprintf("The length of the given password is insufficient, it should be at least %d characters in length. [Error code: %d]", e.MinLength, e.ErrorCode)
 }
 
-func NewInsufficientPasswordLengthError(errorCode int) *InsufficientPasswordLengthError {
-	return &InsufficientPasswordLengthError{ErrorCode: errorCode}
+func NewInsufficientPasswordLengthError(minLength int, errorCode int) *InsufficientPasswordLengthError {
+	return &InsufficientPasswordLengthError{MinLength: minLength, ErrorCode: errorCode}
 }
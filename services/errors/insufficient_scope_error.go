@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+type InsufficientScopeError struct {
+	Scope     string
+	ErrorCode int
+}
+
+func (e *InsufficientScopeError) Error() string {
+	return fmt.Sprintf("The client is not allowed the scope %s. Error Code: %d", e.Scope, e.ErrorCode)
+}
+
+func NewInsufficientScopeError(scope string, errorCode int) *InsufficientScopeError {
+	return &InsufficientScopeError{Scope: scope, ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidClientError struct {
+	ErrorCode int
+}
+
+func (e *InvalidClientError) Error() string {
+	return fmt.Sprintf("The client credentials provided are invalid. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidClientError(errorCode int) *InvalidClientError {
+	return &InvalidClientError{ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidEmailChangeTokenError struct {
+	ErrorCode int
+}
+
+func (e *InvalidEmailChangeTokenError) Error() string {
+	return fmt.Sprintf("The email change link is invalid or has expired. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidEmailChangeTokenError(errorCode int) *InvalidEmailChangeTokenError {
+	return &InvalidEmailChangeTokenError{ErrorCode: errorCode}
+}
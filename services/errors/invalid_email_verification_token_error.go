@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidEmailVerificationTokenError struct {
+	ErrorCode int
+}
+
+func (e *InvalidEmailVerificationTokenError) Error() string {
+	return fmt.Sprintf("The verification link is invalid or has expired, please request a new one. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidEmailVerificationTokenError(errorCode int) *InvalidEmailVerificationTokenError {
+	return &InvalidEmailVerificationTokenError{ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidGrantError struct {
+	ErrorCode int
+}
+
+func (e *InvalidGrantError) Error() string {
+	return fmt.Sprintf("The authorization code is invalid, expired or was issued to another client. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidGrantError(errorCode int) *InvalidGrantError {
+	return &InvalidGrantError{ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidMFACodeError struct {
+	ErrorCode int
+}
+
+func (e *InvalidMFACodeError) Error() string {
+	return fmt.Sprintf("The MFA code provided is invalid. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidMFACodeError(errorCode int) *InvalidMFACodeError {
+	return &InvalidMFACodeError{ErrorCode: errorCode}
+}
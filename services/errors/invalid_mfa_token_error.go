@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidMFATokenError struct {
+	ErrorCode int
+}
+
+func (e *InvalidMFATokenError) Error() string {
+	return fmt.Sprintf("The MFA token is invalid or has expired, please log in again. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidMFATokenError(errorCode int) *InvalidMFATokenError {
+	return &InvalidMFATokenError{ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidPasskeyError struct {
+	ErrorCode int
+}
+
+func (e *InvalidPasskeyError) Error() string {
+	return fmt.Sprintf("The passkey could not be verified, please try again. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidPasskeyError(errorCode int) *InvalidPasskeyError {
+	return &InvalidPasskeyError{ErrorCode: errorCode}
+}
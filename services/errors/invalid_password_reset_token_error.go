@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidPasswordResetTokenError struct {
+	ErrorCode int
+}
+
+func (e *InvalidPasswordResetTokenError) Error() string {
+	return fmt.Sprintf("The password reset link is invalid or has expired, please request a new one. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidPasswordResetTokenError(errorCode int) *InvalidPasswordResetTokenError {
+	return &InvalidPasswordResetTokenError{ErrorCode: errorCode}
+}
@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+type InvalidPatchError struct {
+	Field     string
+	ErrorCode int
+}
+
+func (e *InvalidPatchError) Error() string {
+	return fmt.Sprintf("The patch contains an invalid value for %s. [Error code: %d]", e.Field, e.ErrorCode)
+}
+
+func NewInvalidPatchError(field string, errorCode int) *InvalidPatchError {
+	return &InvalidPatchError{Field: field, ErrorCode: errorCode}
+}
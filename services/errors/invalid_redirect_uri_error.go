@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidRedirectURIError struct {
+	ErrorCode int
+}
+
+func (e *InvalidRedirectURIError) Error() string {
+	return fmt.Sprintf("The redirect URI is not registered for this client. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidRedirectURIError(errorCode int) *InvalidRedirectURIError {
+	return &InvalidRedirectURIError{ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidRefreshTokenError struct {
+	ErrorCode int
+}
+
+func (e *InvalidRefreshTokenError) Error() string {
+	return fmt.Sprintf("The refresh token provided is invalid or has expired. Error Code: %d", e.ErrorCode)
+}
+
+func NewInvalidRefreshTokenError(errorCode int) *InvalidRefreshTokenError {
+	return &InvalidRefreshTokenError{ErrorCode: errorCode}
+}
@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+type InvalidScopeError struct {
+	Scope     string
+	ErrorCode int
+}
+
+func (e *InvalidScopeError) Error() string {
+	return fmt.Sprintf("The scope %s is not allowed for this client. Error Code: %d", e.Scope, e.ErrorCode)
+}
+
+func NewInvalidScopeError(scope string, errorCode int) *InvalidScopeError {
+	return &InvalidScopeError{Scope: scope, ErrorCode: errorCode}
+}
@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+type KeyRotationNotSupportedError struct {
+	Algorithm string
+	ErrorCode int
+}
+
+func (e *KeyRotationNotSupportedError) Error() string {
+	return fmt.Sprintf("Signing keys using %s cannot be generated by the service, downstream services verify them with a shared secret. [Error code: %d]", e.Algorithm, e.ErrorCode)
+}
+
+func NewKeyRotationNotSupportedError(algorithm string, errorCode int) *KeyRotationNotSupportedError {
+	return &KeyRotationNotSupportedError{Algorithm: algorithm, ErrorCode: errorCode}
+}
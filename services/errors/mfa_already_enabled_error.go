@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type MFAAlreadyEnabledError struct {
+	ErrorCode int
+}
+
+func (e *MFAAlreadyEnabledError) Error() string {
+	return fmt.Sprintf("Multi-factor authentication is already enabled for this account. Error Code: %d", e.ErrorCode)
+}
+
+func NewMFAAlreadyEnabledError(errorCode int) *MFAAlreadyEnabledError {
+	return &MFAAlreadyEnabledError{ErrorCode: errorCode}
+}
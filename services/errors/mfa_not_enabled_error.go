@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type MFANotEnabledError struct {
+	ErrorCode int
+}
+
+func (e *MFANotEnabledError) Error() string {
+	return fmt.Sprintf("Multi-factor authentication has not been set up for this account. Error Code: %d", e.ErrorCode)
+}
+
+func NewMFANotEnabledError(errorCode int) *MFANotEnabledError {
+	return &MFANotEnabledError{ErrorCode: errorCode}
+}
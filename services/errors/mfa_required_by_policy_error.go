@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type MFARequiredByPolicyError struct {
+	ErrorCode int
+}
+
+func (e *MFARequiredByPolicyError) Error() string {
+	return fmt.Sprintf("Multi-factor authentication is required for this account and cannot be disabled. Error Code: %d", e.ErrorCode)
+}
+
+func NewMFARequiredByPolicyError(errorCode int) *MFARequiredByPolicyError {
+	return &MFARequiredByPolicyError{ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type PasskeyNotFoundError struct {
+	ErrorCode int
+}
+
+func (e *PasskeyNotFoundError) Error() string {
+	return fmt.Sprintf("The passkey was not found. Error Code: %d", e.ErrorCode)
+}
+
+func NewPasskeyNotFoundError(errorCode int) *PasskeyNotFoundError {
+	return &PasskeyNotFoundError{ErrorCode: errorCode}
+}
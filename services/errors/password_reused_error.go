@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type PasswordReusedError struct {
+	ErrorCode int
+}
+
+func (e *PasswordReusedError) Error() string {
+	return fmt.Sprintf("The password has been used recently, choose a password you have not used before. [Error code: %d]", e.ErrorCode)
+}
+
+func NewPasswordReusedError(errorCode int) *PasswordReusedError {
+	return &PasswordReusedError{ErrorCode: errorCode}
+}
@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type RefreshTokenReusedError struct {
+	ErrorCode int
+}
+
+func (e *RefreshTokenReusedError) Error() string {
+	return fmt.Sprintf("The refresh token provided has already been used, all sessions derived from it have been revoked. Error Code: %d", e.ErrorCode)
+}
+
+func NewRefreshTokenReusedError(errorCode int) *RefreshTokenReusedError {
+	return &RefreshTokenReusedError{ErrorCode: errorCode}
+}
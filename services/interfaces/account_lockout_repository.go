@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"time"
+)
+
+type AccountLockoutRepository interface {
+	GetByUserID(userID int) entities.AccountLockoutEntity
+	IncrementFailedAttempts(userID int) entities.AccountLockoutEntity
+	Lock(userID int, lockedUntil time.Time)
+	Reset(userID int)
+}
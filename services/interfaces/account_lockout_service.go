@@ -0,0 +1,8 @@
+package interfaces
+
+type AccountLockoutService interface {
+	Check(userID int) error
+	RecordFailure(userID int)
+	RecordSuccess(userID int)
+	Unlock(userID int) error
+}
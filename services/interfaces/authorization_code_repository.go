@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type AuthorizationCodeRepository interface {
+	Create(entities.AuthorizationCodeEntity) entities.AuthorizationCodeEntity
+	GetByCodeHash(codeHash string) entities.AuthorizationCodeEntity
+	MarkAsUsed(id int, accessTokenID string) bool
+}
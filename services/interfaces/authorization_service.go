@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+)
+
+type AuthorizationService interface {
+	Authorize(authorizeRequest request.AuthorizeRequest) (string, error)
+	GrantConsent(consentRequest request.ConsentRequest, userID int) (string, error)
+	ExchangeAuthorizationCode(request.AuthorizationCodeRequest) (*response.OAuthTokenResponse, error)
+	UserInfo(userID int, scope string) (*response.UserInfoResponse, error)
+	Discovery() response.OpenIDConfigurationResponse
+}
@@ -0,0 +1,5 @@
+package interfaces
+
+type ClientAuthenticator interface {
+	Authenticate(clientID string, clientSecret string) bool
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type EmailChangeRepository interface {
+	Create(entities.EmailChangeRequestEntity) entities.EmailChangeRequestEntity
+	GetByConfirmTokenHash(tokenHash string) entities.EmailChangeRequestEntity
+	GetByRevertTokenHash(tokenHash string) entities.EmailChangeRequestEntity
+	MarkAsConfirmed(id int) bool
+	MarkAsReverted(id int) bool
+	CancelPendingForUser(userID int)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+)
+
+type EmailChangeService interface {
+	RequestChange(userID int, changeRequest request.ChangeEmailRequest) error
+	Confirm(token string) error
+	Revert(token string) error
+}
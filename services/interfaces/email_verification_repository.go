@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"time"
+)
+
+type EmailVerificationRepository interface {
+	Create(entities.EmailVerificationTokenEntity) entities.EmailVerificationTokenEntity
+	GetByTokenHash(tokenHash string) entities.EmailVerificationTokenEntity
+	MarkAsUsed(id int) bool
+	InvalidateAllForUser(userID int)
+	CountCreatedSince(userID int, since time.Time) int
+}
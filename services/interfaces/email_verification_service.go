@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/request"
+)
+
+type EmailVerificationService interface {
+	SendVerification(user models.User) error
+	Verify(token string) error
+	Resend(resendRequest request.ResendVerificationRequest)
+}
@@ -0,0 +1,5 @@
+package interfaces
+
+type EventPublisher interface {
+	Publish(queue string, event interface{}) error
+}
@@ -4,4 +4,5 @@ import "github.com/gin-gonic/gin"
 
 type GatewayAuthMiddleware interface {
 	GatewayAuthMiddleware() gin.HandlerFunc
+	DelegatedAuthMiddleware() gin.HandlerFunc
 }
@@ -0,0 +1,7 @@
+package interfaces
+
+import "flyhorizons-userservice/models/response"
+
+type IntrospectionService interface {
+	Introspect(token string) response.IntrospectionResponse
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "flyhorizons-userservice/models/response"
+
+type KeyRotator interface {
+	Rotate() (*response.SigningKeyResponse, error)
+	Keys() []response.SigningKeyResponse
+}
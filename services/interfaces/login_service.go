@@ -7,4 +7,7 @@ import (
 
 type LoginService interface {
 	Login(request.LoginRequest, string) (*response.LoginResponse, error)
+	Refresh(request.RefreshTokenRequest, string) (*response.LoginResponse, error)
+	VerifyMFA(request.MFALoginRequest, string) (*response.LoginResponse, error)
+	LoginWithPasskey(request.PasskeyLoginRequest, string) (*response.LoginResponse, error)
 }
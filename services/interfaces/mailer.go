@@ -0,0 +1,5 @@
+package interfaces
+
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
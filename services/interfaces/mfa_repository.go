@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"time"
+)
+
+type MFARepository interface {
+	GetFactor(userID int) entities.MFAFactorEntity
+	SaveFactor(entities.MFAFactorEntity)
+	DeleteFactor(userID int)
+	UseTimeStep(userID int, step int64) bool
+	CreateChallenge(entities.MFAChallengeEntity) entities.MFAChallengeEntity
+	GetChallengeByTokenHash(tokenHash string) entities.MFAChallengeEntity
+	IncrementChallengeAttempts(id int)
+	MarkChallengeAsUsed(id int) bool
+	CountChallengeFailures(userID int, since time.Time) int
+	RevokeChallenges(userID int)
+	ReplaceRecoveryCodes(userID int, recoveryCodes []entities.MFARecoveryCodeEntity)
+	UseRecoveryCode(userID int, codeHash string) bool
+	CountRecoveryCodes(userID int) int
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/response"
+)
+
+type MFAService interface {
+	Enroll(userID int) (*response.MFAEnrollmentResponse, error)
+	EnrollWithChallenge(mfaToken string) (*response.MFAEnrollmentResponse, error)
+	Activate(userID int, code string) error
+	Disable(userID int, code string) error
+	RegenerateRecoveryCodes(userID int, code string) (*response.MFARecoveryCodesResponse, error)
+	Status(userID int) response.MFAStatusResponse
+	BeginChallenge(account models.User) (*response.LoginResponse, error)
+	VerifyChallenge(mfaToken string, code string) (int, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type OAuthClientRepository interface {
+	GetByClientID(clientID string) entities.OAuthClientEntity
+	Create(entities.OAuthClientEntity) entities.OAuthClientEntity
+}
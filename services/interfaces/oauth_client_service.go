@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+)
+
+type OAuthClientService interface {
+	ClientAuthenticator
+	Register(request.RegisterClientRequest) (*response.RegisterClientResponse, error)
+	IssueClientCredentialsToken(clientID string, clientSecret string, scope string) (*response.OAuthTokenResponse, error)
+	AuthorizeIntrospection(clientID string, clientSecret string) error
+}
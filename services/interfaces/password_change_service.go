@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+)
+
+type PasswordChangeService interface {
+	ChangePassword(userID int, changeRequest request.ChangePasswordRequest) error
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+)
+
+type PasswordCheckService interface {
+	Check(checkRequest request.PasswordCheckRequest) response.PasswordCheckResponse
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+// A single hashing algorithm, the algorithm of a stored hash is recognized from its prefix
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Matches(encodedPassword string, rawPassword string) bool
+	Recognizes(encodedPassword string) bool
+	// Whether the hash was created with other parameters than the configured ones
+	NeedsRehash(encodedPassword string) bool
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type PasswordHistoryRepository interface {
+	Create(entities.PasswordHistoryEntity) entities.PasswordHistoryEntity
+	GetRecentByUserID(userID int, limit int) []entities.PasswordHistoryEntity
+	DeleteAllButRecent(userID int, keep int)
+}
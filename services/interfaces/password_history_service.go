@@ -0,0 +1,6 @@
+package interfaces
+
+type PasswordHistoryService interface {
+	CheckReuse(userID int, currentHash string, password string, depth int) error
+	Remember(userID int, replacedHash string, depth int)
+}
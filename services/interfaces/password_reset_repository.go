@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type PasswordResetRepository interface {
+	Create(entities.PasswordResetTokenEntity) entities.PasswordResetTokenEntity
+	GetByTokenHash(tokenHash string) entities.PasswordResetTokenEntity
+	MarkAsUsed(id int) bool
+	InvalidateAllForUser(userID int)
+}
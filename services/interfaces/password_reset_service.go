@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+)
+
+type PasswordResetService interface {
+	Forgot(forgotRequest request.ForgotPasswordRequest)
+	Reset(resetRequest request.ResetPasswordRequest) error
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "time"
+
+// Token buckets of the rate limiter, a shared store lets replicas enforce the limits together
+type RateLimitStore interface {
+	// Takes a token from the bucket of the key, which holds at most requests tokens and refills
+	// completely in per. Returns false and the time until the next token when the bucket is empty.
+	Take(key string, requests int, per time.Duration) (bool, time.Duration)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type RefreshTokenRepository interface {
+	Create(entities.RefreshTokenEntity) entities.RefreshTokenEntity
+	GetByTokenHash(tokenHash string) entities.RefreshTokenEntity
+	MarkAsUsed(id int) bool
+	RevokeFamily(familyID string)
+	RevokeAllForUser(userID int)
+	RevokeAllForUserExceptFamily(userID int, familyID string)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type SigningKeyRepository interface {
+	Create(entities.SigningKeyEntity) error
+	GetAll() []entities.SigningKeyEntity
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "flyhorizons-userservice/models/response"
+
+type TokenKeyProvider interface {
+	// Returns the algorithm and key used to verify tokens signed with the given key ID
+	VerificationKey(kid string) (string, interface{}, error)
+	JWKS() response.JSONWebKeySet
+}
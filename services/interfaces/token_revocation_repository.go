@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type TokenRevocationRepository interface {
+	RevokeToken(entities.RevokedTokenEntity)
+	IsTokenRevoked(jti string) bool
+	RevokeSessions(entities.SessionRevocationEntity)
+	GetSessionRevocation(userID int) entities.SessionRevocationEntity
+	DeleteExpired()
+}
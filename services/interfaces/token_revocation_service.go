@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+	"time"
+)
+
+type TokenRevocationService interface {
+	Logout(logoutRequest request.LogoutRequest, jti string, userID int, expiresAt time.Time)
+	RevokeAllSessions(userID int)
+	RevokeOtherSessions(userID int, refreshToken string)
+	IsRevoked(jti string, userID int, issuedAt time.Time) bool
+}
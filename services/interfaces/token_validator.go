@@ -0,0 +1,7 @@
+package interfaces
+
+import "github.com/golang-jwt/jwt/v4"
+
+type TokenValidator interface {
+	Validate(tokenString string) (jwt.MapClaims, error)
+}
@@ -9,7 +9,10 @@ type UserRepository interface {
 	GetByID(id int) entities.UserEntity
 	GetByEmail(email string) entities.UserEntity
 	Create(entities.UserEntity) entities.UserEntity
-	DeleteByID(id int) bool
-	Update(entities.UserEntity) entities.UserEntity
+	DeleteByID(id int, version int) bool
+	UpdateColumns(id int, version int, columns map[string]interface{}) bool
 	SaveLastLoginTime(id int)
+	UpdatePassword(id int, passwordHash string) bool
+	MarkEmailVerified(id int) bool
+	UpdateEmail(id int, email string) bool
 }
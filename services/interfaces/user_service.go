@@ -1,14 +1,17 @@
 package interfaces
 
 import (
-	"flyhorizons-userservice/models"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
 )
 
 type UserService interface {
-	GetAll() []models.User
-	GetByID(id int) (*models.User, error)
+	GetAll() []response.AdminUserResponse
+	GetByID(id int) (*response.UserProfileResponse, error)
 	UserExists(id int) bool
-	Create(user models.User) (*models.User, error)
-	DeleteByID(id int) (bool, error)
-	Update(user models.User) (*models.User, error)
+	Create(createRequest request.CreateUserRequest) (*response.UserProfileResponse, error)
+	DeleteByID(id int, version int) (bool, error)
+	Update(updateRequest request.UpdateUserRequest, version int) (*response.UserProfileResponse, error)
+	Patch(id int, changes map[string]json.RawMessage, role string, version int) (*response.UserProfileResponse, error)
 }
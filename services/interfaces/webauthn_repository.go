@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+)
+
+type WebAuthnRepository interface {
+	CreateCredential(entities.WebAuthnCredentialEntity) entities.WebAuthnCredentialEntity
+	GetCredentialByCredentialID(credentialID string) entities.WebAuthnCredentialEntity
+	GetCredentialsByUserID(userID int) []entities.WebAuthnCredentialEntity
+	UpdateSignCount(id int, previousSignCount int64, signCount int64) bool
+	DeleteCredential(userID int, credentialID string) bool
+	CreateChallenge(entities.WebAuthnChallengeEntity) entities.WebAuthnChallengeEntity
+	GetChallengeByHash(challengeHash string) entities.WebAuthnChallengeEntity
+	MarkChallengeAsUsed(id int) bool
+}
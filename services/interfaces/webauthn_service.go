@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+)
+
+type WebAuthnService interface {
+	BeginRegistration(userID int) (*response.PasskeyRegistrationOptionsResponse, error)
+	FinishRegistration(userID int, registrationRequest request.PasskeyRegistrationRequest) (*response.PasskeyResponse, error)
+	BeginLogin() (*response.PasskeyLoginOptionsResponse, error)
+	FinishLogin(loginRequest request.PasskeyLoginRequest) (int, error)
+	Credentials(userID int) []response.PasskeyResponse
+	DeleteCredential(userID int, credentialID string) error
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+	"strconv"
+	"time"
+)
+
+type IntrospectionService struct {
+	tokenValidator    interfaces.TokenValidator
+	revocationService interfaces.TokenRevocationService
+}
+
+var _ interfaces.IntrospectionService = (*IntrospectionService)(nil)
+
+func NewIntrospectionService(tokenValidator interfaces.TokenValidator, revocationService interfaces.TokenRevocationService) *IntrospectionService {
+	return &IntrospectionService{
+		tokenValidator:    tokenValidator,
+		revocationService: revocationService,
+	}
+}
+
+func (service *IntrospectionService) Introspect(token string) response.IntrospectionResponse {
+	// Invalid, expired or foreign tokens are only reported as inactive
+	claims, err := service.tokenValidator.Validate(token)
+	if err != nil {
+		return response.IntrospectionResponse{Active: false}
+	}
+
+	jti, _ := claims["jti"].(string)
+	sub, _ := claims["sub"].(float64)
+	iat, _ := claims["iat"].(float64)
+	if service.revocationService.IsRevoked(jti, int(sub), time.Unix(int64(iat), 0)) {
+		return response.IntrospectionResponse{Active: false, Revoked: true}
+	}
+
+	introspection := response.IntrospectionResponse{
+		Active:    true,
+		TokenType: "Bearer",
+		JTI:       jti,
+		IssuedAt:  int64(iat),
+	}
+	// User IDs decode as float64, which fmt would print in exponent notation for large IDs
+	switch sub := claims["sub"].(type) {
+	case float64:
+		introspection.Subject = strconv.FormatInt(int64(sub), 10)
+	case string:
+		introspection.Subject = sub
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		introspection.Expiry = int64(exp)
+	}
+	introspection.Role, _ = claims["role"].(string)
+	introspection.Scope, _ = claims["scope"].(string)
+	introspection.ClientID, _ = claims["client_id"].(string)
+	introspection.Issuer, _ = claims["iss"].(string)
+	introspection.Audience, _ = claims["aud"].(string)
+
+	return introspection
+}
@@ -5,15 +5,17 @@ import (
 	"flyhorizons-userservice/models/enums"
 	"flyhorizons-userservice/models/request"
 	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/services/authentication"
 	"flyhorizons-userservice/services/converter"
 	"flyhorizons-userservice/services/errors"
 	"flyhorizons-userservice/services/interfaces"
 	"log"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type OAuthTokenSigner struct {
@@ -30,17 +32,53 @@ func (signer *OAuthTokenSigner) SignToken(claims jwt.Claims) (string, error) {
 	return signer.jwtSigner.SignToken(claims)
 }
 
+const (
+	accessTokenLifetime  = 15 * time.Minute
+	refreshTokenLifetime = 30 * 24 * time.Hour
+)
+
+// What happens when an account that has not verified its email address logs in
+type EmailVerificationPolicy string
+
+const (
+	EmailVerificationAllow EmailVerificationPolicy = "allow" // Login as usual
+	EmailVerificationLimit EmailVerificationPolicy = "limit" // Only a short-lived access token, no refresh token
+	EmailVerificationBlock EmailVerificationPolicy = "block" // Login is refused until the address is verified
+)
+
+// Read from EMAIL_VERIFICATION_POLICY, unknown values fall back to limit
+func LoadEmailVerificationPolicyFromEnv() EmailVerificationPolicy {
+	switch policy := EmailVerificationPolicy(strings.ToLower(os.Getenv("EMAIL_VERIFICATION_POLICY"))); policy {
+	case EmailVerificationAllow, EmailVerificationBlock:
+		return policy
+	default:
+		return EmailVerificationLimit
+	}
+}
+
 type LoginService struct {
-	repo          interfaces.UserRepository
-	userConverter converter.UserConverter
-	tokenSigner   interfaces.TokenSigner
+	repo             interfaces.UserRepository
+	refreshTokenRepo interfaces.RefreshTokenRepository
+	accountHashing   *authentication.AccountHashing
+	mfaService       interfaces.MFAService
+	webAuthnService  interfaces.WebAuthnService
+	lockoutService   interfaces.AccountLockoutService
+	userConverter    converter.UserConverter
+	tokenSigner      interfaces.TokenSigner
+	verification     EmailVerificationPolicy
 }
 
-func NewLoginService(repo interfaces.UserRepository, userConverter converter.UserConverter, tokenSigner interfaces.TokenSigner) *LoginService {
+func NewLoginService(repo interfaces.UserRepository, refreshTokenRepo interfaces.RefreshTokenRepository, accountHashing *authentication.AccountHashing, mfaService interfaces.MFAService, webAuthnService interfaces.WebAuthnService, lockoutService interfaces.AccountLockoutService, userConverter converter.UserConverter, tokenSigner interfaces.TokenSigner, verificationPolicy EmailVerificationPolicy) *LoginService {
 	return &LoginService{
-		repo:          repo,
-		userConverter: userConverter,
-		tokenSigner:   tokenSigner,
+		repo:             repo,
+		refreshTokenRepo: refreshTokenRepo,
+		accountHashing:   accountHashing,
+		mfaService:       mfaService,
+		webAuthnService:  webAuthnService,
+		lockoutService:   lockoutService,
+		userConverter:    userConverter,
+		tokenSigner:      tokenSigner,
+		verification:     verificationPolicy,
 	}
 }
 
@@ -48,6 +86,19 @@ func (service *LoginService) Login(loginRequest request.LoginRequest, ip string)
 	accountEntity := service.repo.GetByEmail(loginRequest.Email)
 	account := service.userConverter.ConvertUserEntityToUser(accountEntity)
 
+	// Locked accounts are refused before the password is checked
+	if accountEntity.ID != 0 {
+		if err := service.lockoutService.Check(account.ID); err != nil {
+			log.Printf(
+				"Login attempt on locked account:\n  User ID: %v\n  Timestamp: %s\n  IP Address: %s",
+				account.ID,
+				time.Now().Format(time.RFC3339),
+				ip,
+			)
+			return nil, err
+		}
+	}
+
 	// Unsuccessful login attempt
 	if !service.matchesPassword(loginRequest.Password, account.Password) {
 		log.Printf(
@@ -56,16 +107,168 @@ func (service *LoginService) Login(loginRequest request.LoginRequest, ip string)
 			time.Now().Format(time.RFC3339),
 			ip,
 		)
+		if accountEntity.ID != 0 {
+			service.lockoutService.RecordFailure(account.ID)
+		}
 		return nil, errors.NewInvalidCredentialsError(400)
 	}
+	service.upgradePasswordHash(account.ID, loginRequest.Password, account.Password)
 
-	// Generate OAuth Token
-	accessToken, err := service.generateOAuthToken(account)
+	// Accounts protected by MFA receive a challenge instead of tokens
+	mfaChallenge, err := service.mfaService.BeginChallenge(account)
+	if err != nil {
+		return nil, err
+	}
+	if mfaChallenge != nil {
+		log.Printf(
+			"MFA challenge issued:\n  User ID: %v\n  Timestamp: %s\n  IP Address: %s",
+			account.ID,
+			time.Now().Format(time.RFC3339),
+			ip,
+		)
+		return mfaChallenge, nil
+	}
 
+	return service.completeLogin(account, []string{"pwd"}, ip)
+}
+
+// Second step of the login for accounts protected by MFA
+func (service *LoginService) VerifyMFA(mfaRequest request.MFALoginRequest, ip string) (*response.LoginResponse, error) {
+	userID, err := service.mfaService.VerifyChallenge(mfaRequest.MFAToken, mfaRequest.Code)
 	if err != nil {
+		log.Printf(
+			"Unsuccessful MFA attempt:\n  Timestamp: %s\n  IP Address: %s",
+			time.Now().Format(time.RFC3339),
+			ip,
+		)
+		// Wrong authenticator and recovery codes count as failed logins of the user of the challenge
+		if _, ok := err.(*errors.InvalidMFACodeError); ok && userID != 0 {
+			service.lockoutService.RecordFailure(userID)
+		}
 		return nil, err
 	}
 
+	accountEntity := service.repo.GetByID(userID)
+	if accountEntity.ID == 0 {
+		return nil, errors.NewInvalidMFATokenError(401)
+	}
+	account := service.userConverter.ConvertUserEntityToUser(accountEntity)
+
+	return service.completeLogin(account, []string{"pwd", "otp"}, ip)
+}
+
+// Passwordless login, a passkey with user verification counts as multi-factor so no MFA challenge follows
+func (service *LoginService) LoginWithPasskey(passkeyRequest request.PasskeyLoginRequest, ip string) (*response.LoginResponse, error) {
+	userID, err := service.webAuthnService.FinishLogin(passkeyRequest)
+	if err != nil {
+		log.Printf(
+			"Unsuccessful passkey login attempt:\n  Timestamp: %s\n  IP Address: %s",
+			time.Now().Format(time.RFC3339),
+			ip,
+		)
+		return nil, err
+	}
+
+	accountEntity := service.repo.GetByID(userID)
+	if accountEntity.ID == 0 {
+		return nil, errors.NewInvalidPasskeyError(401)
+	}
+	account := service.userConverter.ConvertUserEntityToUser(accountEntity)
+
+	return service.completeLogin(account, []string{"hwk", "user"}, ip)
+}
+
+func (service *LoginService) Refresh(refreshRequest request.RefreshTokenRequest, ip string) (*response.LoginResponse, error) {
+	refreshToken := service.refreshTokenRepo.GetByTokenHash(authentication.HashOpaqueToken(refreshRequest.RefreshToken))
+
+	// Refresh token is unknown, revoked or expired
+	if refreshToken.ID == 0 || refreshToken.RevokedAt != nil || time.Now().After(refreshToken.ExpiresAt) {
+		return nil, errors.NewInvalidRefreshTokenError(401)
+	}
+
+	// A refresh token that was already rotated is being replayed, so the whole family is considered compromised
+	if refreshToken.UsedAt != nil || !service.refreshTokenRepo.MarkAsUsed(refreshToken.ID) {
+		service.refreshTokenRepo.RevokeFamily(refreshToken.FamilyID)
+		log.Printf(
+			"Refresh token reuse detected, token family revoked:\n  User ID: %v\n  Timestamp: %s\n  IP Address: %s",
+			refreshToken.UserID,
+			time.Now().Format(time.RFC3339),
+			ip,
+		)
+		return nil, errors.NewRefreshTokenReusedError(401)
+	}
+
+	accountEntity := service.repo.GetByID(refreshToken.UserID)
+	if accountEntity.ID == 0 {
+		service.refreshTokenRepo.RevokeFamily(refreshToken.FamilyID)
+		return nil, errors.NewInvalidRefreshTokenError(401)
+	}
+	account := service.userConverter.ConvertUserEntityToUser(accountEntity)
+
+	// Unverified accounts only get refresh tokens when the policy allows it
+	if !account.EmailVerified && service.verification != EmailVerificationAllow {
+		service.refreshTokenRepo.RevokeFamily(refreshToken.FamilyID)
+		return nil, errors.NewEmailNotVerifiedError(403)
+	}
+
+	// Refreshed tokens keep the authentication methods of the original login
+	return service.issueTokens(account, refreshToken.FamilyID, strings.Fields(refreshToken.AuthMethods))
+}
+
+// Every login ends here once all factors are verified. A lock that started while the second factor was
+// pending refuses the login, otherwise the failed attempts of the account are reset.
+func (service *LoginService) completeLogin(account models.User, authMethods []string, ip string) (*response.LoginResponse, error) {
+	if err := service.lockoutService.Check(account.ID); err != nil {
+		log.Printf(
+			"Login attempt on locked account:\n  User ID: %v\n  Timestamp: %s\n  IP Address: %s",
+			account.ID,
+			time.Now().Format(time.RFC3339),
+			ip,
+		)
+		return nil, err
+	}
+	service.lockoutService.RecordSuccess(account.ID)
+
+	var loginResponse *response.LoginResponse
+
+	switch {
+	case account.EmailVerified || service.verification == EmailVerificationAllow:
+		// Every login starts a new refresh token family
+		familyID, err := authentication.GenerateOpaqueToken()
+		if err != nil {
+			return nil, err
+		}
+
+		loginResponse, err = service.issueTokens(account, familyID, authMethods)
+		if err != nil {
+			return nil, err
+		}
+	case service.verification == EmailVerificationBlock:
+		log.Printf(
+			"Login refused, email address not verified:\n  User ID: %v\n  Timestamp: %s\n  IP Address: %s",
+			account.ID,
+			time.Now().Format(time.RFC3339),
+			ip,
+		)
+		return nil, errors.NewEmailNotVerifiedError(403)
+	default:
+		// Limited session, the user has to log in again once the access token expires
+		accessToken, err := service.generateOAuthToken(account, authMethods)
+		if err != nil {
+			return nil, err
+		}
+
+		loginResponse = &response.LoginResponse{
+			AccessToken:               accessToken,
+			TokenType:                 "Bearer",
+			ExpiresIn:                 int(accessTokenLifetime.Seconds()),
+			EmailVerificationRequired: true,
+		}
+	}
+
+	// Save last login time
+	service.repo.SaveLastLoginTime(account.ID)
+
 	// Successful login attempt
 	log.Printf(
 		"Successful login attempt:\n  User ID: %v\n  Timestamp: %s\n  IP Address: %s",
@@ -74,15 +277,89 @@ func (service *LoginService) Login(loginRequest request.LoginRequest, ip string)
 		ip,
 	)
 
-	return &response.LoginResponse{AccessToken: accessToken}, nil
+	return loginResponse, nil
 }
 
 func (service *LoginService) matchesPassword(rawPassword, encodedPassword string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(encodedPassword), []byte(rawPassword))
-	return err == nil
+	return service.accountHashing.ComparePassword(encodedPassword, rawPassword)
+}
+
+// The raw password is only known during login, so outdated hashes are replaced right after it matched
+func (service *LoginService) upgradePasswordHash(userID int, rawPassword string, encodedPassword string) {
+	if !service.accountHashing.NeedsRehash(encodedPassword) {
+		return
+	}
+
+	hashedPassword, err := service.accountHashing.HashPassword(rawPassword)
+	if err != nil || !service.repo.UpdatePassword(userID, hashedPassword) {
+		log.Printf(
+			"Failed to upgrade password hash:\n  User ID: %v\n  Timestamp: %s",
+			userID,
+			time.Now().Format(time.RFC3339),
+		)
+		return
+	}
+
+	log.Printf(
+		"Password hash upgraded:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+func (service *LoginService) generateOAuthToken(account models.User, authMethods []string) (string, error) {
+	claims, err := newAccessTokenClaims(account)
+	if err != nil {
+		return "", err
+	}
+	if len(authMethods) > 0 {
+		claims["amr"] = authMethods // Authentication methods (RFC 8176)
+	}
+
+	return service.tokenSigner.SignToken(claims)
 }
 
-func (service *LoginService) generateOAuthToken(account models.User) (string, error) {
+func (service *LoginService) issueTokens(account models.User, familyID string, authMethods []string) (*response.LoginResponse, error) {
+	// Generate OAuth Token
+	accessToken, err := service.generateOAuthToken(account, authMethods)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := service.generateRefreshToken(account.ID, familyID, authMethods)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.LoginResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenLifetime.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (service *LoginService) generateRefreshToken(userID int, familyID string, authMethods []string) (string, error) {
+	refreshToken, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	// Only the hash is stored, the raw token is handed to the client once
+	service.refreshTokenRepo.Create(entities.RefreshTokenEntity{
+		UserID:      userID,
+		FamilyID:    familyID,
+		TokenHash:   authentication.HashOpaqueToken(refreshToken),
+		AuthMethods: strings.Join(authMethods, " "),
+		ExpiresAt:   time.Now().Add(refreshTokenLifetime),
+		CreatedAt:   time.Now(),
+	})
+
+	return refreshToken, nil
+}
+
+// Builds the access token claims of a user account, shared by every flow that signs in a user
+func newAccessTokenClaims(account models.User) (jwt.MapClaims, error) {
 	var role = ""
 
 	if account.AccountType == enums.User {
@@ -90,23 +367,28 @@ func (service *LoginService) generateOAuthToken(account models.User) (string, er
 	} else if account.AccountType == enums.Admin {
 		role = "admin"
 	} else {
-		return "", errors.NewInvalidAccountTypeError(401)
+		return nil, errors.NewInvalidAccountTypeError(401)
+	}
+
+	// Unique token ID, used to revoke this token before it expires
+	jti, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
 	}
 
 	// OAuth compliant claims
 	claims := jwt.MapClaims{
-		"sub":        account.ID,                            // Subject (user ID)
-		"email":      account.Email,                         // User email
-		"account_id": account.ID,                            // User ID (kept for not crashing the frontend)
-		"role":       role,                                  // User role
-		"iss":        "flyhorizons-user-service",            // Issuer
-		"aud":        "flyhorizons-api",                     // Audience
-		"iat":        time.Now().Unix(),                     // Issued at
-		"exp":        time.Now().Add(72 * time.Hour).Unix(), // Expiration
+		"jti":            jti,                                        // Token ID
+		"sub":            account.ID,                                 // Subject (user ID)
+		"email":          account.Email,                              // User email
+		"email_verified": account.EmailVerified,                      // Whether the email address is verified
+		"account_id":     account.ID,                                 // User ID (kept for not crashing the frontend)
+		"role":           role,                                       // User role
+		"iss":            "flyhorizons-user-service",                 // Issuer
+		"aud":            "flyhorizons-api",                          // Audience
+		"iat":            time.Now().Unix(),                          // Issued at
+		"exp":            time.Now().Add(accessTokenLifetime).Unix(), // Expiration
 	}
 
-	// Save last login time
-	service.repo.SaveLastLoginTime(account.ID)
-
-	return service.tokenSigner.SignToken(claims)
+	return claims, nil
 }
@@ -0,0 +1,27 @@
+package mailing
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+	"log"
+	"time"
+)
+
+// Writes emails to the log instead of sending them, only meant for local development
+type LogMailer struct{}
+
+var _ interfaces.Mailer = (*LogMailer)(nil)
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (mailer *LogMailer) Send(to string, subject string, body string) error {
+	log.Printf(
+		"Email not sent, logged instead:\n  To: %s\n  Subject: %s\n  Body: %s\n  Timestamp: %s",
+		to,
+		subject,
+		body,
+		time.Now().Format(time.RFC3339),
+	)
+	return nil
+}
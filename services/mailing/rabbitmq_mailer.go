@@ -0,0 +1,44 @@
+package mailing
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/config"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Hands emails to the notification service through the send_email queue
+type RabbitMQMailer struct{}
+
+var _ interfaces.Mailer = (*RabbitMQMailer)(nil)
+
+func NewRabbitMQMailer() *RabbitMQMailer {
+	return &RabbitMQMailer{}
+}
+
+func (mailer *RabbitMQMailer) Send(to string, subject string, body string) error {
+	message, err := json.Marshal(struct {
+		To      string `json:"to"`
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+	}{
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return config.RabbitMQClient.Channel.Publish(
+		"",
+		"send_email",
+		false,
+		false,
+		amqp091.Publishing{
+			ContentType: "application/json",
+			Body:        message,
+		},
+	)
+}
@@ -0,0 +1,36 @@
+package messaging
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/config"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Publishes events as JSON to a RabbitMQ queue, the queues are declared in config.InitializeRabbitMQ
+type RabbitMQEventPublisher struct{}
+
+var _ interfaces.EventPublisher = (*RabbitMQEventPublisher)(nil)
+
+func NewRabbitMQEventPublisher() *RabbitMQEventPublisher {
+	return &RabbitMQEventPublisher{}
+}
+
+func (publisher *RabbitMQEventPublisher) Publish(queue string, event interface{}) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return config.RabbitMQClient.Channel.Publish(
+		"",
+		queue,
+		false,
+		false,
+		amqp091.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+}
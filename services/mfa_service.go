@@ -0,0 +1,360 @@
+package services
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	mfaChallengeLifetime    = 5 * time.Minute
+	mfaChallengeMaxAttempts = 5
+	mfaUserMaxFailures      = 10 // Failed codes across every challenge of a user within mfaUserFailureWindow
+	mfaUserFailureWindow    = 15 * time.Minute
+	recoveryCodeCount       = 10
+	totpIssuer              = "FlyHorizons"
+)
+
+// Account types that cannot sign in without MFA, accounts without a factor have to enrol during login
+type MFAPolicy struct {
+	RequiredAccountTypes []enums.AccountType
+}
+
+// Admins are forced into MFA when MFA_REQUIRED_FOR_ADMINS is set to true
+func LoadMFAPolicyFromEnv() MFAPolicy {
+	policy := MFAPolicy{}
+	if required, _ := strconv.ParseBool(os.Getenv("MFA_REQUIRED_FOR_ADMINS")); required {
+		policy.RequiredAccountTypes = append(policy.RequiredAccountTypes, enums.Admin)
+	}
+	return policy
+}
+
+func (policy MFAPolicy) IsRequired(accountType enums.AccountType) bool {
+	return slices.Contains(policy.RequiredAccountTypes, accountType)
+}
+
+type MFAService struct {
+	mfaRepo  interfaces.MFARepository
+	userRepo interfaces.UserRepository
+	policy   MFAPolicy
+}
+
+var _ interfaces.MFAService = (*MFAService)(nil)
+
+func NewMFAService(mfaRepo interfaces.MFARepository, userRepo interfaces.UserRepository, policy MFAPolicy) *MFAService {
+	return &MFAService{
+		mfaRepo:  mfaRepo,
+		userRepo: userRepo,
+		policy:   policy,
+	}
+}
+
+// Generates a new secret and recovery codes, enrolling again replaces a secret that was never activated
+func (service *MFAService) Enroll(userID int) (*response.MFAEnrollmentResponse, error) {
+	accountEntity := service.userRepo.GetByID(userID)
+	if accountEntity.ID == 0 {
+		return nil, errors.NewUserNotFoundError(userID, 404)
+	}
+
+	if service.mfaRepo.GetFactor(userID).Enabled {
+		return nil, errors.NewMFAAlreadyEnabledError(409)
+	}
+
+	secret, err := authentication.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := service.generateRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	service.mfaRepo.SaveFactor(entities.MFAFactorEntity{
+		UserID:    userID,
+		Secret:    secret,
+		Enabled:   false,
+		CreatedAt: time.Now(),
+	})
+
+	return &response.MFAEnrollmentResponse{
+		Secret:        secret,
+		OTPAuthURI:    authentication.TOTPURI(totpIssuer, accountEntity.Email, secret),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// Lets accounts that are forced into MFA enrol with the MFA token of their login attempt
+func (service *MFAService) EnrollWithChallenge(mfaToken string) (*response.MFAEnrollmentResponse, error) {
+	challenge, err := service.activeChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Enroll(challenge.UserID)
+}
+
+func (service *MFAService) Activate(userID int, code string) error {
+	factor := service.mfaRepo.GetFactor(userID)
+	if factor.UserID == 0 {
+		return errors.NewMFANotEnabledError(400)
+	}
+	if factor.Enabled {
+		return errors.NewMFAAlreadyEnabledError(409)
+	}
+
+	if !service.verifyCode(&factor, code) {
+		return errors.NewInvalidMFACodeError(400)
+	}
+
+	service.enable(factor)
+	return nil
+}
+
+func (service *MFAService) Disable(userID int, code string) error {
+	factor := service.mfaRepo.GetFactor(userID)
+	if !factor.Enabled {
+		return errors.NewMFANotEnabledError(400)
+	}
+
+	accountEntity := service.userRepo.GetByID(userID)
+	if service.policy.IsRequired(enums.AccountTypeFromInt(accountEntity.AccountType)) {
+		return errors.NewMFARequiredByPolicyError(403)
+	}
+
+	if !service.verifyCode(&factor, code) {
+		return errors.NewInvalidMFACodeError(400)
+	}
+
+	service.mfaRepo.DeleteFactor(userID)
+
+	log.Printf(
+		"Successfully disabled MFA:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+// Replaces every recovery code of the user, the authenticator code confirms the user still holds the factor
+func (service *MFAService) RegenerateRecoveryCodes(userID int, code string) (*response.MFARecoveryCodesResponse, error) {
+	factor := service.mfaRepo.GetFactor(userID)
+	if !factor.Enabled {
+		return nil, errors.NewMFANotEnabledError(400)
+	}
+
+	if !service.verifyCode(&factor, code) {
+		return nil, errors.NewInvalidMFACodeError(400)
+	}
+
+	recoveryCodes, err := service.generateRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf(
+		"Successfully regenerated MFA recovery codes:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return &response.MFARecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (service *MFAService) Status(userID int) response.MFAStatusResponse {
+	accountEntity := service.userRepo.GetByID(userID)
+	factor := service.mfaRepo.GetFactor(userID)
+
+	status := response.MFAStatusResponse{
+		Enabled:  factor.Enabled,
+		Required: service.policy.IsRequired(enums.AccountTypeFromInt(accountEntity.AccountType)),
+	}
+	if factor.Enabled {
+		status.RecoveryCodesRemaining = service.mfaRepo.CountRecoveryCodes(userID)
+	}
+
+	return status
+}
+
+// Returns an MFA challenge when the account needs a second factor, nil when the password is sufficient
+func (service *MFAService) BeginChallenge(account models.User) (*response.LoginResponse, error) {
+	factor := service.mfaRepo.GetFactor(account.ID)
+	if !factor.Enabled && !service.policy.IsRequired(account.AccountType) {
+		return nil, nil
+	}
+
+	// Every challenge needs the password, but whoever knows it could otherwise keep starting new challenges
+	if service.mfaRepo.CountChallengeFailures(account.ID, time.Now().Add(-mfaUserFailureWindow)) >= mfaUserMaxFailures {
+		return nil, errors.NewAccountLockedError(mfaUserFailureWindow, 423)
+	}
+
+	mfaToken, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the hash is stored, the raw token is handed to the client once
+	service.mfaRepo.CreateChallenge(entities.MFAChallengeEntity{
+		TokenHash: authentication.HashOpaqueToken(mfaToken),
+		UserID:    account.ID,
+		ExpiresAt: time.Now().Add(mfaChallengeLifetime),
+		CreatedAt: time.Now(),
+	})
+
+	return &response.LoginResponse{
+		MFARequired:           true,
+		MFAEnrollmentRequired: !factor.Enabled,
+		MFAToken:              mfaToken,
+	}, nil
+}
+
+// Returns the user of the challenge once an authenticator code or a recovery code is verified, both count
+// as a one-time password. A wrong code also returns the user, so the failure counts towards the lockout.
+// Accounts that were forced to enrol during login activate their factor with the first valid authenticator code.
+func (service *MFAService) VerifyChallenge(mfaToken string, code string) (int, error) {
+	challenge, err := service.activeChallenge(mfaToken)
+	if err != nil {
+		return 0, err
+	}
+
+	factor := service.mfaRepo.GetFactor(challenge.UserID)
+	if factor.UserID == 0 {
+		return 0, errors.NewMFANotEnabledError(401)
+	}
+
+	verified := false
+	if isTOTPCode(code) {
+		verified = service.verifyCode(&factor, code)
+	} else if factor.Enabled {
+		// Recovery codes only replace the authenticator once the factor is active
+		verified = service.useRecoveryCode(challenge.UserID, code)
+	}
+
+	if !verified {
+		service.mfaRepo.IncrementChallengeAttempts(challenge.ID)
+		service.limitChallengeFailures(challenge.UserID)
+		return challenge.UserID, errors.NewInvalidMFACodeError(401)
+	}
+
+	// A challenge can only be exchanged once
+	if !service.mfaRepo.MarkChallengeAsUsed(challenge.ID) {
+		return 0, errors.NewInvalidMFATokenError(401)
+	}
+
+	if !factor.Enabled {
+		service.enable(factor)
+	}
+
+	return challenge.UserID, nil
+}
+
+// Ends every open challenge of the user once the failures of all challenges reach the limit,
+// BeginChallenge refuses new ones until the failures leave the window
+func (service *MFAService) limitChallengeFailures(userID int) {
+	if service.mfaRepo.CountChallengeFailures(userID, time.Now().Add(-mfaUserFailureWindow)) < mfaUserMaxFailures {
+		return
+	}
+
+	service.mfaRepo.RevokeChallenges(userID)
+	log.Printf(
+		"MFA challenges revoked after too many failed codes:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+func (service *MFAService) activeChallenge(mfaToken string) (entities.MFAChallengeEntity, error) {
+	challenge := service.mfaRepo.GetChallengeByTokenHash(authentication.HashOpaqueToken(mfaToken))
+
+	// Challenge is unknown, used, expired or was guessed at too often
+	if challenge.ID == 0 || challenge.UsedAt != nil || time.Now().After(challenge.ExpiresAt) || challenge.Attempts >= mfaChallengeMaxAttempts {
+		return entities.MFAChallengeEntity{}, errors.NewInvalidMFATokenError(401)
+	}
+
+	return challenge, nil
+}
+
+// Accepts every code only once, the time step of the code is stored on success
+func (service *MFAService) verifyCode(factor *entities.MFAFactorEntity, code string) bool {
+	step, ok := authentication.ValidateTOTP(factor.Secret, code, time.Now())
+	if !ok || step <= factor.LastUsedStep || !service.mfaRepo.UseTimeStep(factor.UserID, step) {
+		return false
+	}
+
+	factor.LastUsedStep = step
+	return true
+}
+
+func (service *MFAService) enable(factor entities.MFAFactorEntity) {
+	enabledAt := time.Now()
+	factor.Enabled = true
+	factor.EnabledAt = &enabledAt
+	service.mfaRepo.SaveFactor(factor)
+
+	log.Printf(
+		"Successfully enabled MFA:\n  User ID: %v\n  Timestamp: %s",
+		factor.UserID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+// Only the hashes of the recovery codes are stored, the codes are shown to the user once
+func (service *MFAService) generateRecoveryCodes(userID int) ([]string, error) {
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	recoveryCodeEntities := make([]entities.MFARecoveryCodeEntity, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		recoveryCode, err := authentication.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		recoveryCodeEntities = append(recoveryCodeEntities, entities.MFARecoveryCodeEntity{
+			UserID:    userID,
+			CodeHash:  authentication.HashRecoveryCode(recoveryCode),
+			CreatedAt: time.Now(),
+		})
+	}
+
+	service.mfaRepo.ReplaceRecoveryCodes(userID, recoveryCodeEntities)
+	return recoveryCodes, nil
+}
+
+func (service *MFAService) useRecoveryCode(userID int, recoveryCode string) bool {
+	if !service.mfaRepo.UseRecoveryCode(userID, authentication.HashRecoveryCode(recoveryCode)) {
+		return false
+	}
+
+	log.Printf(
+		"MFA recovery code used:\n  User ID: %v\n  Remaining Recovery Codes: %d\n  Timestamp: %s",
+		userID,
+		service.mfaRepo.CountRecoveryCodes(userID),
+		time.Now().Format(time.RFC3339),
+	)
+
+	return true
+}
+
+// Authenticator codes are six digits, anything else is treated as a recovery code
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, character := range code {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Scope a client needs to introspect tokens, partner apps of the authorization code flow are never given it
+const introspectionScope = "introspect"
+
+type OAuthClientService struct {
+	clientRepo     interfaces.OAuthClientRepository
+	accountHashing *authentication.AccountHashing
+	tokenSigner    interfaces.TokenSigner
+}
+
+var _ interfaces.OAuthClientService = (*OAuthClientService)(nil)
+
+func NewOAuthClientService(clientRepo interfaces.OAuthClientRepository, accountHashing *authentication.AccountHashing, tokenSigner interfaces.TokenSigner) *OAuthClientService {
+	return &OAuthClientService{
+		clientRepo:     clientRepo,
+		accountHashing: accountHashing,
+		tokenSigner:    tokenSigner,
+	}
+}
+
+func (service *OAuthClientService) Register(registerRequest request.RegisterClientRequest) (*response.RegisterClientResponse, error) {
+	clientID, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	clientID = clientID[:16]
+
+	// Public clients cannot keep a secret, so they never receive one
+	clientSecret, secretHash := "", ""
+	if !registerRequest.Public {
+		clientSecret, err = authentication.GenerateOpaqueToken()
+		if err != nil {
+			return nil, err
+		}
+
+		// The secret is stored like a password
+		secretHash, err = service.accountHashing.HashPassword(clientSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	client := service.clientRepo.Create(entities.OAuthClientEntity{
+		ClientID:     clientID,
+		Name:         registerRequest.Name,
+		SecretHash:   secretHash,
+		Scopes:       strings.Join(registerRequest.Scopes, " "),
+		RedirectURIs: strings.Join(registerRequest.RedirectURIs, " "),
+		Public:       registerRequest.Public,
+		CreatedAt:    time.Now(),
+	})
+
+	log.Printf(
+		"Successfully registered OAuth client:\n  Client ID: %s\n  Name: %s\n  Timestamp: %s",
+		client.ClientID,
+		client.Name,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return &response.RegisterClientResponse{
+		ClientID:     client.ClientID,
+		ClientSecret: clientSecret,
+		Name:         client.Name,
+		Scopes:       strings.Fields(client.Scopes),
+		RedirectURIs: strings.Fields(client.RedirectURIs),
+		Public:       client.Public,
+	}, nil
+}
+
+func (service *OAuthClientService) Authenticate(clientID string, clientSecret string) bool {
+	_, ok := service.authenticateClient(clientID, clientSecret)
+	return ok
+}
+
+// Only authenticated clients that were registered with the introspect scope can read the claims of other tokens
+func (service *OAuthClientService) AuthorizeIntrospection(clientID string, clientSecret string) error {
+	client, ok := service.authenticateClient(clientID, clientSecret)
+	if !ok {
+		return errors.NewInvalidClientError(401)
+	}
+
+	if !slices.Contains(strings.Fields(client.Scopes), introspectionScope) {
+		log.Printf(
+			"Introspection refused, client is missing the %s scope:\n  Client ID: %s\n  Timestamp: %s",
+			introspectionScope,
+			clientID,
+			time.Now().Format(time.RFC3339),
+		)
+		return errors.NewInsufficientScopeError(introspectionScope, 403)
+	}
+
+	return nil
+}
+
+func (service *OAuthClientService) IssueClientCredentialsToken(clientID string, clientSecret string, scope string) (*response.OAuthTokenResponse, error) {
+	client, ok := service.authenticateClient(clientID, clientSecret)
+	if !ok {
+		log.Printf(
+			"Unsuccessful client authentication:\n  Client ID: %s\n  Timestamp: %s",
+			clientID,
+			time.Now().Format(time.RFC3339),
+		)
+		return nil, errors.NewInvalidClientError(401)
+	}
+
+	// Without a requested scope the client receives every scope it is allowed
+	allowedScopes := strings.Fields(client.Scopes)
+	grantedScopes := allowedScopes
+	if scope != "" {
+		grantedScopes = strings.Fields(scope)
+		for _, requestedScope := range grantedScopes {
+			if !slices.Contains(allowedScopes, requestedScope) {
+				return nil, errors.NewInvalidScopeError(requestedScope, 400)
+			}
+		}
+	}
+	grantedScope := strings.Join(grantedScopes, " ")
+
+	jti, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// The subject identifies the client instead of a user account
+	claims := jwt.MapClaims{
+		"jti":       jti,                                        // Token ID
+		"sub":       client.ClientID,                            // Subject (client ID)
+		"client_id": client.ClientID,                            // Client ID
+		"role":      "service",                                  // Machine identity
+		"scope":     grantedScope,                               // Granted scopes
+		"iss":       "flyhorizons-user-service",                 // Issuer
+		"aud":       "flyhorizons-api",                          // Audience
+		"iat":       time.Now().Unix(),                          // Issued at
+		"exp":       time.Now().Add(accessTokenLifetime).Unix(), // Expiration
+	}
+
+	accessToken, err := service.tokenSigner.SignToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(accessTokenLifetime.Seconds()),
+		Scope:       grantedScope,
+	}, nil
+}
+
+func (service *OAuthClientService) authenticateClient(clientID string, clientSecret string) (entities.OAuthClientEntity, bool) {
+	if clientID == "" || clientSecret == "" {
+		return entities.OAuthClientEntity{}, false
+	}
+
+	client := service.clientRepo.GetByClientID(clientID)
+	if client.ID == 0 || client.Public {
+		return entities.OAuthClientEntity{}, false
+	}
+
+	return client, service.accountHashing.ComparePassword(client.SecretHash, clientSecret)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"flyhorizons-userservice/services/validation"
+	"log"
+	"time"
+)
+
+// Published on the user_password_changed queue (the user.password_changed event) once the new hash is stored
+type passwordChangedEvent struct {
+	UserID    int       `json:"userId"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+type PasswordChangeService struct {
+	userRepo           interfaces.UserRepository
+	revocationService  interfaces.TokenRevocationService
+	lockoutService     interfaces.AccountLockoutService
+	eventPublisher     interfaces.EventPublisher
+	accountHashing     *authentication.AccountHashing
+	passwordValidation validation.PasswordValidator
+	passwordHistory    interfaces.PasswordHistoryService
+}
+
+var _ interfaces.PasswordChangeService = (*PasswordChangeService)(nil)
+
+func NewPasswordChangeService(userRepo interfaces.UserRepository, revocationService interfaces.TokenRevocationService, lockoutService interfaces.AccountLockoutService, eventPublisher interfaces.EventPublisher, accountHashing *authentication.AccountHashing, passwordValidator validation.PasswordValidator, passwordHistoryService interfaces.PasswordHistoryService) *PasswordChangeService {
+	return &PasswordChangeService{
+		userRepo:           userRepo,
+		revocationService:  revocationService,
+		lockoutService:     lockoutService,
+		eventPublisher:     eventPublisher,
+		accountHashing:     accountHashing,
+		passwordValidation: passwordValidator,
+		passwordHistory:    passwordHistoryService,
+	}
+}
+
+// Replaces the password of a signed in user, who has to prove they know the current one.
+// Every other session is signed out, the session of the given refresh token is kept.
+func (service *PasswordChangeService) ChangePassword(userID int, changeRequest request.ChangePasswordRequest) error {
+	accountEntity := service.userRepo.GetByID(userID)
+	if accountEntity.ID == 0 {
+		return errors.NewUserNotFoundError(userID, 404)
+	}
+
+	// Guessing the current password counts towards the same lockout as failed logins
+	if err := service.lockoutService.Check(userID); err != nil {
+		log.Printf(
+			"Password change attempt on locked account:\n  User ID: %v\n  Timestamp: %s",
+			userID,
+			time.Now().Format(time.RFC3339),
+		)
+		return err
+	}
+
+	// A stolen access token alone is not enough to take over the account
+	if !service.accountHashing.ComparePassword(accountEntity.Password, changeRequest.CurrentPassword) {
+		log.Printf(
+			"Failed password change, current password does not match:\n  User ID: %v\n  Timestamp: %s",
+			userID,
+			time.Now().Format(time.RFC3339),
+		)
+		service.lockoutService.RecordFailure(userID)
+		return errors.NewInvalidCredentialsError(403)
+	}
+	service.lockoutService.RecordSuccess(userID)
+
+	accountType := enums.AccountTypeFromInt(accountEntity.AccountType)
+	if err := service.passwordValidation.ValidateForAccountType(changeRequest.NewPassword, accountType); err != nil {
+		return err
+	}
+	historyDepth := service.passwordValidation.PolicyFor(accountType).HistoryDepth
+	if err := service.passwordHistory.CheckReuse(userID, accountEntity.Password, changeRequest.NewPassword, historyDepth); err != nil {
+		return err
+	}
+
+	hashedPassword, err := service.accountHashing.HashPassword(changeRequest.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if !service.userRepo.UpdatePassword(userID, hashedPassword) {
+		return errors.NewUserNotFoundError(userID, 404)
+	}
+	service.passwordHistory.Remember(userID, accountEntity.Password, historyDepth)
+
+	service.revocationService.RevokeOtherSessions(userID, changeRequest.RefreshToken)
+
+	if err := service.eventPublisher.Publish("user_password_changed", passwordChangedEvent{UserID: userID, ChangedAt: time.Now()}); err != nil {
+		log.Printf("An error occurred while posting the messaging to RabbitMQ %v\n", err)
+	}
+
+	log.Printf(
+		"Successfully changed password:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
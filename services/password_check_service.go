@@ -0,0 +1,52 @@
+package services
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+	"flyhorizons-userservice/services/validation"
+)
+
+type PasswordCheckService struct {
+	passwordValidation validation.PasswordValidator
+	strengthEstimator  *validation.StrengthEstimator
+}
+
+var _ interfaces.PasswordCheckService = (*PasswordCheckService)(nil)
+
+func NewPasswordCheckService(passwordValidator validation.PasswordValidator, strengthEstimator *validation.StrengthEstimator) *PasswordCheckService {
+	return &PasswordCheckService{
+		passwordValidation: passwordValidator,
+		strengthEstimator:  strengthEstimator,
+	}
+}
+
+// Rates a password before it is submitted, nothing is stored or logged
+func (service *PasswordCheckService) Check(checkRequest request.PasswordCheckRequest) response.PasswordCheckResponse {
+	accountType := enums.User
+	if checkRequest.AccountType != nil {
+		accountType = *checkRequest.AccountType
+	}
+
+	user := models.User{
+		FullName:    checkRequest.FullName,
+		Email:       checkRequest.Email,
+		AccountType: accountType,
+	}
+	strength := service.strengthEstimator.Estimate(checkRequest.Password, user)
+
+	violations := []string{}
+	for _, violation := range service.passwordValidation.PolicyViolations(checkRequest.Password, accountType) {
+		violations = append(violations, violation.Error())
+	}
+
+	return response.PasswordCheckResponse{
+		Score:       strength.Score,
+		Entropy:     strength.Entropy,
+		Valid:       len(violations) == 0,
+		Violations:  violations,
+		Suggestions: strength.Suggestions,
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+)
+
+// The history depth is the number of most recent passwords that can't be reused, the current password
+// included, so the history itself holds at most depth - 1 replaced hashes
+type PasswordHistoryService struct {
+	historyRepo    interfaces.PasswordHistoryRepository
+	accountHashing *authentication.AccountHashing
+}
+
+var _ interfaces.PasswordHistoryService = (*PasswordHistoryService)(nil)
+
+func NewPasswordHistoryService(historyRepo interfaces.PasswordHistoryRepository, accountHashing *authentication.AccountHashing) *PasswordHistoryService {
+	return &PasswordHistoryService{
+		historyRepo:    historyRepo,
+		accountHashing: accountHashing,
+	}
+}
+
+func (service *PasswordHistoryService) CheckReuse(userID int, currentHash string, password string, depth int) error {
+	if depth <= 0 {
+		return nil
+	}
+
+	if currentHash != "" && service.accountHashing.ComparePassword(currentHash, password) {
+		return errors.NewPasswordReusedError(400)
+	}
+	for _, historyEntity := range service.historyRepo.GetRecentByUserID(userID, depth-1) {
+		if service.accountHashing.ComparePassword(historyEntity.PasswordHash, password) {
+			return errors.NewPasswordReusedError(400)
+		}
+	}
+
+	return nil
+}
+
+// Called after the password changed, with the hash it replaced
+func (service *PasswordHistoryService) Remember(userID int, replacedHash string, depth int) {
+	if depth > 1 && replacedHash != "" {
+		service.historyRepo.Create(entities.PasswordHistoryEntity{
+			UserID:       userID,
+			PasswordHash: replacedHash,
+			CreatedAt:    time.Now(),
+		})
+	}
+	service.historyRepo.DeleteAllButRecent(userID, max(depth-1, 0))
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"flyhorizons-userservice/services/validation"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+const passwordResetTokenLifetime = 30 * time.Minute
+
+// Pending reset requests, requests beyond this are dropped instead of slowing down the endpoint
+const passwordResetQueueSize = 100
+
+type PasswordResetService struct {
+	userRepo           interfaces.UserRepository
+	resetRepo          interfaces.PasswordResetRepository
+	revocationService  interfaces.TokenRevocationService
+	mailer             interfaces.Mailer
+	accountHashing     *authentication.AccountHashing
+	passwordValidation validation.PasswordValidator
+	passwordHistory    interfaces.PasswordHistoryService
+	resetURL           string
+	resetQueue         chan string
+}
+
+var _ interfaces.PasswordResetService = (*PasswordResetService)(nil)
+
+func NewPasswordResetService(userRepo interfaces.UserRepository, resetRepo interfaces.PasswordResetRepository, revocationService interfaces.TokenRevocationService, mailer interfaces.Mailer, accountHashing *authentication.AccountHashing, passwordValidator validation.PasswordValidator, passwordHistoryService interfaces.PasswordHistoryService, resetURL string) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:           userRepo,
+		resetRepo:          resetRepo,
+		revocationService:  revocationService,
+		mailer:             mailer,
+		accountHashing:     accountHashing,
+		passwordValidation: passwordValidator,
+		passwordHistory:    passwordHistoryService,
+		resetURL:           resetURL,
+		resetQueue:         make(chan string, passwordResetQueueSize),
+	}
+}
+
+// Queues a reset link for the email address. Nothing is returned to the caller and the account is looked up
+// off the request path, so neither the response nor its timing shows which email addresses have an account.
+func (service *PasswordResetService) Forgot(forgotRequest request.ForgotPasswordRequest) {
+	select {
+	case service.resetQueue <- forgotRequest.Email:
+	default:
+		log.Printf(
+			"Password reset queue is full, request dropped:\n  Timestamp: %s",
+			time.Now().Format(time.RFC3339),
+		)
+	}
+}
+
+// Sends the queued reset links in the background
+func (service *PasswordResetService) StartResetQueue() {
+	go func() {
+		for email := range service.resetQueue {
+			service.SendResetLink(email)
+		}
+	}()
+}
+
+// Emails a reset link when the account exists
+func (service *PasswordResetService) SendResetLink(email string) {
+	accountEntity := service.userRepo.GetByEmail(email)
+	if accountEntity.ID == 0 {
+		log.Printf(
+			"Password reset requested for unknown email:\n  Timestamp: %s",
+			time.Now().Format(time.RFC3339),
+		)
+		return
+	}
+
+	resetToken, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		log.Printf("An error occurred while generating a password reset token: %v", err)
+		return
+	}
+
+	// Only the newest link works, the hash is stored and the raw token only goes out by email
+	service.resetRepo.InvalidateAllForUser(accountEntity.ID)
+	service.resetRepo.Create(entities.PasswordResetTokenEntity{
+		TokenHash: authentication.HashOpaqueToken(resetToken),
+		UserID:    accountEntity.ID,
+		ExpiresAt: time.Now().Add(passwordResetTokenLifetime),
+		CreatedAt: time.Now(),
+	})
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nUse the link below to choose a new password. The link expires in %d minutes.\n\n%s\n\nIf you did not request a password reset, you can ignore this email.",
+		accountEntity.FullName,
+		int(passwordResetTokenLifetime.Minutes()),
+		linkWithToken(service.resetURL, resetToken),
+	)
+	if err := service.mailer.Send(accountEntity.Email, "Reset your FlyHorizons password", body); err != nil {
+		log.Printf("An error occurred while sending the password reset email: %v", err)
+		return
+	}
+
+	log.Printf(
+		"Password reset requested:\n  User ID: %v\n  Timestamp: %s",
+		accountEntity.ID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+func (service *PasswordResetService) Reset(resetRequest request.ResetPasswordRequest) error {
+	resetToken := service.resetRepo.GetByTokenHash(authentication.HashOpaqueToken(resetRequest.Token))
+
+	// Reset token is unknown, used or expired
+	if resetToken.ID == 0 || resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return errors.NewInvalidPasswordResetTokenError(400)
+	}
+
+	accountEntity := service.userRepo.GetByID(resetToken.UserID)
+	if accountEntity.ID == 0 {
+		return errors.NewInvalidPasswordResetTokenError(400)
+	}
+
+	// Validated before the token is used, so a rejected password does not cost the user the link
+	accountType := enums.AccountTypeFromInt(accountEntity.AccountType)
+	if err := service.passwordValidation.ValidateForAccountType(resetRequest.NewPassword, accountType); err != nil {
+		return err
+	}
+	historyDepth := service.passwordValidation.PolicyFor(accountType).HistoryDepth
+	if err := service.passwordHistory.CheckReuse(accountEntity.ID, accountEntity.Password, resetRequest.NewPassword, historyDepth); err != nil {
+		return err
+	}
+
+	hashedPassword, err := service.accountHashing.HashPassword(resetRequest.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// A token can only be used once
+	if !service.resetRepo.MarkAsUsed(resetToken.ID) {
+		return errors.NewInvalidPasswordResetTokenError(400)
+	}
+
+	if !service.userRepo.UpdatePassword(resetToken.UserID, hashedPassword) {
+		return errors.NewInvalidPasswordResetTokenError(400)
+	}
+	service.passwordHistory.Remember(accountEntity.ID, accountEntity.Password, historyDepth)
+
+	// Whoever knew the old password is signed out everywhere
+	service.resetRepo.InvalidateAllForUser(resetToken.UserID)
+	service.revocationService.RevokeAllSessions(resetToken.UserID)
+
+	log.Printf(
+		"Successfully reset password:\n  User ID: %v\n  Timestamp: %s",
+		resetToken.UserID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+// Adds the token to the query of a link that is sent by email
+func linkWithToken(baseURL string, token string) string {
+	link, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time // From this moment the bucket is full again and can be forgotten
+}
+
+// Keeps the buckets in memory, limits are only enforced per replica
+type MemoryStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+var _ interfaces.RateLimitStore = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (store *MemoryStore) Take(key string, requests int, per time.Duration) (bool, time.Duration) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := time.Now()
+	refillRate := float64(requests) / per.Seconds() // Tokens per second
+
+	current, exists := store.buckets[key]
+	if !exists {
+		current = &bucket{tokens: float64(requests), updatedAt: now}
+		store.buckets[key] = current
+	}
+
+	current.tokens = min(float64(requests), current.tokens+now.Sub(current.updatedAt).Seconds()*refillRate)
+	current.updatedAt = now
+
+	allowed := current.tokens >= 1
+	if allowed {
+		current.tokens--
+	}
+	current.fullAt = now.Add(secondsToDuration((float64(requests) - current.tokens) / refillRate))
+
+	if !allowed {
+		return false, secondsToDuration((1 - current.tokens) / refillRate)
+	}
+	return true, 0
+}
+
+// Forgets full buckets, they behave the same as buckets that were never used
+func (store *MemoryStore) StartCleanupSchedule(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+			store.deleteFull()
+		}
+	}()
+}
+
+func (store *MemoryStore) deleteFull() {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := time.Now()
+	for key, current := range store.buckets {
+		if !now.Before(current.fullAt) {
+			delete(store.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,198 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/services/interfaces"
+	"flyhorizons-userservice/utils"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request bodies the email limit reads at most, every request it applies to is far smaller
+const maxEmailBodySize = 4 << 10
+
+// Number of requests allowed per period, written as "10/1m"
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Parses "requests/period", an empty value or "off" disables the limit
+func ParseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" {
+		return RateLimit{}, nil
+	}
+
+	requests, period, found := strings.Cut(value, "/")
+	if !found {
+		return RateLimit{}, fmt.Errorf("ratelimit: %q is not formatted as requests/period", value)
+	}
+	limit := RateLimit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests < 0 {
+		return RateLimit{}, fmt.Errorf("ratelimit: invalid number of requests in %q", value)
+	}
+	if limit.Per, err = time.ParseDuration(period); err != nil || limit.Per <= 0 {
+		return RateLimit{}, fmt.Errorf("ratelimit: invalid period in %q", value)
+	}
+
+	return limit, nil
+}
+
+func (limit RateLimit) Enabled() bool {
+	return limit.Requests > 0 && limit.Per > 0
+}
+
+// Limits of a single route, the email limit applies to the "email" field of the JSON body
+type RouteRateLimit struct {
+	PerIP    RateLimit
+	PerEmail RateLimit
+}
+
+// Routes are keyed on their method and path pattern, e.g. "POST /login"
+type Config map[string]RouteRateLimit
+
+var defaultRouteRateLimits = []struct {
+	route    string
+	envName  string
+	perIP    string
+	perEmail string
+}{
+	{"POST /login", "LOGIN", "20/1m", "5/1m"},
+	{"POST /login/mfa", "LOGIN_MFA", "20/1m", ""},
+	{"POST /login/mfa/enroll", "LOGIN_MFA_ENROLL", "20/1m", ""},
+	{"POST /login/passkey", "LOGIN_PASSKEY", "20/1m", ""},
+	{"POST /login/passkey/options", "LOGIN_PASSKEY_OPTIONS", "20/1m", ""},
+	{"POST /token/refresh", "TOKEN_REFRESH", "60/1m", ""},
+	{"POST /oauth/token", "OAUTH_TOKEN", "60/1m", ""},
+	{"POST /oauth/introspect", "OAUTH_INTROSPECT", "600/1m", ""},
+	{"POST /users", "REGISTER", "10/1h", "3/1h"},
+	{"POST /password/forgot", "PASSWORD_FORGOT", "10/1h", "3/1h"},
+	{"POST /password/reset", "PASSWORD_RESET", "10/1h", ""},
+	{"POST /password/check", "PASSWORD_CHECK", "60/1m", ""},
+	{"POST /users/:userID/password", "PASSWORD_CHANGE", "10/1h", ""},
+	{"POST /users/verify/resend", "VERIFICATION_RESEND", "10/1h", "5/1h"},
+}
+
+// Every limit can be overridden through RATE_LIMIT_<ROUTE>_IP and RATE_LIMIT_<ROUTE>_EMAIL,
+// for example RATE_LIMIT_LOGIN_IP=50/1m. Invalid values keep the default.
+func LoadConfigFromEnv() Config {
+	config := Config{}
+	for _, defaults := range defaultRouteRateLimits {
+		config[defaults.route] = RouteRateLimit{
+			PerIP:    loadRateLimitFromEnv("RATE_LIMIT_"+defaults.envName+"_IP", defaults.perIP),
+			PerEmail: loadRateLimitFromEnv("RATE_LIMIT_"+defaults.envName+"_EMAIL", defaults.perEmail),
+		}
+	}
+	return config
+}
+
+func loadRateLimitFromEnv(name string, defaultValue string) RateLimit {
+	defaultLimit, _ := ParseRateLimit(defaultValue)
+
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return defaultLimit
+	}
+	limit, err := ParseRateLimit(value)
+	if err != nil {
+		log.Printf("Ignoring %s: %v", name, err)
+		return defaultLimit
+	}
+	return limit
+}
+
+type RateLimiter struct {
+	store  interfaces.RateLimitStore
+	config Config
+}
+
+var _ interfaces.RateLimiter = (*RateLimiter)(nil)
+
+func NewRateLimiter(store interfaces.RateLimitStore, config Config) *RateLimiter {
+	return &RateLimiter{
+		store:  store,
+		config: config,
+	}
+}
+
+// Registered on the router, routes without a configured limit pass through
+func (limiter *RateLimiter) RateLimitMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		route := ctx.Request.Method + " " + ctx.FullPath()
+		limits, exists := limiter.config[route]
+		if !exists {
+			ctx.Next()
+			return
+		}
+
+		if limits.PerIP.Enabled() && !limiter.take(ctx, route+"|ip|"+utils.GetIPAddress(ctx.Request), limits.PerIP) {
+			return
+		}
+
+		if limits.PerEmail.Enabled() {
+			if email := requestEmail(ctx); email != "" && !limiter.take(ctx, route+"|email|"+email, limits.PerEmail) {
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+func (limiter *RateLimiter) take(ctx *gin.Context, key string, limit RateLimit) bool {
+	allowed, retryAfter := limiter.store.Take(key, limit.Requests, limit.Per)
+	if allowed {
+		return true
+	}
+
+	log.Printf(
+		"Rate limit exceeded:\n  Route: %s %s\n  Timestamp: %s\n  IP Address: %s",
+		ctx.Request.Method,
+		ctx.FullPath(),
+		time.Now().Format(time.RFC3339),
+		utils.GetIPAddress(ctx.Request),
+	)
+
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests, please try again later"})
+	return false
+}
+
+// Reads the email from the JSON body and puts the body back for the handler. Bodies larger than
+// maxEmailBodySize are not parsed and pass without an email limit, the handler still reads them in full.
+func requestEmail(ctx *gin.Context) string {
+	if ctx.Request.Body == nil {
+		return ""
+	}
+
+	originalBody := ctx.Request.Body
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, originalBody, maxEmailBodySize))
+	ctx.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), originalBody), originalBody}
+	if err != nil {
+		return ""
+	}
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(payload.Email))
+}
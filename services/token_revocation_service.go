@@ -0,0 +1,125 @@
+package services
+
+import (
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/interfaces"
+	"log"
+	"time"
+)
+
+type TokenRevocationService struct {
+	revocationRepo   interfaces.TokenRevocationRepository
+	refreshTokenRepo interfaces.RefreshTokenRepository
+}
+
+var _ interfaces.TokenRevocationService = (*TokenRevocationService)(nil)
+
+func NewTokenRevocationService(revocationRepo interfaces.TokenRevocationRepository, refreshTokenRepo interfaces.RefreshTokenRepository) *TokenRevocationService {
+	return &TokenRevocationService{
+		revocationRepo:   revocationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+func (service *TokenRevocationService) Logout(logoutRequest request.LogoutRequest, jti string, userID int, expiresAt time.Time) {
+	// A token without an ID can't be revoked on its own, so every session of the user is ended instead
+	if logoutRequest.AllSessions || jti == "" {
+		service.RevokeAllSessions(userID)
+		return
+	}
+
+	service.revocationRepo.RevokeToken(entities.RevokedTokenEntity{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		RevokedAt: time.Now(),
+	})
+
+	// The refresh token can only end the session it belongs to
+	if logoutRequest.RefreshToken != "" {
+		refreshToken := service.refreshTokenRepo.GetByTokenHash(authentication.HashOpaqueToken(logoutRequest.RefreshToken))
+		if refreshToken.ID != 0 && refreshToken.UserID == userID {
+			service.refreshTokenRepo.RevokeFamily(refreshToken.FamilyID)
+		}
+	}
+
+	log.Printf(
+		"Successful logout:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+func (service *TokenRevocationService) RevokeAllSessions(userID int) {
+	revokedBefore := sessionRevocationCutOff()
+
+	service.revocationRepo.RevokeSessions(entities.SessionRevocationEntity{
+		UserID:        userID,
+		RevokedBefore: revokedBefore,
+		ExpiresAt:     revokedBefore.Add(accessTokenLifetime),
+	})
+	service.refreshTokenRepo.RevokeAllForUser(userID)
+
+	log.Printf(
+		"Revoked all sessions:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+// Revokes every session except the one the refresh token belongs to. Access tokens can't be told apart
+// per session, so the caller keeps its session by refreshing with the token it passed in.
+func (service *TokenRevocationService) RevokeOtherSessions(userID int, refreshToken string) {
+	var currentToken entities.RefreshTokenEntity
+	if refreshToken != "" {
+		currentToken = service.refreshTokenRepo.GetByTokenHash(authentication.HashOpaqueToken(refreshToken))
+	}
+
+	// Without a valid refresh token of this user there is no session to keep
+	if currentToken.ID == 0 || currentToken.UserID != userID || currentToken.RevokedAt != nil {
+		service.RevokeAllSessions(userID)
+		return
+	}
+
+	revokedBefore := sessionRevocationCutOff()
+
+	service.revocationRepo.RevokeSessions(entities.SessionRevocationEntity{
+		UserID:        userID,
+		RevokedBefore: revokedBefore,
+		ExpiresAt:     revokedBefore.Add(accessTokenLifetime),
+	})
+	service.refreshTokenRepo.RevokeAllForUserExceptFamily(userID, currentToken.FamilyID)
+
+	log.Printf(
+		"Revoked other sessions:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+}
+
+func (service *TokenRevocationService) IsRevoked(jti string, userID int, issuedAt time.Time) bool {
+	if jti != "" && service.revocationRepo.IsTokenRevoked(jti) {
+		return true
+	}
+
+	sessionRevocation := service.revocationRepo.GetSessionRevocation(userID)
+	return sessionRevocation.UserID != 0 && issuedAt.Before(sessionRevocation.RevokedBefore)
+}
+
+// Token timestamps only have second precision, so the cut-off is rounded up to revoke every token issued
+// in the same second as the revocation. A token issued right after it in that second is revoked as well,
+// a client that keeps its session refreshes again.
+func sessionRevocationCutOff() time.Time {
+	return time.Now().Truncate(time.Second).Add(time.Second)
+}
+
+func (service *TokenRevocationService) StartCleanupSchedule(interval time.Duration) {
+	go func() {
+		for {
+			service.revocationRepo.DeleteExpired()
+			time.Sleep(interval)
+		}
+	}()
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/models/enums"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/errors"
+	"slices"
+	"strings"
+)
+
+// Fields of the user resource, the email address and password have their own flows and are never patchable
+var userResourceFields = []string{"id", "full_name", "email", "email_verified", "account_type", "created_at", "password"}
+
+// Fields each role may change through PATCH
+var patchableUserFields = map[string][]string{
+	"user":  {"full_name"},
+	"admin": {"full_name", "email_verified", "account_type"},
+}
+
+func applyUserPatch(userEntity entities.UserEntity, changes map[string]json.RawMessage, role string) (entities.UserEntity, error) {
+	for field, value := range changes {
+		if !slices.Contains(userResourceFields, field) {
+			return userEntity, errors.NewInvalidPatchError(field, 400)
+		}
+		if !slices.Contains(patchableUserFields[role], field) {
+			return userEntity, errors.NewFieldNotPatchableError(field, 403)
+		}
+
+		// None of the patchable fields can be removed, so null is rejected like any other invalid value
+		switch field {
+		case "full_name":
+			var fullName *string
+			if json.Unmarshal(value, &fullName) != nil || fullName == nil || strings.TrimSpace(*fullName) == "" {
+				return userEntity, errors.NewInvalidPatchError(field, 400)
+			}
+			userEntity.FullName = strings.TrimSpace(*fullName)
+		case "email_verified":
+			var emailVerified *bool
+			if json.Unmarshal(value, &emailVerified) != nil || emailVerified == nil {
+				return userEntity, errors.NewInvalidPatchError(field, 400)
+			}
+			userEntity.EmailVerified = *emailVerified
+		case "account_type":
+			var accountType *int
+			if json.Unmarshal(value, &accountType) != nil || accountType == nil || (*accountType != int(enums.Admin) && *accountType != int(enums.User)) {
+				return userEntity, errors.NewInvalidPatchError(field, 400)
+			}
+			userEntity.AccountType = *accountType
+		}
+	}
+
+	return userEntity, nil
+}
+
+// Columns of the fields that can change through an update, keyed by column name
+func changedUserColumns(storedUserEntity entities.UserEntity, userEntity entities.UserEntity) map[string]interface{} {
+	columns := map[string]interface{}{}
+	if userEntity.FullName != storedUserEntity.FullName {
+		columns["FullName"] = userEntity.FullName
+	}
+	if userEntity.EmailVerified != storedUserEntity.EmailVerified {
+		columns["EmailVerified"] = userEntity.EmailVerified
+	}
+	if userEntity.AccountType != storedUserEntity.AccountType {
+		columns["AccountType"] = userEntity.AccountType
+	}
+	return columns
+}
@@ -3,13 +3,16 @@ package services
 import (
 	"encoding/json"
 	"flyhorizons-userservice/config"
-	"flyhorizons-userservice/models"
 	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/services/authentication"
 	"flyhorizons-userservice/services/converter"
 	"flyhorizons-userservice/services/errors"
 	"flyhorizons-userservice/services/interfaces"
 	"flyhorizons-userservice/services/validation"
+	"fmt"
 	"log"
 	"time"
 
@@ -21,29 +24,32 @@ type UserService struct {
 	accountHashing     *authentication.AccountHashing
 	passwordValidation validation.PasswordValidator
 	userConverter      converter.UserConverter
+	emailVerification  interfaces.EmailVerificationService
 }
 
-func NewUserService(repo interfaces.UserRepository, accountHashing *authentication.AccountHashing, passwordValidator validation.PasswordValidator, userConverter converter.UserConverter) *UserService {
+func NewUserService(repo interfaces.UserRepository, accountHashing *authentication.AccountHashing, passwordValidator validation.PasswordValidator, userConverter converter.UserConverter, emailVerificationService interfaces.EmailVerificationService) *UserService {
 	return &UserService{
-		userRepo:       repo,
-		accountHashing: accountHashing,
-		userConverter:  userConverter,
+		userRepo:           repo,
+		accountHashing:     accountHashing,
+		passwordValidation: passwordValidator,
+		userConverter:      userConverter,
+		emailVerification:  emailVerificationService,
 	}
 }
 
-func (userService *UserService) GetAll() []models.User {
+func (userService *UserService) GetAll() []response.AdminUserResponse {
 	userEntities := userService.userRepo.GetAll()
 
-	var users []models.User
+	var users []response.AdminUserResponse
 	for _, userEntity := range userEntities {
-		user := userService.userConverter.ConvertUserEntityToUser(userEntity)
+		user := userService.userConverter.ConvertUserEntityToAdminUserResponse(userEntity)
 		users = append(users, user)
 	}
 
 	return users
 }
 
-func (userService *UserService) GetByID(id int) (*models.User, error) {
+func (userService *UserService) GetByID(id int) (*response.UserProfileResponse, error) {
 	userEntity := userService.userRepo.GetByID(id)
 	// User is not found
 	if userEntity.ID == 0 {
@@ -51,7 +57,7 @@ func (userService *UserService) GetByID(id int) (*models.User, error) {
 	}
 
 	// User is found
-	user := userService.userConverter.ConvertUserEntityToUser(userEntity)
+	user := userService.userConverter.ConvertUserEntityToUserProfileResponse(userEntity)
 	return &user, nil
 }
 
@@ -64,28 +70,32 @@ func (UserService *UserService) UserExists(id int) bool {
 	return false
 }
 
-func (userService *UserService) Create(user models.User) (*models.User, error) {
-	if userService.UserExists(user.ID) {
-		return nil, errors.NewUserExistsError(user.ID, 409)
+func (userService *UserService) Create(createRequest request.CreateUserRequest) (*response.UserProfileResponse, error) {
+	if userService.userRepo.GetByEmail(createRequest.Email).ID != 0 {
+		return nil, errors.NewEmailInUseError(409)
 	}
 
-	// Validate password
-	err := userService.passwordValidation.Validate(user.Password)
+	// Validate password against the policy of regular users, registration never creates admins
+	err := userService.passwordValidation.ValidateForAccountType(createRequest.Password, enums.User)
 	if err != nil {
 		return nil, err
 	}
 
 	// Encode password
-	hashedPassword, err := userService.accountHashing.HashPassword(user.Password)
+	hashedPassword, err := userService.accountHashing.HashPassword(createRequest.Password)
 	if err != nil {
 		return nil, err
 	}
-	user.Password = hashedPassword
 
-	var userEntity = userService.userConverter.ConvertUserToUserEntity(user)
+	var userEntity = userService.userConverter.ConvertCreateUserRequestToUserEntity(createRequest, hashedPassword)
 	var postUserEntity = userService.userRepo.Create(userEntity)
 	var postUser = userService.userConverter.ConvertUserEntityToUser(postUserEntity)
 
+	// The account is created either way, the user can request a new link when sending fails
+	if err := userService.emailVerification.SendVerification(postUser); err != nil {
+		log.Printf("An error occurred while sending the verification email: %v", err)
+	}
+
 	// Successful account creation
 	accountTypeInt := enums.AccountTypeFromInt(int(postUser.AccountType))
 	var accountType string
@@ -104,46 +114,57 @@ func (userService *UserService) Create(user models.User) (*models.User, error) {
 		time.Now().Format(time.RFC3339),
 	)
 
-	return &postUser, nil
+	profile := userService.userConverter.ConvertUserEntityToUserProfileResponse(postUserEntity)
+	return &profile, nil
 }
 
-func (userService *UserService) DeleteByID(id int) (bool, error) {
+// Only deletes the account when it still has the version the caller last read
+func (userService *UserService) DeleteByID(id int, version int) (bool, error) {
 	if !userService.UserExists(id) {
 		return false, errors.NewUserNotFoundError(id, 404)
 	}
 
 	// Delete data from the user database
-	isDeleted := userService.userRepo.DeleteByID(id)
+	isDeleted := userService.userRepo.DeleteByID(id, version)
+	if !isDeleted {
+		// Only a changed version is a conflict, anything else is a failed delete
+		storedUserEntity := userService.userRepo.GetByID(id)
+		if storedUserEntity.ID == 0 {
+			return false, errors.NewUserNotFoundError(id, 404)
+		}
+		if storedUserEntity.Version != version {
+			return false, errors.NewConcurrentModificationError(id, 412)
+		}
+		return false, fmt.Errorf("failed to delete user %d", id)
+	}
 
 	// Delete data from any other databases (containing user data)
-	if isDeleted {
-		// Post user_deleted event to RabbitMQ
-		channel := config.RabbitMQClient.Channel
-
-		body, err := json.Marshal(struct {
-			UserID int `json:"userId"`
-		}{
-			UserID: id,
-		})
-
-		if err != nil {
-			log.Printf("Failed to marshal user deletion event: %v", err)
-		}
+	// Post user_deleted event to RabbitMQ
+	channel := config.RabbitMQClient.Channel
 
-		err = channel.Publish(
-			"",
-			"user_deleted",
-			false,
-			false,
-			amqp091.Publishing{
-				ContentType: "application/json",
-				Body:        body,
-			},
-		)
-
-		if err != nil {
-			log.Printf("An error occurred while posting the messaging to RabbitMQ %v\n", err)
-		}
+	body, err := json.Marshal(struct {
+		UserID int `json:"userId"`
+	}{
+		UserID: id,
+	})
+
+	if err != nil {
+		log.Printf("Failed to marshal user deletion event: %v", err)
+	}
+
+	err = channel.Publish(
+		"",
+		"user_deleted",
+		false,
+		false,
+		amqp091.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Printf("An error occurred while posting the messaging to RabbitMQ %v\n", err)
 	}
 
 	// Successful account deletion
@@ -156,33 +177,62 @@ func (userService *UserService) DeleteByID(id int) (bool, error) {
 	return isDeleted, nil
 }
 
-func (userService *UserService) Update(user models.User) (*models.User, error) {
-	if !userService.UserExists(user.ID) {
-		return nil, errors.NewUserNotFoundError(user.ID, 404)
+func (userService *UserService) Update(updateRequest request.UpdateUserRequest, version int) (*response.UserProfileResponse, error) {
+	if !userService.UserExists(updateRequest.ID) {
+		return nil, errors.NewUserNotFoundError(updateRequest.ID, 404)
 	}
 
-	// Validate password
-	err := userService.passwordValidation.Validate(user.Password)
-	if err != nil {
-		return nil, err
+	storedUserEntity := userService.userRepo.GetByID(updateRequest.ID)
+	if storedUserEntity.Version != version {
+		return nil, errors.NewConcurrentModificationError(updateRequest.ID, 412)
 	}
 
-	// Encode password
-	hashedPassword, err := userService.accountHashing.HashPassword(user.Password)
+	// The password, email address and account type keep their stored values
+	var userEntity = userService.userConverter.ConvertUpdateUserRequestToUserEntity(updateRequest, storedUserEntity)
+
+	return userService.saveChanges(storedUserEntity, userEntity)
+}
+
+// Applies a JSON Merge Patch, or a JSON Patch converted to one, to the account. Only the fields
+// on the allow-list of the role can be changed and only the changed columns are written.
+func (userService *UserService) Patch(id int, changes map[string]json.RawMessage, role string, version int) (*response.UserProfileResponse, error) {
+	storedUserEntity := userService.userRepo.GetByID(id)
+	if storedUserEntity.ID == 0 {
+		return nil, errors.NewUserNotFoundError(id, 404)
+	}
+
+	// The precondition is checked before the patch itself is validated
+	if storedUserEntity.Version != version {
+		return nil, errors.NewConcurrentModificationError(id, 412)
+	}
+
+	userEntity, err := applyUserPatch(storedUserEntity, changes, role)
 	if err != nil {
 		return nil, err
 	}
-	user.Password = hashedPassword
 
-	var userEntity = userService.userConverter.ConvertUserToUserEntity(user)
-	var putUserEntity = userService.userRepo.Update(userEntity)
-	var putUser = userService.userConverter.ConvertUserEntityToUser(putUserEntity)
+	return userService.saveChanges(storedUserEntity, userEntity)
+}
+
+// The columns are only written when the account still has the stored version, so a concurrent
+// update that came in after the account was read is not overwritten
+func (userService *UserService) saveChanges(storedUserEntity entities.UserEntity, userEntity entities.UserEntity) (*response.UserProfileResponse, error) {
+	columns := changedUserColumns(storedUserEntity, userEntity)
+	if len(columns) > 0 {
+		if !userService.userRepo.UpdateColumns(storedUserEntity.ID, storedUserEntity.Version, columns) {
+			return nil, errors.NewConcurrentModificationError(storedUserEntity.ID, 412)
+		}
+		userEntity.Version = storedUserEntity.Version + 1
+	}
+
+	var putUser = userService.userConverter.ConvertUserEntityToUserProfileResponse(userEntity)
 
 	// Successful account updating
 	log.Printf(
-		"Successfully updated account:\n  User ID: %v\n  Account Type: %v\n  Timestamp: %s",
+		"Successfully updated account:\n  User ID: %v\n  Account Type: %v\n  Changed Columns: %v\n  Timestamp: %s",
 		putUser.ID,
 		enums.AccountTypeFromInt(int(putUser.AccountType)),
+		len(columns),
 		time.Now().Format(time.RFC3339),
 	)
 
@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Breached passwords as SHA-1 hashes, one "HASH" or "HASH:COUNT" line each as in the Have I Been Pwned
+// downloads. Only the first 8 bytes of every hash are kept so hundreds of millions of entries fit in
+// memory, the chance that a password is refused because of a shortened hash is negligible.
+type BreachedPasswordList struct {
+	prefixes []uint64 // Sorted
+}
+
+func LoadBreachedPasswordList(filepath string) (*BreachedPasswordList, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached passwords file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := openPasswordList(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read breached passwords file: %w", err)
+	}
+
+	list := &BreachedPasswordList{}
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if hash == "" {
+			continue
+		}
+		decoded, err := hex.DecodeString(hash)
+		if err != nil || len(decoded) != sha1.Size {
+			return nil, fmt.Errorf("invalid SHA-1 hash on line %d of the breached passwords file", lineNumber)
+		}
+		list.prefixes = append(list.prefixes, binary.BigEndian.Uint64(decoded))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading breached passwords: %w", err)
+	}
+
+	slices.Sort(list.prefixes)
+	list.prefixes = slices.Compact(list.prefixes)
+	return list, nil
+}
+
+func (list *BreachedPasswordList) Contains(password string) bool {
+	hash := sha1.Sum([]byte(password))
+	_, found := slices.BinarySearch(list.prefixes, binary.BigEndian.Uint64(hash[:]))
+	return found
+}
+
+func (list *BreachedPasswordList) Len() int {
+	return len(list.prefixes)
+}
+
+// Password lists may be gzip compressed, which is recognized from the magic bytes
+func openPasswordList(file io.Reader) (io.Reader, error) {
+	reader := bufio.NewReader(file)
+	magic, err := reader.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(reader)
+	}
+	return reader, nil
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"os"
+	"strconv"
+	"unicode"
+)
+
+type PasswordPolicy struct {
+	MinLength      int // In characters
+	RequireUpper   bool
+	RequireLower   bool
+	RequireNumber  bool
+	RequireSpecial bool
+	HistoryDepth   int // Most recent passwords that can't be reused, the current one included, 0 allows reuse
+}
+
+// At least 13 characters with upper and lowercase letters, a number and a symbol
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:      13,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireNumber:  true,
+		RequireSpecial: true,
+		HistoryDepth:   5,
+	}
+}
+
+// Admins get a longer minimum length and history by default
+func DefaultAdminPasswordPolicy() PasswordPolicy {
+	policy := DefaultPasswordPolicy()
+	policy.MinLength = 16
+	policy.HistoryDepth = 10
+	return policy
+}
+
+// Every setting can be overridden through <prefix>MIN_LENGTH, <prefix>REQUIRE_UPPER, _LOWER, _NUMBER
+// and _SPECIAL and <prefix>HISTORY_DEPTH, invalid values keep the default
+func LoadPasswordPolicyFromEnv(prefix string, policy PasswordPolicy) PasswordPolicy {
+	if minLength, err := strconv.Atoi(os.Getenv(prefix + "MIN_LENGTH")); err == nil && minLength > 0 {
+		policy.MinLength = minLength
+	}
+	loadBoolFromEnv(prefix+"REQUIRE_UPPER", &policy.RequireUpper)
+	loadBoolFromEnv(prefix+"REQUIRE_LOWER", &policy.RequireLower)
+	loadBoolFromEnv(prefix+"REQUIRE_NUMBER", &policy.RequireNumber)
+	loadBoolFromEnv(prefix+"REQUIRE_SPECIAL", &policy.RequireSpecial)
+	if historyDepth, err := strconv.Atoi(os.Getenv(prefix + "HISTORY_DEPTH")); err == nil && historyDepth >= 0 {
+		policy.HistoryDepth = historyDepth
+	}
+	return policy
+}
+
+func loadBoolFromEnv(name string, value *bool) {
+	if parsed, err := strconv.ParseBool(os.Getenv(name)); err == nil {
+		*value = parsed
+	}
+}
+
+// Descriptions of the required character classes, used in the complexity error
+func (policy PasswordPolicy) RequiredClasses() []string {
+	var classes []string
+	if policy.RequireUpper {
+		classes = append(classes, "an uppercase letter")
+	}
+	if policy.RequireLower {
+		classes = append(classes, "a lowercase letter")
+	}
+	if policy.RequireNumber {
+		classes = append(classes, "a number")
+	}
+	if policy.RequireSpecial {
+		classes = append(classes, "a symbol")
+	}
+	return classes
+}
+
+func (policy PasswordPolicy) meetsCharacterClasses(password string) bool {
+	var hasUpper, hasLower, hasNumber, hasSpecial bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsNumber(char):
+			hasNumber = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSpecial = true
+		}
+	}
+	return (hasUpper || !policy.RequireUpper) &&
+		(hasLower || !policy.RequireLower) &&
+		(hasNumber || !policy.RequireNumber) &&
+		(hasSpecial || !policy.RequireSpecial)
+}
@@ -2,14 +2,26 @@ package validation
 
 import (
 	"bufio"
+	"bytes"
+	_ "embed"
+	"flyhorizons-userservice/models/enums"
 	"flyhorizons-userservice/services/errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
-	"unicode"
+	"unicode/utf8"
 )
 
+// Built into the binary, so the common passwords are rejected without any configuration
+//
+//go:embed data/most_common_passwords.txt
+var bundledCommonPasswords []byte
+
 type PasswordValidator struct {
-	commonPasswords map[string]struct{}
+	commonPasswords   map[string]struct{}
+	breachedPasswords *BreachedPasswordList
+	policies          map[enums.AccountType]PasswordPolicy // Account types without a policy use the default policy
 }
 
 func NewPasswordValidator(filepath string) (*PasswordValidator, error) {
@@ -26,6 +38,35 @@ func NewPasswordValidator(filepath string) (*PasswordValidator, error) {
 	return validator, nil
 }
 
+// Reads the policies from the environment (PASSWORD_* for users, ADMIN_PASSWORD_* for admins) and loads
+// the COMMON_PASSWORDS_FILE and optional BREACHED_PASSWORDS_FILE lists, both may be gzip compressed.
+// Without COMMON_PASSWORDS_FILE the bundled list of common passwords is used.
+func LoadPasswordValidatorFromEnv() (*PasswordValidator, error) {
+	validator := &PasswordValidator{
+		commonPasswords: make(map[string]struct{}),
+	}
+	validator.SetPolicy(enums.User, LoadPasswordPolicyFromEnv("PASSWORD_", DefaultPasswordPolicy()))
+	validator.SetPolicy(enums.Admin, LoadPasswordPolicyFromEnv("ADMIN_PASSWORD_", DefaultAdminPasswordPolicy()))
+
+	if filepath := os.Getenv("COMMON_PASSWORDS_FILE"); filepath != "" {
+		if err := validator.LoadCommonPasswords(filepath); err != nil {
+			return nil, err
+		}
+	} else if err := validator.readCommonPasswords(bytes.NewReader(bundledCommonPasswords)); err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded %d common passwords", len(validator.commonPasswords))
+
+	if filepath := os.Getenv("BREACHED_PASSWORDS_FILE"); filepath != "" {
+		if err := validator.LoadBreachedPasswords(filepath); err != nil {
+			return nil, err
+		}
+		log.Printf("Loaded %d breached password hashes", validator.breachedPasswords.Len())
+	}
+
+	return validator, nil
+}
+
 func (passwordValidator *PasswordValidator) LoadCommonPasswords(filepath string) error {
 	file, err := os.Open(filepath)
 	if err != nil {
@@ -33,7 +74,20 @@ func (passwordValidator *PasswordValidator) LoadCommonPasswords(filepath string)
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return passwordValidator.readCommonPasswords(file)
+}
+
+func (passwordValidator *PasswordValidator) readCommonPasswords(file io.Reader) error {
+	reader, err := openPasswordList(file)
+	if err != nil {
+		return fmt.Errorf("failed to read common passwords file: %w", err)
+	}
+
+	if passwordValidator.commonPasswords == nil {
+		passwordValidator.commonPasswords = make(map[string]struct{})
+	}
+
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		password := scanner.Text()
 		passwordValidator.commonPasswords[password] = struct{}{}
@@ -46,43 +100,62 @@ func (passwordValidator *PasswordValidator) LoadCommonPasswords(filepath string)
 	return nil
 }
 
-// Internal methods
-func (passwordValidator *PasswordValidator) CheckLength(password string) bool {
-	return len(password) > 12
+func (passwordValidator *PasswordValidator) LoadBreachedPasswords(filepath string) error {
+	breachedPasswords, err := LoadBreachedPasswordList(filepath)
+	if err != nil {
+		return err
+	}
+	passwordValidator.breachedPasswords = breachedPasswords
+	return nil
 }
 
-func (passwordValidator *PasswordValidator) CheckCharacterComplexity(password string) bool {
-	var hasUpper, hasLower, hasNumber, hasSpecial bool
-	for _, char := range password {
-		switch {
-		case unicode.IsUpper(char):
-			hasUpper = true
-		case unicode.IsLower(char):
-			hasLower = true
-		case unicode.IsNumber(char):
-			hasNumber = true
-		case unicode.IsPunct(char) || unicode.IsSymbol(char):
-			hasSpecial = true
-		}
+func (passwordValidator *PasswordValidator) SetPolicy(accountType enums.AccountType, policy PasswordPolicy) {
+	if passwordValidator.policies == nil {
+		passwordValidator.policies = make(map[enums.AccountType]PasswordPolicy)
+	}
+	passwordValidator.policies[accountType] = policy
+}
+
+func (passwordValidator *PasswordValidator) PolicyFor(accountType enums.AccountType) PasswordPolicy {
+	if policy, exists := passwordValidator.policies[accountType]; exists {
+		return policy
 	}
-	return hasUpper && hasLower && hasNumber && hasSpecial
+	return DefaultPasswordPolicy()
 }
 
+// Internal methods
 func (passwordValidator *PasswordValidator) CheckAgainstWeakestPasswordsList(password string) bool {
-	_, found := passwordValidator.commonPasswords[password]
-	return found
+	if _, found := passwordValidator.commonPasswords[password]; found {
+		return true
+	}
+	return passwordValidator.breachedPasswords != nil && passwordValidator.breachedPasswords.Contains(password)
 }
 
-// Validation method
+// Validation method, validates against the policy of regular users
 func (passwordValidator *PasswordValidator) Validate(password string) error {
+	return passwordValidator.ValidateForAccountType(password, enums.User)
+}
+
+func (passwordValidator *PasswordValidator) ValidateForAccountType(password string, accountType enums.AccountType) error {
+	if violations := passwordValidator.PolicyViolations(password, accountType); len(violations) > 0 {
+		return violations[0]
+	}
+	return nil
+}
+
+// Every rule of the policy the password breaks, instead of only the first one
+func (passwordValidator *PasswordValidator) PolicyViolations(password string, accountType enums.AccountType) []error {
+	policy := passwordValidator.PolicyFor(accountType)
+
+	var violations []error
 	if passwordValidator.CheckAgainstWeakestPasswordsList(password) {
-		return errors.NewCommonPasswordError(400)
+		violations = append(violations, errors.NewCommonPasswordError(400))
 	}
-	if !passwordValidator.CheckLength(password) {
-		return errors.NewInsufficientPasswordLengthError(400)
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		violations = append(violations, errors.NewInsufficientPasswordLengthError(policy.MinLength, 400))
 	}
-	if !passwordValidator.CheckCharacterComplexity(password) {
-		return errors.NewInsufficientPasswordComplexityError(400)
+	if !policy.meetsCharacterClasses(password) {
+		violations = append(violations, errors.NewInsufficientPasswordComplexityError(policy.RequiredClasses(), 400))
 	}
-	return nil
+	return violations
 }
@@ -0,0 +1,22 @@
+package validation
+
+// Words that show up in leaked passwords all the time, matched after undoing common substitutions
+var commonWords = []string{
+	// Password clichés
+	"password", "passwd", "pass", "secret", "secure", "login", "admin", "welcome", "letmein", "access",
+	"master", "default", "changeme", "trustno", "iloveyou", "love", "lovely", "hello", "whatever", "qwerty",
+	"test", "guest", "user", "root", "super", "superman", "batman", "spiderman", "starwars", "pokemon",
+	"dragon", "monkey", "shadow", "sunshine", "princess", "freedom", "flower", "cookie", "cheese", "chocolate",
+	"football", "baseball", "soccer", "hockey", "basketball", "tennis", "golf", "jordan", "michael", "charlie",
+	"thomas", "daniel", "jessica", "ashley", "jennifer", "hunter", "ranger", "killer", "tigger", "buster",
+	"pepper", "ginger", "maggie", "summer", "winter", "spring", "autumn", "purple", "orange", "yellow",
+	"silver", "golden", "diamond", "angel", "heaven", "magic", "money", "happy", "family", "friend",
+	"friends", "forever", "computer", "internet", "matrix", "ninja", "pirate", "samsung", "apple", "google",
+	"private", "company", "office", "work", "home", "house", "world", "music", "guitar",
+	// Months and days
+	"january", "february", "march", "april", "june", "july", "august", "september", "october", "november",
+	"december", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+	// Words related to this service
+	"flyhorizons", "horizon", "horizons", "flight", "flights", "airline", "airport", "travel", "plane", "pilot",
+	"holiday", "vacation", "ticket", "booking", "fontys",
+}
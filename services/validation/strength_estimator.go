@@ -0,0 +1,358 @@
+package validation
+
+import (
+	"flyhorizons-userservice/models"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	SuggestionLength     = "Use a longer password, a few unrelated words are easy to remember and hard to guess"
+	SuggestionDictionary = "Avoid common words, also when letters are replaced by look-alikes such as '@' for 'a'"
+	SuggestionSequence   = "Avoid keyboard patterns and sequences such as 'qwerty', 'abcd' or '1234'"
+	SuggestionRepeat     = "Avoid repeated characters and words such as 'aaa' or 'abcabc'"
+	SuggestionDate       = "Avoid dates and years, they are often easy to find out"
+	SuggestionPersonal   = "Don't use your name or email address in your password"
+	SuggestionMoreWords  = "Add another word or two, uncommon words are better"
+)
+
+// Longer passwords are not matched against patterns, matching grows quadratically with the length
+const MaxEstimatedPasswordLength = 256
+
+// Patterns are matched in the order of this list, characters can only belong to one pattern
+var keyboardSequences = []string{
+	"qwertyuiop", "asdfghjkl", "zxcvbnm", "azertyuiop", "qsdfghjklm", "wxcvbn",
+	"1234567890", "abcdefghijklmnopqrstuvwxyz",
+}
+
+var (
+	fullDatePattern = regexp.MustCompile(`\d{1,2}[-/.]\d{1,2}[-/.]\d{2,4}|\d{8}|\d{6}`)
+	yearPattern     = regexp.MustCompile(`(19|20)\d{2}`)
+)
+
+// Common substitutions, undone before looking for words
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i")
+
+type PasswordStrength struct {
+	Score       int     // 0 (very weak) to 4 (very strong)
+	Entropy     float64 // Estimated number of bits an attacker has to guess
+	Suggestions []string
+}
+
+// Estimates how hard a password is to guess by attackers that try words, patterns and the personal
+// information of the user before falling back to brute force
+type StrengthEstimator struct {
+	dictionary    map[string]struct{}
+	maxWordLength int
+}
+
+func NewStrengthEstimator() *StrengthEstimator {
+	estimator := &StrengthEstimator{
+		dictionary: make(map[string]struct{}),
+	}
+	for _, word := range commonWords {
+		estimator.dictionary[word] = struct{}{}
+		estimator.maxWordLength = max(estimator.maxWordLength, utf8.RuneCountInString(word))
+	}
+	return estimator
+}
+
+type strengthEstimate struct {
+	password    []rune
+	lower       []rune
+	normalized  []rune
+	covered     []bool
+	patternBits float64
+	suggestions []string
+}
+
+func (estimator *StrengthEstimator) Estimate(password string, user models.User) PasswordStrength {
+	// Only the length counts, which is far beyond what an attacker can brute force
+	if length := utf8.RuneCountInString(password); length > MaxEstimatedPasswordLength {
+		entropy := float64(length) * math.Log2(float64(characterSetSize(password)))
+		return PasswordStrength{Score: scoreFromEntropy(entropy), Entropy: math.Round(entropy*10) / 10, Suggestions: []string{}}
+	}
+
+	lower := strings.ToLower(password)
+	estimate := &strengthEstimate{
+		password:   []rune(password),
+		lower:      []rune(lower),
+		normalized: []rune(leetReplacer.Replace(lower)),
+	}
+	estimate.covered = make([]bool, len(estimate.password))
+
+	estimator.matchWords(estimate, personalTokens(user), 1, SuggestionPersonal)
+	estimator.matchWords(estimate, estimator.dictionary, math.Log2(float64(len(estimator.dictionary))), SuggestionDictionary)
+	matchDates(estimate, lower)
+	matchSequences(estimate)
+	matchRepeats(estimate)
+
+	// Characters outside of any pattern have to be brute forced
+	bruteForceBits := math.Log2(float64(characterSetSize(password)))
+	entropy := estimate.patternBits
+	for _, covered := range estimate.covered {
+		if !covered {
+			entropy += bruteForceBits
+		}
+	}
+
+	strength := PasswordStrength{
+		Score:       scoreFromEntropy(entropy),
+		Entropy:     math.Round(entropy*10) / 10,
+		Suggestions: estimate.suggestions,
+	}
+	if len(estimate.password) < 12 {
+		strength.Suggestions = appendSuggestion(strength.Suggestions, SuggestionLength)
+	}
+	if strength.Score < 3 && len(strength.Suggestions) == 0 {
+		strength.Suggestions = appendSuggestion(strength.Suggestions, SuggestionMoreWords)
+	}
+	if strength.Suggestions == nil {
+		strength.Suggestions = []string{}
+	}
+	return strength
+}
+
+// Roughly the scale of zxcvbn, a score of 3 or more withstands an offline attack on a slow hash
+func scoreFromEntropy(entropy float64) int {
+	switch {
+	case entropy < 28:
+		return 0
+	case entropy < 40:
+		return 1
+	case entropy < 55:
+		return 2
+	case entropy < 70:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Marks the characters from start to end as part of a pattern, unless some already belong to another one
+func (estimate *strengthEstimate) cover(start int, end int, bits float64, suggestion string) bool {
+	for i := start; i < end; i++ {
+		if estimate.covered[i] {
+			return false
+		}
+	}
+	for i := start; i < end; i++ {
+		estimate.covered[i] = true
+	}
+	estimate.patternBits += bits
+	estimate.suggestions = appendSuggestion(estimate.suggestions, suggestion)
+	return true
+}
+
+// Longest words first, every word costs the attacker bits for picking it and for its capitalization
+func (estimator *StrengthEstimator) matchWords(estimate *strengthEstimate, words map[string]struct{}, wordBits float64, suggestion string) {
+	if len(words) == 0 || len(estimate.normalized) != len(estimate.password) {
+		return
+	}
+
+	maxLength := estimator.maxWordLength
+	for word := range words {
+		maxLength = max(maxLength, utf8.RuneCountInString(word))
+	}
+
+	for start := 0; start < len(estimate.normalized); start++ {
+		for end := min(len(estimate.normalized), start+maxLength); end-start >= 3; end-- {
+			if _, found := words[string(estimate.normalized[start:end])]; !found {
+				continue
+			}
+			bits := wordBits
+			if string(estimate.lower[start:end]) != string(estimate.password[start:end]) {
+				bits++ // Capitalized
+			}
+			if string(estimate.lower[start:end]) != string(estimate.normalized[start:end]) {
+				bits++ // Substitutions
+			}
+			if estimate.cover(start, end, bits, suggestion) {
+				start = end - 1
+				break
+			}
+		}
+	}
+}
+
+func matchDates(estimate *strengthEstimate, lower string) {
+	for _, match := range fullDatePattern.FindAllStringIndex(lower, -1) {
+		if isDate(lower[match[0]:match[1]]) {
+			start, end := runeIndex(lower, match[0]), runeIndex(lower, match[1])
+			estimate.cover(start, end, math.Log2(365*200), SuggestionDate)
+		}
+	}
+	for _, match := range yearPattern.FindAllStringIndex(lower, -1) {
+		start, end := runeIndex(lower, match[0]), runeIndex(lower, match[1])
+		estimate.cover(start, end, math.Log2(200), SuggestionDate)
+	}
+}
+
+func isDate(value string) bool {
+	if parts := strings.FieldsFunc(value, func(char rune) bool { return !unicode.IsDigit(char) }); len(parts) == 3 {
+		numbers := make([]int, len(parts))
+		for i, part := range parts {
+			numbers[i], _ = strconv.Atoi(part)
+		}
+		// Day and month in either order, optionally preceded by the year
+		isDayAndMonth := func(first int, second int) bool {
+			return first >= 1 && second >= 1 && (first <= 31 && second <= 12 || first <= 12 && second <= 31)
+		}
+		return isDayAndMonth(numbers[0], numbers[1]) || len(parts[0]) == 4 && isDayAndMonth(numbers[1], numbers[2])
+	}
+
+	for _, layout := range []string{"02012006", "01022006", "20060102", "020106", "010206", "060102"} {
+		if len(layout) != len(value) {
+			continue
+		}
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Runs of at least 4 neighbours on a keyboard row or in the alphabet, in either direction
+func matchSequences(estimate *strengthEstimate) {
+	for _, sequence := range keyboardSequences {
+		positions := make(map[rune]int)
+		for i, char := range sequence {
+			positions[char] = i
+		}
+
+		for start := 0; start < len(estimate.lower)-1; {
+			end := start + 1
+			first, inSequence := positions[estimate.lower[start]]
+			second, nextInSequence := positions[estimate.lower[end]]
+			direction := second - first
+			if inSequence && nextInSequence && (direction == 1 || direction == -1) {
+				for end+1 < len(estimate.lower) {
+					next, found := positions[estimate.lower[end+1]]
+					if !found || next-positions[estimate.lower[end]] != direction {
+						break
+					}
+					end++
+				}
+				if end-start+1 >= 4 {
+					bits := math.Log2(float64(len(keyboardSequences)*2*len(sequence))) + math.Log2(float64(end-start+1))
+					estimate.cover(start, end+1, bits, SuggestionSequence)
+				}
+			}
+			start = end
+		}
+	}
+}
+
+// Repeated characters ("aaa") and repeated chunks ("abcabc"), the repetitions cost almost nothing.
+// The chunk that covers the most characters wins, the shortest one on a tie.
+func matchRepeats(estimate *strengthEstimate) {
+	runes := estimate.lower
+	matches := repeatMatches(runes)
+	for start := 0; start < len(runes); start++ {
+		bestLength, bestCount := 0, 0
+		for length := 1; start+2*length <= len(runes); length++ {
+			// Every character up to the end of the repetitions equals the one a chunk earlier
+			count := 1 + matches[length][start]/length
+			if count >= 2 && count*length >= 3 && count*length > bestCount*bestLength {
+				bestLength, bestCount = length, count
+			}
+		}
+		if bestCount == 0 {
+			continue
+		}
+
+		chunk := string(runes[start : start+bestLength])
+		bits := float64(bestLength)*math.Log2(float64(characterSetSize(chunk))) + math.Log2(float64(bestCount))
+		if estimate.cover(start, start+bestCount*bestLength, bits, SuggestionRepeat) {
+			start += bestCount*bestLength - 1
+		}
+	}
+}
+
+// For every chunk length, the number of characters from each position on that equal the character
+// one chunk length further, so the repetitions of any chunk are counted without comparing strings
+func repeatMatches(runes []rune) [][]int {
+	matches := make([][]int, len(runes)/2+1)
+	for length := 1; length < len(matches); length++ {
+		matches[length] = make([]int, len(runes)+1)
+		for i := len(runes) - length - 1; i >= 0; i-- {
+			if runes[i] == runes[i+length] {
+				matches[length][i] = matches[length][i+1] + 1
+			}
+		}
+	}
+	return matches
+}
+
+// Parts of the name and email address that are long enough to be meaningful
+func personalTokens(user models.User) map[string]struct{} {
+	tokens := make(map[string]struct{})
+	add := func(value string) {
+		value = leetReplacer.Replace(strings.ToLower(value))
+		if utf8.RuneCountInString(value) >= 3 {
+			tokens[value] = struct{}{}
+		}
+	}
+
+	isSeparator := func(char rune) bool { return !unicode.IsLetter(char) && !unicode.IsDigit(char) }
+	for _, part := range strings.FieldsFunc(user.FullName, isSeparator) {
+		add(part)
+	}
+
+	localPart, domain, _ := strings.Cut(user.Email, "@")
+	add(localPart)
+	for _, part := range strings.FieldsFunc(localPart, isSeparator) {
+		add(part)
+	}
+	if labels := strings.Split(domain, "."); len(labels) > 1 {
+		add(labels[len(labels)-2])
+	}
+	return tokens
+}
+
+func characterSetSize(password string) int {
+	var hasUpper, hasLower, hasNumber, hasSymbol, hasOther bool
+	for _, char := range password {
+		switch {
+		case char > unicode.MaxASCII:
+			hasOther = true
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsNumber(char):
+			hasNumber = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	size := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{hasUpper, 26}, {hasLower, 26}, {hasNumber, 10}, {hasSymbol, 33}, {hasOther, 100}} {
+		if class.present {
+			size += class.size
+		}
+	}
+	return max(size, 1)
+}
+
+func runeIndex(value string, byteIndex int) int {
+	return utf8.RuneCountInString(value[:byteIndex])
+}
+
+func appendSuggestion(suggestions []string, suggestion string) []string {
+	for _, existing := range suggestions {
+		if existing == suggestion {
+			return suggestions
+		}
+	}
+	return append(suggestions, suggestion)
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/interfaces"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	webAuthnChallengeLifetime = 5 * time.Minute
+	webAuthnCeremonyRegister  = "registration"
+	webAuthnCeremonyLogin     = "login"
+)
+
+// Relying party of the passkeys, the RP ID is the domain passkeys are bound to
+type WebAuthnConfig struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+// Reads WEBAUTHN_RP_ID, WEBAUTHN_RP_NAME and the comma separated WEBAUTHN_ORIGINS of the frontend
+func LoadWebAuthnConfigFromEnv() WebAuthnConfig {
+	config := WebAuthnConfig{
+		RPID:    os.Getenv("WEBAUTHN_RP_ID"),
+		RPName:  os.Getenv("WEBAUTHN_RP_NAME"),
+		Origins: strings.FieldsFunc(os.Getenv("WEBAUTHN_ORIGINS"), func(r rune) bool { return r == ',' || r == ' ' }),
+	}
+	if config.RPID == "" {
+		config.RPID = "localhost"
+	}
+	if config.RPName == "" {
+		config.RPName = "FlyHorizons"
+	}
+	if len(config.Origins) == 0 {
+		config.Origins = []string{"http://localhost:3000"}
+	}
+	return config
+}
+
+type WebAuthnService struct {
+	webAuthnRepo interfaces.WebAuthnRepository
+	userRepo     interfaces.UserRepository
+	config       WebAuthnConfig
+}
+
+var _ interfaces.WebAuthnService = (*WebAuthnService)(nil)
+
+func NewWebAuthnService(webAuthnRepo interfaces.WebAuthnRepository, userRepo interfaces.UserRepository, config WebAuthnConfig) *WebAuthnService {
+	return &WebAuthnService{
+		webAuthnRepo: webAuthnRepo,
+		userRepo:     userRepo,
+		config:       config,
+	}
+}
+
+// Returns the options for navigator.credentials.create(), passkeys the user already has are excluded
+func (service *WebAuthnService) BeginRegistration(userID int) (*response.PasskeyRegistrationOptionsResponse, error) {
+	accountEntity := service.userRepo.GetByID(userID)
+	if accountEntity.ID == 0 {
+		return nil, errors.NewUserNotFoundError(userID, 404)
+	}
+
+	challenge, err := service.createChallenge(userID, webAuthnCeremonyRegister)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeCredentials := []response.PasskeyCredentialDescriptor{}
+	for _, credential := range service.webAuthnRepo.GetCredentialsByUserID(userID) {
+		excludeCredentials = append(excludeCredentials, response.PasskeyCredentialDescriptor{Type: "public-key", ID: credential.CredentialID})
+	}
+
+	return &response.PasskeyRegistrationOptionsResponse{
+		Challenge:    challenge,
+		RelyingParty: response.PasskeyRelyingParty{ID: service.config.RPID, Name: service.config.RPName},
+		User: response.PasskeyUser{
+			ID:          userHandle(userID),
+			Name:        accountEntity.Email,
+			DisplayName: accountEntity.FullName,
+		},
+		PubKeyCredParams: []response.PasskeyCredentialParameters{
+			{Type: "public-key", Algorithm: authentication.COSEAlgorithmES256},
+			{Type: "public-key", Algorithm: authentication.COSEAlgorithmRS256},
+		},
+		Timeout:            int(webAuthnChallengeLifetime.Milliseconds()),
+		ExcludeCredentials: excludeCredentials,
+		// Discoverable credentials let passengers sign in without entering their email
+		AuthenticatorSelection: response.PasskeyAuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+func (service *WebAuthnService) FinishRegistration(userID int, registrationRequest request.PasskeyRegistrationRequest) (*response.PasskeyResponse, error) {
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(registrationRequest.Response.ClientDataJSON)
+	if err != nil {
+		return nil, errors.NewInvalidPasskeyError(400)
+	}
+	rawAttestationObject, err := base64.RawURLEncoding.DecodeString(registrationRequest.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.NewInvalidPasskeyError(400)
+	}
+
+	if _, err := service.verifyClientData(clientDataJSON, "webauthn.create", webAuthnCeremonyRegister, userID); err != nil {
+		return nil, err
+	}
+
+	attestation, err := authentication.ParseAttestationObject(rawAttestationObject)
+	if err != nil {
+		return nil, errors.NewInvalidPasskeyError(400)
+	}
+	if !service.verifyAuthenticatorData(attestation.AuthData) {
+		return nil, errors.NewInvalidPasskeyError(400)
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := attestation.Verify(clientDataHash[:]); err != nil {
+		return nil, errors.NewInvalidPasskeyError(400)
+	}
+
+	// Only algorithms offered in the registration options are accepted
+	if _, _, err := authentication.ParseCOSEPublicKey(attestation.AuthData.CredentialPublicKey); err != nil {
+		return nil, errors.NewInvalidPasskeyError(400)
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(attestation.AuthData.CredentialID)
+	if credentialID != registrationRequest.ID || service.webAuthnRepo.GetCredentialByCredentialID(credentialID).ID != 0 {
+		return nil, errors.NewInvalidPasskeyError(400)
+	}
+
+	name := registrationRequest.Name
+	if name == "" {
+		name = "Passkey"
+	}
+
+	credential := service.webAuthnRepo.CreateCredential(entities.WebAuthnCredentialEntity{
+		UserID:       userID,
+		CredentialID: credentialID,
+		PublicKey:    attestation.AuthData.CredentialPublicKey,
+		SignCount:    int64(attestation.AuthData.SignCount),
+		Name:         name,
+		CreatedAt:    time.Now(),
+	})
+
+	log.Printf(
+		"Successfully registered passkey:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	passkey := convertCredentialToPasskey(credential)
+	return &passkey, nil
+}
+
+// Returns the options for navigator.credentials.get(), the browser offers every passkey of this relying party
+func (service *WebAuthnService) BeginLogin() (*response.PasskeyLoginOptionsResponse, error) {
+	challenge, err := service.createChallenge(0, webAuthnCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.PasskeyLoginOptionsResponse{
+		Challenge:        challenge,
+		RelyingPartyID:   service.config.RPID,
+		Timeout:          int(webAuthnChallengeLifetime.Milliseconds()),
+		UserVerification: "required",
+	}, nil
+}
+
+// Verifies an assertion and returns the user the passkey belongs to
+func (service *WebAuthnService) FinishLogin(loginRequest request.PasskeyLoginRequest) (int, error) {
+	credential := service.webAuthnRepo.GetCredentialByCredentialID(loginRequest.ID)
+	if credential.ID == 0 {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(loginRequest.Response.ClientDataJSON)
+	if err != nil {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+	rawAuthData, err := base64.RawURLEncoding.DecodeString(loginRequest.Response.AuthenticatorData)
+	if err != nil {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(loginRequest.Response.Signature)
+	if err != nil {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+
+	// The user handle is optional, but has to belong to the owner of the passkey when it is sent
+	if loginRequest.Response.UserHandle != "" && loginRequest.Response.UserHandle != userHandle(credential.UserID) {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+
+	if _, err := service.verifyClientData(clientDataJSON, "webauthn.get", webAuthnCeremonyLogin, 0); err != nil {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+
+	authData, err := authentication.ParseAuthenticatorData(rawAuthData)
+	if err != nil || !service.verifyAuthenticatorData(authData) {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := authentication.VerifyAssertionSignature(credential.PublicKey, rawAuthData, clientDataHash[:], signature); err != nil {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+
+	// Authenticators that count signatures have to increase the counter, otherwise the passkey may have been cloned
+	signCount := int64(authData.SignCount)
+	if (signCount != 0 || credential.SignCount != 0) && signCount <= credential.SignCount {
+		log.Printf(
+			"Passkey signature counter did not increase, possible cloned authenticator:\n  User ID: %v\n  Timestamp: %s",
+			credential.UserID,
+			time.Now().Format(time.RFC3339),
+		)
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+	if !service.webAuthnRepo.UpdateSignCount(credential.ID, credential.SignCount, signCount) {
+		return 0, errors.NewInvalidPasskeyError(401)
+	}
+
+	return credential.UserID, nil
+}
+
+func (service *WebAuthnService) Credentials(userID int) []response.PasskeyResponse {
+	passkeys := []response.PasskeyResponse{}
+	for _, credential := range service.webAuthnRepo.GetCredentialsByUserID(userID) {
+		passkeys = append(passkeys, convertCredentialToPasskey(credential))
+	}
+	return passkeys
+}
+
+func (service *WebAuthnService) DeleteCredential(userID int, credentialID string) error {
+	if !service.webAuthnRepo.DeleteCredential(userID, credentialID) {
+		return errors.NewPasskeyNotFoundError(404)
+	}
+
+	log.Printf(
+		"Successfully removed passkey:\n  User ID: %v\n  Timestamp: %s",
+		userID,
+		time.Now().Format(time.RFC3339),
+	)
+
+	return nil
+}
+
+func (service *WebAuthnService) createChallenge(userID int, ceremony string) (string, error) {
+	challenge, err := authentication.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	// Only the hash is stored, the browser returns the raw challenge in the client data
+	service.webAuthnRepo.CreateChallenge(entities.WebAuthnChallengeEntity{
+		ChallengeHash: authentication.HashOpaqueToken(challenge),
+		UserID:        userID,
+		Ceremony:      ceremony,
+		ExpiresAt:     time.Now().Add(webAuthnChallengeLifetime),
+		CreatedAt:     time.Now(),
+	})
+
+	return challenge, nil
+}
+
+// Checks the type, origin and challenge of the client data and consumes the challenge
+func (service *WebAuthnService) verifyClientData(clientDataJSON []byte, clientDataType string, ceremony string, userID int) (authentication.ClientData, error) {
+	clientData, err := authentication.ParseClientData(clientDataJSON)
+	if err != nil || clientData.Type != clientDataType || clientData.CrossOrigin || !slices.Contains(service.config.Origins, clientData.Origin) {
+		return authentication.ClientData{}, errors.NewInvalidPasskeyError(400)
+	}
+
+	// Challenge is unknown, used, expired or was issued for another ceremony or user
+	challenge := service.webAuthnRepo.GetChallengeByHash(authentication.HashOpaqueToken(clientData.Challenge))
+	if challenge.ID == 0 || challenge.UsedAt != nil || time.Now().After(challenge.ExpiresAt) || challenge.Ceremony != ceremony || challenge.UserID != userID {
+		return authentication.ClientData{}, errors.NewInvalidPasskeyError(400)
+	}
+
+	// A challenge can only be used once
+	if !service.webAuthnRepo.MarkChallengeAsUsed(challenge.ID) {
+		return authentication.ClientData{}, errors.NewInvalidPasskeyError(400)
+	}
+
+	return clientData, nil
+}
+
+// Passkeys replace the password and the second factor, so user verification is required
+func (service *WebAuthnService) verifyAuthenticatorData(authData authentication.AuthenticatorData) bool {
+	return authentication.MatchesRPIDHash(authData, service.config.RPID) && authData.UserPresent() && authData.UserVerified()
+}
+
+// Opaque handle that identifies the user to the authenticator without exposing the email address
+func userHandle(userID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(userID)))
+}
+
+func convertCredentialToPasskey(credential entities.WebAuthnCredentialEntity) response.PasskeyResponse {
+	return response.PasskeyResponse{
+		CredentialID: credential.CredentialID,
+		Name:         credential.Name,
+		CreatedAt:    credential.CreatedAt,
+		LastUsedAt:   credential.LastUsedAt,
+	}
+}
@@ -3,8 +3,201 @@ CREATE TABLE Account (
 	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
 	FullName NVARCHAR(50) NOT NULL,
 	Email NVARCHAR(100) NOT NULL,
+	EmailVerified BIT NOT NULL DEFAULT 0,
 	AccountType INT NOT NULL,
 	Password NVARCHAR(500) NOT NULL,
 	CreatedAt DATETIME NOT NULL,
-	LastLogin DATETIME NOT NULL
+	LastLogin DATETIME NOT NULL,
+	Version INT NOT NULL DEFAULT 1
+);
+
+-- Backfill for databases created before email verification, existing accounts keep signing in as verified
+IF COL_LENGTH('Account', 'EmailVerified') IS NULL
+BEGIN
+	ALTER TABLE Account ADD EmailVerified BIT NOT NULL DEFAULT 0;
+	EXEC('UPDATE Account SET EmailVerified = 1');
+END;
+
+-- Backfill for databases created before optimistic locking
+IF COL_LENGTH('Account', 'Version') IS NULL
+	ALTER TABLE Account ADD Version INT NOT NULL DEFAULT 1;
+
+-- Refresh Tokens (only the SHA-256 hash of the token is stored)
+CREATE TABLE RefreshToken (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	FamilyID NVARCHAR(64) NOT NULL,
+	TokenHash NVARCHAR(64) NOT NULL UNIQUE,
+	AuthMethods NVARCHAR(100) NOT NULL DEFAULT '',
+	ExpiresAt DATETIME NOT NULL,
+	UsedAt DATETIME NULL,
+	RevokedAt DATETIME NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+CREATE INDEX IX_RefreshToken_FamilyID ON RefreshToken(FamilyID);
+
+-- Revoked access tokens, kept until the token would have expired
+CREATE TABLE RevokedToken (
+	JTI NVARCHAR(64) PRIMARY KEY NOT NULL,
+	UserID INT NOT NULL,
+	ExpiresAt DATETIME NOT NULL,
+	RevokedAt DATETIME NOT NULL
+);
+
+-- Tokens of the user issued before RevokedBefore are revoked
+CREATE TABLE SessionRevocation (
+	UserID INT PRIMARY KEY NOT NULL,
+	RevokedBefore DATETIME NOT NULL,
+	ExpiresAt DATETIME NOT NULL
+);
+
+-- OAuth clients for service-to-service authentication (secrets are hashed with bcrypt)
+CREATE TABLE OAuthClient (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	ClientID NVARCHAR(64) NOT NULL UNIQUE,
+	Name NVARCHAR(100) NOT NULL,
+	SecretHash NVARCHAR(500) NOT NULL,
+	Scopes NVARCHAR(500) NOT NULL,
+	RedirectURIs NVARCHAR(2000) NOT NULL DEFAULT '',
+	Public BIT NOT NULL DEFAULT 0,
+	CreatedAt DATETIME NOT NULL
+);
+
+-- Authorization codes of the authorization code flow (only the SHA-256 hash of the code is stored)
+CREATE TABLE AuthorizationCode (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	CodeHash NVARCHAR(64) NOT NULL UNIQUE,
+	ClientID NVARCHAR(64) NOT NULL,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	RedirectURI NVARCHAR(500) NOT NULL,
+	Scope NVARCHAR(500) NOT NULL,
+	CodeChallenge NVARCHAR(128) NOT NULL,
+	Nonce NVARCHAR(500) NULL,
+	AuthTime DATETIME NOT NULL,
+	ExpiresAt DATETIME NOT NULL,
+	UsedAt DATETIME NULL,
+	AccessTokenID NVARCHAR(64) NULL -- Revoked when the code is reused
+);
+
+-- TOTP factors, only enabled factors are required at login
+CREATE TABLE MFAFactor (
+	UserID INT PRIMARY KEY NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	Secret NVARCHAR(64) NOT NULL,
+	Enabled BIT NOT NULL DEFAULT 0,
+	LastUsedStep BIGINT NOT NULL DEFAULT 0,
+	CreatedAt DATETIME NOT NULL,
+	EnabledAt DATETIME NULL
+);
+
+-- MFA challenges issued after the password check (only the SHA-256 hash of the token is stored)
+CREATE TABLE MFAChallenge (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	TokenHash NVARCHAR(64) NOT NULL UNIQUE,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	Attempts INT NOT NULL DEFAULT 0,
+	ExpiresAt DATETIME NOT NULL,
+	UsedAt DATETIME NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+-- Single-use MFA recovery codes (only the SHA-256 hash of the code is stored)
+CREATE TABLE MFARecoveryCode (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	CodeHash NVARCHAR(64) NOT NULL,
+	UsedAt DATETIME NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+CREATE INDEX IX_MFARecoveryCode_UserID ON MFARecoveryCode(UserID);
+
+-- WebAuthn credentials (passkeys), the public key is stored in COSE format
+CREATE TABLE WebAuthnCredential (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	CredentialID VARCHAR(1400) NOT NULL UNIQUE, -- Base64url encoded, at most 1023 bytes before encoding
+	PublicKey VARBINARY(MAX) NOT NULL,
+	SignCount BIGINT NOT NULL DEFAULT 0,
+	Name NVARCHAR(100) NOT NULL DEFAULT '',
+	CreatedAt DATETIME NOT NULL,
+	LastUsedAt DATETIME NULL
+);
+
+CREATE INDEX IX_WebAuthnCredential_UserID ON WebAuthnCredential(UserID);
+
+-- WebAuthn ceremony challenges (only the SHA-256 hash of the challenge is stored), UserID is 0 for login challenges
+CREATE TABLE WebAuthnChallenge (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	ChallengeHash NVARCHAR(64) NOT NULL UNIQUE,
+	UserID INT NOT NULL DEFAULT 0,
+	Ceremony NVARCHAR(20) NOT NULL,
+	ExpiresAt DATETIME NOT NULL,
+	UsedAt DATETIME NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+-- Password reset tokens (only the SHA-256 hash of the token is stored)
+CREATE TABLE PasswordResetToken (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	TokenHash NVARCHAR(64) NOT NULL UNIQUE,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	ExpiresAt DATETIME NOT NULL,
+	UsedAt DATETIME NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+-- Email Verification Tokens (only the SHA-256 hash of the token is stored)
+CREATE TABLE EmailVerificationToken (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	TokenHash NVARCHAR(64) NOT NULL UNIQUE,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	ExpiresAt DATETIME NOT NULL,
+	UsedAt DATETIME NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+CREATE INDEX IX_EmailVerificationToken_UserID ON EmailVerificationToken(UserID);
+
+-- Email Change Requests (only the SHA-256 hashes of the confirm and revert tokens are stored)
+CREATE TABLE EmailChangeRequest (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	OldEmail NVARCHAR(100) NOT NULL,
+	NewEmail NVARCHAR(100) NOT NULL,
+	ConfirmTokenHash NVARCHAR(64) NOT NULL UNIQUE,
+	RevertTokenHash NVARCHAR(64) NOT NULL UNIQUE,
+	ExpiresAt DATETIME NOT NULL,
+	RevertExpiresAt DATETIME NOT NULL,
+	ConfirmedAt DATETIME NULL,
+	RevertedAt DATETIME NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+CREATE INDEX IX_EmailChangeRequest_UserID ON EmailChangeRequest(UserID);
+
+-- Failed login attempts and temporary lockouts
+CREATE TABLE AccountLockout (
+	UserID INT PRIMARY KEY NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	FailedAttempts INT NOT NULL DEFAULT 0,
+	LastFailedAt DATETIME NULL,
+	LockedUntil DATETIME NULL
+);
+
+-- Hashes of previous passwords, to prevent reuse
+CREATE TABLE PasswordHistory (
+	ID INT IDENTITY(1,1) PRIMARY KEY NOT NULL,
+	UserID INT NOT NULL FOREIGN KEY REFERENCES Account(ID) ON DELETE CASCADE,
+	PasswordHash NVARCHAR(500) NOT NULL,
+	CreatedAt DATETIME NOT NULL
+);
+
+CREATE INDEX IX_PasswordHistory_UserID ON PasswordHistory(UserID);
+
+-- Generated signing keys shared by every instance, the newest key is active (private keys are stored as PKCS #8 PEM)
+CREATE TABLE SigningKey (
+	KeyID NVARCHAR(64) PRIMARY KEY NOT NULL,
+	Algorithm NVARCHAR(10) NOT NULL,
+	PrivateKey NVARCHAR(MAX) NOT NULL,
+	ActivatedAt DATETIME NOT NULL
 );
\ No newline at end of file
@@ -3,8 +3,9 @@ package endtoend
 import (
 	"bytes"
 	"encoding/json"
-	"flyhorizons-userservice/models"
 	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
 	"flyhorizons-userservice/repositories"
 	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/routes"
@@ -23,6 +24,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
@@ -72,8 +74,8 @@ func NewTestUserRepository() *repositories.UserRepository {
 func setupUsers(repo *repositories.UserRepository) {
 	// Users
 	testUsers := []entities.UserEntity{
-		{ID: 1, FullName: "John Doe", Email: "john@doe.it", AccountType: 1, Password: "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
-		{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: 0, Password: "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+		{ID: 1, FullName: "John Doe", Email: "john@doe.it", AccountType: 1, Password: "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC), Version: 1},
+		{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: 0, Password: "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC), Version: 1},
 	}
 
 	// Add users to the test database
@@ -87,8 +89,10 @@ func setupUsers(repo *repositories.UserRepository) {
 func setupUserService(repo *repositories.UserRepository) *services.UserService {
 	userConverter := converter.UserConverter{}
 	passwordValidator := validation.PasswordValidator{}
-	accountHashing := authentication.NewAccountHashing()
-	return services.NewUserService(repo, accountHashing, passwordValidator, userConverter)
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
+	emailVerificationService := new(mock_repositories.MockEmailVerificationService)
+	emailVerificationService.On("SendVerification", mock.Anything).Return(nil)
+	return services.NewUserService(repo, accountHashing, passwordValidator, userConverter, emailVerificationService)
 }
 
 func setupUserRouter(service services.UserService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
@@ -97,25 +101,37 @@ func setupUserRouter(service services.UserService, gatewayAuthMiddleware *mock_r
 	return router
 }
 
-func getUsers() []models.User {
-	return []models.User{
+func getUserProfiles() []response.UserProfileResponse {
+	return []response.UserProfileResponse{
 		{
 			ID:          1,
 			FullName:    "John Doe",
 			Email:       "john@doe.it",
-			AccountType: 1,
-			Password:    "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", // 1234!
+			AccountType: enums.User,
 		},
 		{
 			ID:          2,
 			FullName:    "Jane Doe",
 			Email:       "jane@doe.nl",
-			AccountType: 0,
-			Password:    "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", // 4321!
+			AccountType: enums.Admin,
 		},
 	}
 }
 
+func getAdminUserResponses() []response.AdminUserResponse {
+	return []response.AdminUserResponse{
+		{ID: 1, FullName: "John Doe", Email: "john@doe.it", AccountType: enums.User, CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+		{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: enums.Admin, CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+	}
+}
+
+// The stored users have real hashes, none of them may end up in a response
+func assertNoPasswordHash(t *testing.T, responseBody string) {
+	assert.NotContains(t, responseBody, "$2a$")
+	assert.NotContains(t, responseBody, "$argon2id$")
+	assert.NotContains(t, responseBody, "password")
+}
+
 // End-to-End Tests
 func TestEndToEndGetAllAsAdminReturnsUsers(t *testing.T) {
 	// Arrange
@@ -126,7 +142,7 @@ func TestEndToEndGetAllAsAdminReturnsUsers(t *testing.T) {
 	userService := setupUserService(userRepo)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
 	bearerToken := "Bearer mocktoken12345"
-	mockUsers := getUsers()
+	mockUsers := getAdminUserResponses()
 	// Setup router
 	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
 
@@ -143,11 +159,12 @@ func TestEndToEndGetAllAsAdminReturnsUsers(t *testing.T) {
 	assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 	// Unmarshal the JSON response
-	var users []models.User
+	var users []response.AdminUserResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &users)
 
 	assert.NoError(t, err)
 	assert.Equal(t, mockUsers, users)
+	assertNoPasswordHash(t, responseRecorder.Body.String())
 }
 
 func TestEndToEndGetAllAsUserReturnsAccessDenied(t *testing.T) {
@@ -184,7 +201,7 @@ func TestEndToEndGetUserByMatchingIDReturnsUser(t *testing.T) {
 	userService := setupUserService(userRepo)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
 	bearerToken := "Bearer mocktoken12345"
-	mockUser := getUsers()[0]
+	mockUser := getUserProfiles()[0]
 	// Setup router
 	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
 
@@ -202,11 +219,13 @@ func TestEndToEndGetUserByMatchingIDReturnsUser(t *testing.T) {
 	assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 	// Unmarshal the JSON response
-	var user models.User
+	var user response.UserProfileResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
 
 	assert.NoError(t, err)
 	assert.Equal(t, mockUser, user)
+	assert.Equal(t, `"1"`, responseRecorder.Header().Get("ETag"))
+	assertNoPasswordHash(t, responseRecorder.Body.String())
 }
 
 func TestEndToEndGetUserByNonMatchingIDReturnsUserNotFoundError(t *testing.T) {
@@ -243,11 +262,10 @@ func TestEndToEndCreateNonExistingUserReturnsNewUser(t *testing.T) {
 	// Setup service
 	userService := setupUserService(userRepo)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
-	mockUser := models.User{
-		FullName:    "Clark Kent",
-		Email:       "clark@torch.com",
-		AccountType: enums.User,
-		Password:    "HashedPasswordABC1234!",
+	mockUser := request.CreateUserRequest{
+		FullName: "Clark Kent",
+		Email:    "clark@torch.com",
+		Password: "HashedPasswordABC1234!",
 	}
 	// Setup router
 	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
@@ -263,15 +281,16 @@ func TestEndToEndCreateNonExistingUserReturnsNewUser(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
 
-	var user models.User
+	var user response.UserProfileResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
 	assert.NoError(t, err)
 	assert.Equal(t, mockUser.FullName, user.FullName)
-	assert.Equal(t, mockUser.AccountType, user.AccountType)
+	assert.Equal(t, enums.User, user.AccountType)
 	assert.Equal(t, mockUser.Email, user.Email)
+	assertNoPasswordHash(t, responseRecorder.Body.String())
 }
 
-func TestEndToEndCreateExistingUserReturnsUserExistsError(t *testing.T) {
+func TestEndToEndCreateUserRequestingAdminAccountCreatesRegularUser(t *testing.T) {
 	// Arrange
 	// Setup repository
 	userRepo := NewTestUserRepository()
@@ -279,7 +298,40 @@ func TestEndToEndCreateExistingUserReturnsUserExistsError(t *testing.T) {
 	// Setup service
 	userService := setupUserService(userRepo)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
-	mockUser := getUsers()[0]
+	// Setup router
+	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
+	// The account type and ID of the request body are ignored
+	requestBody := []byte(`{"id": 2, "full_name": "Lex Luthor", "email": "lex@lexcorp.com", "account_type": 0, "password": "HashedPasswordABC1234!"}`)
+	httpRequest, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(requestBody)) // JSON body
+	httpRequest.Header.Set("Content-Type", "application/json")                        // Set the Content-Type header
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var user response.UserProfileResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.User, user.AccountType)
+	assert.NotEqual(t, 2, user.ID)
+}
+
+func TestEndToEndCreateUserUsingExistingEmailReturnsEmailInUseError(t *testing.T) {
+	// Arrange
+	// Setup repository
+	userRepo := NewTestUserRepository()
+	setupUsers(userRepo)
+	// Setup service
+	userService := setupUserService(userRepo)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockUser := request.CreateUserRequest{
+		FullName: "John Doe",
+		Email:    "john@doe.it",
+		Password: "HashedPasswordABC1234!",
+	}
 	// Setup router
 	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
 	// Make the JSON to create the user
@@ -330,7 +382,7 @@ func TestEndToEndUpdateUserByMatchingIDReturnsUpdatedUser(t *testing.T) {
 	userService := setupUserService(userRepo)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
 	bearerToken := "Bearer mocktoken12345"
-	mockUser := getUsers()[0]
+	mockUser := request.UpdateUserRequest{ID: 1, FullName: "John Doe"}
 	// Setup router
 	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
 	// Make the JSON to create the airport
@@ -338,6 +390,7 @@ func TestEndToEndUpdateUserByMatchingIDReturnsUpdatedUser(t *testing.T) {
 	httpRequest, _ := http.NewRequest("PUT", "/users/", bytes.NewBuffer(requestBody))
 	httpRequest.Header.Set("Content-Type", "application/json") // Set the Content-Type header
 	httpRequest.Header.Set("Authorization", bearerToken)       // Set the Bearer token
+	httpRequest.Header.Set("If-Match", `"1"`)                  // Set the ETag of the user
 
 	responseRecorder := httptest.NewRecorder()
 
@@ -347,13 +400,14 @@ func TestEndToEndUpdateUserByMatchingIDReturnsUpdatedUser(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
-	var user models.User
+	var user response.UserProfileResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
 	assert.NoError(t, err)
 	assert.Equal(t, mockUser.ID, user.ID)
 	assert.Equal(t, mockUser.FullName, user.FullName)
-	assert.Equal(t, mockUser.AccountType, user.AccountType)
-	assert.Equal(t, mockUser.Email, user.Email)
+	assert.Equal(t, enums.User, user.AccountType)
+	assert.Equal(t, "john@doe.it", user.Email)
+	assertNoPasswordHash(t, responseRecorder.Body.String())
 }
 
 func TestEndToEndUpdateUserByNonMatchingIDReturnsAccessDenied(t *testing.T) {
@@ -365,7 +419,7 @@ func TestEndToEndUpdateUserByNonMatchingIDReturnsAccessDenied(t *testing.T) {
 	userService := setupUserService(userRepo)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 2)
 	bearerToken := "Bearer mocktoken12345"
-	mockUser := getUsers()[0]
+	mockUser := request.UpdateUserRequest{ID: 1, FullName: "John Doe"}
 	// Setup router
 	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
 	// Make the JSON to create the airport
@@ -382,3 +436,69 @@ func TestEndToEndUpdateUserByNonMatchingIDReturnsAccessDenied(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
 }
+
+func TestEndToEndPatchUserOnlyChangesPatchedColumns(t *testing.T) {
+	// Arrange
+	// Setup repository
+	userRepo := NewTestUserRepository()
+	setupUsers(userRepo)
+	storedUser := userRepo.GetByID(1)
+	// Setup service
+	userService := setupUserService(userRepo)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	// Setup router
+	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
+	httpRequest, _ := http.NewRequest("PATCH", "/users/1", bytes.NewBufferString(`{"full_name":"John Smith"}`))
+	httpRequest.Header.Set("Content-Type", "application/merge-patch+json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("If-Match", `"1"`)
+
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, `"2"`, responseRecorder.Header().Get("ETag"))
+	assertNoPasswordHash(t, responseRecorder.Body.String())
+	patchedUser := userRepo.GetByID(1)
+	assert.Equal(t, "John Smith", patchedUser.FullName)
+	assert.Equal(t, storedUser.Password, patchedUser.Password)
+	assert.True(t, storedUser.CreatedAt.Equal(patchedUser.CreatedAt))
+}
+
+func TestEndToEndConcurrentUpdatesRejectTheStaleOne(t *testing.T) {
+	// Arrange
+	// Setup repository
+	userRepo := NewTestUserRepository()
+	setupUsers(userRepo)
+	// Setup service
+	userService := setupUserService(userRepo)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	// Setup router
+	router := setupUserRouter(*userService, mockAPIGatewayMiddleware)
+	// Both requests were made after reading the same version of the user
+	newUpdateRequest := func(fullName string) *http.Request {
+		requestBody, _ := json.Marshal(request.UpdateUserRequest{ID: 1, FullName: fullName})
+		httpRequest, _ := http.NewRequest("PUT", "/users/", bytes.NewBuffer(requestBody))
+		httpRequest.Header.Set("Content-Type", "application/json")
+		httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+		httpRequest.Header.Set("If-Match", `"1"`)
+		return httpRequest
+	}
+
+	firstResponseRecorder := httptest.NewRecorder()
+	secondResponseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(firstResponseRecorder, newUpdateRequest("John Smith"))
+	router.ServeHTTP(secondResponseRecorder, newUpdateRequest("Johnny Doe"))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, firstResponseRecorder.Code)
+	assert.Equal(t, http.StatusPreconditionFailed, secondResponseRecorder.Code)
+	updatedUser := userRepo.GetByID(1)
+	assert.Equal(t, "John Smith", updatedUser.FullName)
+	assert.Equal(t, 2, updatedUser.Version)
+}
@@ -0,0 +1,75 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestAccountLockoutRepository() *repositories.AccountLockoutRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.AccountLockoutEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewAccountLockoutRepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestAccountLockoutRepositoryIncrementCountsPerAccount(t *testing.T) {
+	// Arrange
+	lockoutRepo := NewTestAccountLockoutRepository()
+
+	// Act
+	lockoutRepo.IncrementFailedAttempts(1)
+	lockout := lockoutRepo.IncrementFailedAttempts(1)
+	otherLockout := lockoutRepo.IncrementFailedAttempts(2)
+
+	// Assert
+	assert.Equal(t, 2, lockout.FailedAttempts)
+	assert.NotNil(t, lockout.LastFailedAt)
+	assert.Equal(t, 1, otherLockout.FailedAttempts)
+}
+
+func TestAccountLockoutRepositoryLockResetsCounter(t *testing.T) {
+	// Arrange
+	lockoutRepo := NewTestAccountLockoutRepository()
+	lockoutRepo.IncrementFailedAttempts(1)
+	lockedUntil := time.Now().Add(time.Hour)
+
+	// Act
+	lockoutRepo.Lock(1, lockedUntil)
+
+	// Assert
+	lockout := lockoutRepo.GetByUserID(1)
+	assert.Equal(t, 0, lockout.FailedAttempts)
+	assert.WithinDuration(t, lockedUntil, *lockout.LockedUntil, time.Second)
+}
+
+func TestAccountLockoutRepositoryResetRemovesLockout(t *testing.T) {
+	// Arrange
+	lockoutRepo := NewTestAccountLockoutRepository()
+	lockoutRepo.IncrementFailedAttempts(1)
+	lockoutRepo.Lock(1, time.Now().Add(time.Hour))
+
+	// Act
+	lockoutRepo.Reset(1)
+
+	// Assert
+	lockout := lockoutRepo.GetByUserID(1)
+	assert.Equal(t, 0, lockout.UserID)
+	assert.Nil(t, lockout.LockedUntil)
+}
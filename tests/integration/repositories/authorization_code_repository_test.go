@@ -0,0 +1,75 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestAuthorizationCodeRepository() *repositories.AuthorizationCodeRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.AuthorizationCodeEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewAuthorizationCodeRepository(&baseRepo.BaseRepository)
+}
+
+// Adds an authorization code to the database on every run
+func setupAuthorizationCodes(repo *repositories.AuthorizationCodeRepository) entities.AuthorizationCodeEntity {
+	return repo.Create(entities.AuthorizationCodeEntity{
+		ID:            1,
+		CodeHash:      "hash-1",
+		ClientID:      "partner-app",
+		UserID:        1,
+		RedirectURI:   "https://partner.example/callback",
+		Scope:         "openid",
+		CodeChallenge: "challenge",
+		AuthTime:      time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC),
+		ExpiresAt:     time.Date(2099, time.March, 31, 10, 30, 0, 0, time.UTC),
+	})
+}
+
+// Integration Database Tests
+func TestAuthorizationCodeRepositoryGetByValidCodeHashReturnsCode(t *testing.T) {
+	// Arrange
+	codeRepo := NewTestAuthorizationCodeRepository()
+	testCode := setupAuthorizationCodes(codeRepo)
+
+	// Act
+	code := codeRepo.GetByCodeHash("hash-1")
+
+	// Assert
+	assert.Equal(t, testCode.ID, code.ID)
+	assert.Equal(t, "partner-app", code.ClientID)
+	assert.Nil(t, code.UsedAt)
+}
+
+func TestAuthorizationCodeRepositoryMarkAsUsedOnlySucceedsOnce(t *testing.T) {
+	// Arrange
+	codeRepo := NewTestAuthorizationCodeRepository()
+	testCode := setupAuthorizationCodes(codeRepo)
+
+	// Act
+	first := codeRepo.MarkAsUsed(testCode.ID, "jti-1")
+	second := codeRepo.MarkAsUsed(testCode.ID, "jti-2")
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, second)
+	assert.NotNil(t, codeRepo.GetByCodeHash("hash-1").UsedAt)
+	assert.Equal(t, "jti-1", codeRepo.GetByCodeHash("hash-1").AccessTokenID)
+}
@@ -0,0 +1,77 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestEmailChangeRepository() *repositories.EmailChangeRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.EmailChangeRequestEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewEmailChangeRepository(&baseRepo.BaseRepository)
+}
+
+func getTestEmailChangeRequest(userID int, tokenSuffix string) entities.EmailChangeRequestEntity {
+	return entities.EmailChangeRequestEntity{
+		UserID:           userID,
+		OldEmail:         "john@doe.it",
+		NewEmail:         "john@doe.com",
+		ConfirmTokenHash: "confirm-" + tokenSuffix,
+		RevertTokenHash:  "revert-" + tokenSuffix,
+		ExpiresAt:        time.Now().Add(time.Hour),
+		RevertExpiresAt:  time.Now().Add(24 * time.Hour),
+		CreatedAt:        time.Now(),
+	}
+}
+
+// Integration Database Tests
+func TestEmailChangeRepositoryRevertedChangeCannotBeConfirmed(t *testing.T) {
+	// Arrange
+	changeRepo := NewTestEmailChangeRepository()
+	change := changeRepo.Create(getTestEmailChangeRequest(1, "1"))
+
+	// Act
+	reverted := changeRepo.MarkAsReverted(change.ID)
+	confirmed := changeRepo.MarkAsConfirmed(change.ID)
+	revertedAgain := changeRepo.MarkAsReverted(change.ID)
+
+	// Assert
+	assert.True(t, reverted)
+	assert.False(t, confirmed)
+	assert.False(t, revertedAgain)
+	assert.Nil(t, changeRepo.GetByConfirmTokenHash("confirm-1").ConfirmedAt)
+}
+
+func TestEmailChangeRepositoryCancelPendingKeepsConfirmedChanges(t *testing.T) {
+	// Arrange
+	changeRepo := NewTestEmailChangeRepository()
+	confirmedChange := changeRepo.Create(getTestEmailChangeRequest(1, "1"))
+	changeRepo.MarkAsConfirmed(confirmedChange.ID)
+	changeRepo.Create(getTestEmailChangeRequest(1, "2"))
+	changeRepo.Create(getTestEmailChangeRequest(2, "3"))
+
+	// Act
+	changeRepo.CancelPendingForUser(1)
+
+	// Assert
+	assert.Nil(t, changeRepo.GetByRevertTokenHash("revert-1").RevertedAt)
+	assert.NotNil(t, changeRepo.GetByRevertTokenHash("revert-2").RevertedAt)
+	assert.Nil(t, changeRepo.GetByRevertTokenHash("revert-3").RevertedAt)
+}
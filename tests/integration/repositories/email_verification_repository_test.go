@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestEmailVerificationRepository() *repositories.EmailVerificationRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.EmailVerificationTokenEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewEmailVerificationRepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestEmailVerificationRepositoryMarkAsUsedOnlyOnce(t *testing.T) {
+	// Arrange
+	verificationRepo := NewTestEmailVerificationRepository()
+	token := verificationRepo.Create(entities.EmailVerificationTokenEntity{TokenHash: "hash", UserID: 1, ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()})
+
+	// Act
+	first := verificationRepo.MarkAsUsed(token.ID)
+	replayed := verificationRepo.MarkAsUsed(token.ID)
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, replayed)
+	assert.NotNil(t, verificationRepo.GetByTokenHash("hash").UsedAt)
+}
+
+func TestEmailVerificationRepositoryCountCreatedSinceOnlyCountsRecentTokensOfUser(t *testing.T) {
+	// Arrange
+	verificationRepo := NewTestEmailVerificationRepository()
+	verificationRepo.Create(entities.EmailVerificationTokenEntity{TokenHash: "hash-1", UserID: 1, ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now().Add(-2 * time.Hour)})
+	verificationRepo.Create(entities.EmailVerificationTokenEntity{TokenHash: "hash-2", UserID: 1, ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now().Add(-10 * time.Minute)})
+	verificationRepo.Create(entities.EmailVerificationTokenEntity{TokenHash: "hash-3", UserID: 1, ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()})
+	verificationRepo.Create(entities.EmailVerificationTokenEntity{TokenHash: "hash-4", UserID: 2, ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()})
+
+	// Act
+	count := verificationRepo.CountCreatedSince(1, time.Now().Add(-time.Hour))
+
+	// Assert
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,124 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestMFARepository() *repositories.MFARepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.MFAFactorEntity{}, &entities.MFAChallengeEntity{}, &entities.MFARecoveryCodeEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewMFARepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestMFARepositoryUseTimeStepRejectsReplayedStep(t *testing.T) {
+	// Arrange
+	mfaRepo := NewTestMFARepository()
+	mfaRepo.SaveFactor(entities.MFAFactorEntity{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", Enabled: true, CreatedAt: time.Now()})
+
+	// Act
+	first := mfaRepo.UseTimeStep(1, 100)
+	replayed := mfaRepo.UseTimeStep(1, 100)
+	older := mfaRepo.UseTimeStep(1, 99)
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, replayed)
+	assert.False(t, older)
+	assert.Equal(t, int64(100), mfaRepo.GetFactor(1).LastUsedStep)
+}
+
+func TestMFARepositoryDeleteFactorRemovesFactor(t *testing.T) {
+	// Arrange
+	mfaRepo := NewTestMFARepository()
+	mfaRepo.SaveFactor(entities.MFAFactorEntity{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", Enabled: true, CreatedAt: time.Now()})
+
+	// Act
+	mfaRepo.DeleteFactor(1)
+
+	// Assert
+	assert.Equal(t, entities.MFAFactorEntity{}, mfaRepo.GetFactor(1))
+}
+
+func TestMFARepositoryChallengeCountsAttemptsAndIsSingleUse(t *testing.T) {
+	// Arrange
+	mfaRepo := NewTestMFARepository()
+	challenge := mfaRepo.CreateChallenge(entities.MFAChallengeEntity{TokenHash: "hash-1", UserID: 1, ExpiresAt: time.Now().Add(time.Minute), CreatedAt: time.Now()})
+
+	// Act
+	mfaRepo.IncrementChallengeAttempts(challenge.ID)
+	mfaRepo.IncrementChallengeAttempts(challenge.ID)
+	first := mfaRepo.MarkChallengeAsUsed(challenge.ID)
+	second := mfaRepo.MarkChallengeAsUsed(challenge.ID)
+
+	// Assert
+	storedChallenge := mfaRepo.GetChallengeByTokenHash("hash-1")
+	assert.Equal(t, 2, storedChallenge.Attempts)
+	assert.NotNil(t, storedChallenge.UsedAt)
+	assert.True(t, first)
+	assert.False(t, second)
+}
+
+func TestMFARepositoryCountsFailuresOfRecentChallengesAndRevokesThem(t *testing.T) {
+	// Arrange
+	mfaRepo := NewTestMFARepository()
+	oldChallenge := mfaRepo.CreateChallenge(entities.MFAChallengeEntity{TokenHash: "hash-1", UserID: 1, ExpiresAt: time.Now().Add(-time.Hour), CreatedAt: time.Now().Add(-time.Hour)})
+	firstChallenge := mfaRepo.CreateChallenge(entities.MFAChallengeEntity{TokenHash: "hash-2", UserID: 1, ExpiresAt: time.Now().Add(time.Minute), CreatedAt: time.Now()})
+	secondChallenge := mfaRepo.CreateChallenge(entities.MFAChallengeEntity{TokenHash: "hash-3", UserID: 1, ExpiresAt: time.Now().Add(time.Minute), CreatedAt: time.Now()})
+	mfaRepo.IncrementChallengeAttempts(oldChallenge.ID)
+	mfaRepo.IncrementChallengeAttempts(firstChallenge.ID)
+	mfaRepo.IncrementChallengeAttempts(secondChallenge.ID)
+	mfaRepo.IncrementChallengeAttempts(secondChallenge.ID)
+
+	// Act
+	failures := mfaRepo.CountChallengeFailures(1, time.Now().Add(-time.Minute))
+	mfaRepo.RevokeChallenges(1)
+
+	// Assert
+	assert.Equal(t, 3, failures)
+	assert.Equal(t, 0, mfaRepo.CountChallengeFailures(2, time.Now().Add(-time.Minute)))
+	assert.NotNil(t, mfaRepo.GetChallengeByTokenHash("hash-2").UsedAt)
+	assert.NotNil(t, mfaRepo.GetChallengeByTokenHash("hash-3").UsedAt)
+}
+
+func TestMFARepositoryRecoveryCodesAreSingleUseAndReplaceable(t *testing.T) {
+	// Arrange
+	mfaRepo := NewTestMFARepository()
+	mfaRepo.ReplaceRecoveryCodes(1, []entities.MFARecoveryCodeEntity{
+		{UserID: 1, CodeHash: "hash-1", CreatedAt: time.Now()},
+		{UserID: 1, CodeHash: "hash-2", CreatedAt: time.Now()},
+	})
+
+	// Act
+	first := mfaRepo.UseRecoveryCode(1, "hash-1")
+	reused := mfaRepo.UseRecoveryCode(1, "hash-1")
+	otherUser := mfaRepo.UseRecoveryCode(2, "hash-2")
+	remaining := mfaRepo.CountRecoveryCodes(1)
+	mfaRepo.ReplaceRecoveryCodes(1, []entities.MFARecoveryCodeEntity{{UserID: 1, CodeHash: "hash-3", CreatedAt: time.Now()}})
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, reused)
+	assert.False(t, otherUser)
+	assert.Equal(t, 1, remaining)
+	assert.False(t, mfaRepo.UseRecoveryCode(1, "hash-2"))
+	assert.Equal(t, 1, mfaRepo.CountRecoveryCodes(1))
+}
@@ -0,0 +1,67 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestPasswordHistoryRepository() *repositories.PasswordHistoryRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.PasswordHistoryEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewPasswordHistoryRepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestPasswordHistoryRepositoryGetRecentReturnsNewestFirst(t *testing.T) {
+	// Arrange
+	historyRepo := NewTestPasswordHistoryRepository()
+	now := time.Now()
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 1, PasswordHash: "hash-1", CreatedAt: now.Add(-2 * time.Hour)})
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 1, PasswordHash: "hash-3", CreatedAt: now})
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 1, PasswordHash: "hash-2", CreatedAt: now.Add(-time.Hour)})
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 2, PasswordHash: "other", CreatedAt: now})
+
+	// Act
+	history := historyRepo.GetRecentByUserID(1, 2)
+
+	// Assert
+	assert.Len(t, history, 2)
+	assert.Equal(t, "hash-3", history[0].PasswordHash)
+	assert.Equal(t, "hash-2", history[1].PasswordHash)
+}
+
+func TestPasswordHistoryRepositoryDeleteAllButRecentOnlyAffectsUser(t *testing.T) {
+	// Arrange
+	historyRepo := NewTestPasswordHistoryRepository()
+	now := time.Now()
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 1, PasswordHash: "hash-1", CreatedAt: now.Add(-2 * time.Hour)})
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 1, PasswordHash: "hash-2", CreatedAt: now.Add(-time.Hour)})
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 1, PasswordHash: "hash-3", CreatedAt: now})
+	historyRepo.Create(entities.PasswordHistoryEntity{UserID: 2, PasswordHash: "other", CreatedAt: now.Add(-3 * time.Hour)})
+
+	// Act
+	historyRepo.DeleteAllButRecent(1, 1)
+
+	// Assert
+	history := historyRepo.GetRecentByUserID(1, 10)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "hash-3", history[0].PasswordHash)
+	assert.Len(t, historyRepo.GetRecentByUserID(2, 10), 1)
+}
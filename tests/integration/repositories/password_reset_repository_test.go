@@ -0,0 +1,59 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestPasswordResetRepository() *repositories.PasswordResetRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.PasswordResetTokenEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewPasswordResetRepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestPasswordResetRepositoryMarkAsUsedOnlyOnce(t *testing.T) {
+	// Arrange
+	resetRepo := NewTestPasswordResetRepository()
+	token := resetRepo.Create(entities.PasswordResetTokenEntity{TokenHash: "hash", UserID: 1, ExpiresAt: time.Now().Add(time.Minute), CreatedAt: time.Now()})
+
+	// Act
+	first := resetRepo.MarkAsUsed(token.ID)
+	replayed := resetRepo.MarkAsUsed(token.ID)
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, replayed)
+	assert.NotNil(t, resetRepo.GetByTokenHash("hash").UsedAt)
+}
+
+func TestPasswordResetRepositoryInvalidateAllForUserOnlyAffectsUser(t *testing.T) {
+	// Arrange
+	resetRepo := NewTestPasswordResetRepository()
+	resetRepo.Create(entities.PasswordResetTokenEntity{TokenHash: "hash-1", UserID: 1, ExpiresAt: time.Now().Add(time.Minute), CreatedAt: time.Now()})
+	resetRepo.Create(entities.PasswordResetTokenEntity{TokenHash: "hash-2", UserID: 2, ExpiresAt: time.Now().Add(time.Minute), CreatedAt: time.Now()})
+
+	// Act
+	resetRepo.InvalidateAllForUser(1)
+
+	// Assert
+	assert.NotNil(t, resetRepo.GetByTokenHash("hash-1").UsedAt)
+	assert.Nil(t, resetRepo.GetByTokenHash("hash-2").UsedAt)
+}
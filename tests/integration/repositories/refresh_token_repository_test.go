@@ -0,0 +1,112 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestRefreshTokenRepository() *repositories.RefreshTokenRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.RefreshTokenEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewRefreshTokenRepository(&baseRepo.BaseRepository)
+}
+
+// Adds a refresh token family with two tokens to the database on every run
+func setupRefreshTokens(repo *repositories.RefreshTokenRepository) []entities.RefreshTokenEntity {
+	testRefreshTokens := []entities.RefreshTokenEntity{
+		{ID: 1, UserID: 1, FamilyID: "family-1", TokenHash: "hash-1", ExpiresAt: time.Date(2099, time.March, 31, 10, 30, 0, 0, time.UTC), CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+		{ID: 2, UserID: 1, FamilyID: "family-1", TokenHash: "hash-2", ExpiresAt: time.Date(2099, time.March, 31, 10, 30, 0, 0, time.UTC), CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, refreshToken := range testRefreshTokens {
+		repo.Create(refreshToken)
+	}
+
+	return testRefreshTokens
+}
+
+// Integration Database Tests
+func TestRefreshTokenRepositoryGetByValidTokenHashReturnsToken(t *testing.T) {
+	// Arrange
+	refreshTokenRepo := NewTestRefreshTokenRepository()
+	testRefreshTokens := setupRefreshTokens(refreshTokenRepo)
+
+	// Act
+	refreshToken := refreshTokenRepo.GetByTokenHash("hash-2")
+
+	// Assert
+	assert.Equal(t, testRefreshTokens[1].ID, refreshToken.ID)
+	assert.Equal(t, testRefreshTokens[1].FamilyID, refreshToken.FamilyID)
+}
+
+func TestRefreshTokenRepositoryGetByInvalidTokenHashReturnsEmptyToken(t *testing.T) {
+	// Arrange
+	refreshTokenRepo := NewTestRefreshTokenRepository()
+	setupRefreshTokens(refreshTokenRepo)
+
+	// Act
+	refreshToken := refreshTokenRepo.GetByTokenHash("unknown-hash")
+
+	// Assert
+	assert.Equal(t, entities.RefreshTokenEntity{}, refreshToken)
+}
+
+func TestRefreshTokenRepositoryMarkAsUsedOnlySucceedsOnce(t *testing.T) {
+	// Arrange
+	refreshTokenRepo := NewTestRefreshTokenRepository()
+	setupRefreshTokens(refreshTokenRepo)
+
+	// Act
+	firstUse := refreshTokenRepo.MarkAsUsed(1)
+	secondUse := refreshTokenRepo.MarkAsUsed(1)
+
+	// Assert
+	assert.True(t, firstUse)
+	assert.False(t, secondUse)
+	assert.NotNil(t, refreshTokenRepo.GetByTokenHash("hash-1").UsedAt)
+}
+
+func TestRefreshTokenRepositoryRevokeFamilyRevokesAllTokens(t *testing.T) {
+	// Arrange
+	refreshTokenRepo := NewTestRefreshTokenRepository()
+	setupRefreshTokens(refreshTokenRepo)
+
+	// Act
+	refreshTokenRepo.RevokeFamily("family-1")
+
+	// Assert
+	assert.NotNil(t, refreshTokenRepo.GetByTokenHash("hash-1").RevokedAt)
+	assert.NotNil(t, refreshTokenRepo.GetByTokenHash("hash-2").RevokedAt)
+}
+
+func TestRefreshTokenRepositoryRevokeAllForUserExceptFamilyKeepsFamily(t *testing.T) {
+	// Arrange
+	refreshTokenRepo := NewTestRefreshTokenRepository()
+	setupRefreshTokens(refreshTokenRepo)
+	refreshTokenRepo.Create(entities.RefreshTokenEntity{ID: 3, UserID: 1, FamilyID: "family-2", TokenHash: "hash-3", ExpiresAt: time.Date(2099, time.March, 31, 10, 30, 0, 0, time.UTC), CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)})
+
+	// Act
+	refreshTokenRepo.RevokeAllForUserExceptFamily(1, "family-1")
+
+	// Assert
+	assert.Nil(t, refreshTokenRepo.GetByTokenHash("hash-1").RevokedAt)
+	assert.Nil(t, refreshTokenRepo.GetByTokenHash("hash-2").RevokedAt)
+	assert.NotNil(t, refreshTokenRepo.GetByTokenHash("hash-3").RevokedAt)
+}
@@ -0,0 +1,47 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestSigningKeyRepository() *repositories.SigningKeyRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.SigningKeyEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewSigningKeyRepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestSigningKeyRepositoryGetAllReturnsOldestFirst(t *testing.T) {
+	// Arrange
+	keyRepo := NewTestSigningKeyRepository()
+	now := time.Now()
+	keyRepo.Create(entities.SigningKeyEntity{KeyID: "key-2", Algorithm: "ES256", PrivateKey: "pem-2", ActivatedAt: now})
+	keyRepo.Create(entities.SigningKeyEntity{KeyID: "key-1", Algorithm: "ES256", PrivateKey: "pem-1", ActivatedAt: now.Add(-time.Hour)})
+
+	// Act
+	keys := keyRepo.GetAll()
+
+	// Assert
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "key-1", keys[0].KeyID)
+	assert.Equal(t, "key-2", keys[1].KeyID)
+	assert.Equal(t, "pem-2", keys[1].PrivateKey)
+}
@@ -0,0 +1,78 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestTokenRevocationRepository() *repositories.TokenRevocationRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.RevokedTokenEntity{}, &entities.SessionRevocationEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewTokenRevocationRepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestTokenRevocationRepositoryRevokedTokenIsRevoked(t *testing.T) {
+	// Arrange
+	revocationRepo := NewTestTokenRevocationRepository()
+	revocationRepo.RevokeToken(entities.RevokedTokenEntity{JTI: "jti-1", UserID: 1, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: time.Now()})
+
+	// Act
+	isRevoked := revocationRepo.IsTokenRevoked("jti-1")
+	isOtherRevoked := revocationRepo.IsTokenRevoked("jti-2")
+
+	// Assert
+	assert.True(t, isRevoked)
+	assert.False(t, isOtherRevoked)
+}
+
+func TestTokenRevocationRepositoryExpiredEntriesAreIgnoredAndDeleted(t *testing.T) {
+	// Arrange
+	revocationRepo := NewTestTokenRevocationRepository()
+	revocationRepo.RevokeToken(entities.RevokedTokenEntity{JTI: "jti-1", UserID: 1, ExpiresAt: time.Now().Add(-time.Minute), RevokedAt: time.Now()})
+	revocationRepo.RevokeSessions(entities.SessionRevocationEntity{UserID: 1, RevokedBefore: time.Now(), ExpiresAt: time.Now().Add(-time.Minute)})
+
+	// Act
+	isRevoked := revocationRepo.IsTokenRevoked("jti-1")
+	sessionRevocation := revocationRepo.GetSessionRevocation(1)
+	revocationRepo.DeleteExpired()
+
+	// Assert
+	assert.False(t, isRevoked)
+	assert.Equal(t, entities.SessionRevocationEntity{}, sessionRevocation)
+	var count int64
+	revocationRepo.DB.Model(&entities.RevokedTokenEntity{}).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestTokenRevocationRepositoryRevokeSessionsOverwritesPreviousRevocation(t *testing.T) {
+	// Arrange
+	revocationRepo := NewTestTokenRevocationRepository()
+	firstRevocation := time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)
+	secondRevocation := time.Date(2025, time.March, 31, 11, 30, 0, 0, time.UTC)
+	revocationRepo.RevokeSessions(entities.SessionRevocationEntity{UserID: 1, RevokedBefore: firstRevocation, ExpiresAt: time.Now().Add(time.Hour)})
+
+	// Act
+	revocationRepo.RevokeSessions(entities.SessionRevocationEntity{UserID: 1, RevokedBefore: secondRevocation, ExpiresAt: time.Now().Add(time.Hour)})
+	sessionRevocation := revocationRepo.GetSessionRevocation(1)
+
+	// Assert
+	assert.True(t, secondRevocation.Equal(sessionRevocation.RevokedBefore))
+}
@@ -58,8 +58,8 @@ func NewTestUserRepository() *repositories.UserRepository {
 func setupUsers(repo *repositories.UserRepository) []entities.UserEntity {
 	// Users
 	testUsers := []entities.UserEntity{
-		{ID: 1, FullName: "John Doe", Email: "john@doe.it", AccountType: 1, Password: "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
-		{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: 0, Password: "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+		{ID: 1, FullName: "John Doe", Email: "john@doe.it", AccountType: 1, Password: "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC), Version: 1},
+		{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: 0, Password: "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC), Version: 1},
 	}
 
 	// Add users to the test database
@@ -145,6 +145,7 @@ func TestUserRepositoryCreateUserReturnsNewUser(t *testing.T) {
 		AccountType: 0,
 		Password:    "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO",
 		CreatedAt:   time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC),
+		Version:     1,
 	}
 
 	// Act
@@ -163,7 +164,7 @@ func TestDeleteByValidIDReturnsTrue(t *testing.T) {
 	userID := 1
 
 	// Act
-	isDeleted := userRepo.DeleteByID(userID)
+	isDeleted := userRepo.DeleteByID(userID, testUsers[0].Version)
 	users := userRepo.GetAll()
 
 	// Assert
@@ -178,7 +179,7 @@ func TestDeleteByInvalidIDReturnsFalse(t *testing.T) {
 	invalidUserID := 999
 
 	// Act
-	isDeleted := userRepo.DeleteByID(invalidUserID)
+	isDeleted := userRepo.DeleteByID(invalidUserID, 1)
 	users := userRepo.GetAll()
 
 	// Assert
@@ -186,26 +187,19 @@ func TestDeleteByInvalidIDReturnsFalse(t *testing.T) {
 	assert.False(t, isDeleted)
 }
 
-func TestUpdateValidUserReturnsUpdatedUser(t *testing.T) {
+func TestDeleteWithStaleVersionReturnsFalse(t *testing.T) {
 	// Arrange
 	userRepo := NewTestUserRepository()
 	testUsers := setupUsers(userRepo)
-	// Update all user fields
-	updatedUser := entities.UserEntity{
-		ID:          1,
-		FullName:    "Jonathan Doe",
-		Email:       "jonathan@doe.it",
-		AccountType: 1,
-		Password:    "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO",
-		CreatedAt:   time.Date(2025, time.March, 31, 10, 30, 15, 0, time.UTC),
-	}
+	staleVersion := testUsers[0].Version - 1
 
 	// Act
-	user := userRepo.Update(updatedUser)
+	isDeleted := userRepo.DeleteByID(testUsers[0].ID, staleVersion)
+	users := userRepo.GetAll()
 
 	// Assert
-	assert.Equal(t, updatedUser, user)
-	assert.NotNil(t, testUsers)
+	assert.Len(t, users, len(testUsers))
+	assert.False(t, isDeleted)
 }
 
 func TestSaveLastLoginTimeReturnsNil(t *testing.T) {
@@ -216,3 +210,93 @@ func TestSaveLastLoginTimeReturnsNil(t *testing.T) {
 	// Act & Assert
 	userRepo.SaveLastLoginTime(mockUserID)
 }
+
+func TestUpdatePasswordOnlyUpdatesPassword(t *testing.T) {
+	// Arrange
+	userRepo := NewTestUserRepository()
+	testUsers := setupUsers(userRepo)
+
+	// Act
+	isUpdated := userRepo.UpdatePassword(1, "new-hash")
+	unknownUser := userRepo.UpdatePassword(99, "new-hash")
+
+	// Assert
+	assert.True(t, isUpdated)
+	assert.False(t, unknownUser)
+	updatedUser := userRepo.GetByID(1)
+	assert.Equal(t, "new-hash", updatedUser.Password)
+	assert.Equal(t, testUsers[0].FullName, updatedUser.FullName)
+	assert.Equal(t, testUsers[0].Email, updatedUser.Email)
+	assert.Equal(t, testUsers[0].Version+1, updatedUser.Version)
+}
+
+func TestUpdateColumnsOnlyUpdatesGivenColumns(t *testing.T) {
+	// Arrange
+	userRepo := NewTestUserRepository()
+	testUsers := setupUsers(userRepo)
+
+	// Act
+	isUpdated := userRepo.UpdateColumns(1, 1, map[string]interface{}{"FullName": "John Smith"})
+	unknownUser := userRepo.UpdateColumns(99, 1, map[string]interface{}{"FullName": "John Smith"})
+
+	// Assert
+	assert.True(t, isUpdated)
+	assert.False(t, unknownUser)
+	updatedUser := userRepo.GetByID(1)
+	assert.Equal(t, "John Smith", updatedUser.FullName)
+	assert.Equal(t, testUsers[0].Version+1, updatedUser.Version)
+	assert.Equal(t, testUsers[0].Password, updatedUser.Password)
+	assert.Equal(t, testUsers[0].AccountType, updatedUser.AccountType)
+	assert.True(t, testUsers[0].CreatedAt.Equal(updatedUser.CreatedAt))
+}
+
+func TestUpdateColumnsWithStaleVersionDoesNotWrite(t *testing.T) {
+	// Arrange
+	userRepo := NewTestUserRepository()
+	testUsers := setupUsers(userRepo)
+	userRepo.UpdateColumns(1, 1, map[string]interface{}{"FullName": "John Smith"})
+
+	// Act
+	isUpdated := userRepo.UpdateColumns(1, 1, map[string]interface{}{"FullName": "Johnny Doe"})
+
+	// Assert
+	assert.False(t, isUpdated)
+	updatedUser := userRepo.GetByID(1)
+	assert.Equal(t, "John Smith", updatedUser.FullName)
+	assert.Equal(t, testUsers[0].Version+1, updatedUser.Version)
+}
+
+func TestMarkEmailVerifiedOnlyUpdatesVerification(t *testing.T) {
+	// Arrange
+	userRepo := NewTestUserRepository()
+	testUsers := setupUsers(userRepo)
+
+	// Act
+	isVerified := userRepo.MarkEmailVerified(1)
+	unknownUser := userRepo.MarkEmailVerified(99)
+
+	// Assert
+	assert.True(t, isVerified)
+	assert.False(t, unknownUser)
+	verifiedUser := userRepo.GetByID(1)
+	assert.True(t, verifiedUser.EmailVerified)
+	assert.Equal(t, testUsers[0].Version+1, verifiedUser.Version)
+	assert.Equal(t, testUsers[0].Password, verifiedUser.Password)
+	assert.False(t, userRepo.GetByID(2).EmailVerified)
+}
+
+func TestUpdateEmailMarksAddressVerified(t *testing.T) {
+	// Arrange
+	userRepo := NewTestUserRepository()
+	testUsers := setupUsers(userRepo)
+
+	// Act
+	isUpdated := userRepo.UpdateEmail(1, "john@doe.com")
+
+	// Assert
+	assert.True(t, isUpdated)
+	updatedUser := userRepo.GetByID(1)
+	assert.Equal(t, "john@doe.com", updatedUser.Email)
+	assert.True(t, updatedUser.EmailVerified)
+	assert.Equal(t, testUsers[0].Password, updatedUser.Password)
+}
@@ -0,0 +1,89 @@
+package repositories_test
+
+import (
+	"flyhorizons-userservice/repositories"
+	entities "flyhorizons-userservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func NewTestWebAuthnRepository() *repositories.WebAuthnRepository {
+	baseRepo := &TestBaseRepository{}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Auto-migrate tables for the test database
+	if err := db.AutoMigrate(&entities.WebAuthnCredentialEntity{}, &entities.WebAuthnChallengeEntity{}); err != nil {
+		log.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	baseRepo.DB = db
+	return repositories.NewWebAuthnRepository(&baseRepo.BaseRepository)
+}
+
+// Integration Database Tests
+func TestWebAuthnRepositoryUpdateSignCountRejectsConcurrentUpdate(t *testing.T) {
+	// Arrange
+	webAuthnRepo := NewTestWebAuthnRepository()
+	credential := webAuthnRepo.CreateCredential(entities.WebAuthnCredentialEntity{
+		UserID:       1,
+		CredentialID: "credential-1",
+		PublicKey:    []byte{0x01},
+		SignCount:    3,
+		CreatedAt:    time.Now(),
+	})
+
+	// Act
+	first := webAuthnRepo.UpdateSignCount(credential.ID, 3, 4)
+	// A second login that read the same counter loses
+	concurrent := webAuthnRepo.UpdateSignCount(credential.ID, 3, 4)
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, concurrent)
+	stored := webAuthnRepo.GetCredentialByCredentialID("credential-1")
+	assert.Equal(t, int64(4), stored.SignCount)
+	assert.NotNil(t, stored.LastUsedAt)
+}
+
+func TestWebAuthnRepositoryDeleteCredentialOnlyDeletesOwnCredential(t *testing.T) {
+	// Arrange
+	webAuthnRepo := NewTestWebAuthnRepository()
+	webAuthnRepo.CreateCredential(entities.WebAuthnCredentialEntity{UserID: 1, CredentialID: "credential-1", PublicKey: []byte{0x01}, CreatedAt: time.Now()})
+
+	// Act
+	otherUser := webAuthnRepo.DeleteCredential(2, "credential-1")
+	owner := webAuthnRepo.DeleteCredential(1, "credential-1")
+
+	// Assert
+	assert.False(t, otherUser)
+	assert.True(t, owner)
+	assert.Empty(t, webAuthnRepo.GetCredentialsByUserID(1))
+}
+
+func TestWebAuthnRepositoryMarkChallengeAsUsedOnlyOnce(t *testing.T) {
+	// Arrange
+	webAuthnRepo := NewTestWebAuthnRepository()
+	challenge := webAuthnRepo.CreateChallenge(entities.WebAuthnChallengeEntity{
+		ChallengeHash: "hash",
+		Ceremony:      "login",
+		ExpiresAt:     time.Now().Add(time.Minute),
+		CreatedAt:     time.Now(),
+	})
+
+	// Act
+	first := webAuthnRepo.MarkChallengeAsUsed(challenge.ID)
+	replayed := webAuthnRepo.MarkChallengeAsUsed(challenge.ID)
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, replayed)
+	assert.NotNil(t, webAuthnRepo.GetChallengeByHash("hash").UsedAt)
+}
@@ -0,0 +1,111 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestEmailChangeRoute struct {
+}
+
+// Setup
+func setupEmailChangeRouter(mockEmailChangeService *mock_repositories.MockEmailChangeService, mockAPIGatewayMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterEmailChangeRoutes(router, mockEmailChangeService, mockAPIGatewayMiddleware)
+
+	return router
+}
+
+// Router Integration Tests
+func TestRequestEmailChangeReturnsAccepted(t *testing.T) {
+	// Arrange
+	mockEmailChangeService := new(mock_repositories.MockEmailChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockChangeRequest := request.ChangeEmailRequest{NewEmail: "john@doe.com"}
+	mockEmailChangeService.On("RequestChange", 1, mockChangeRequest).Return(nil)
+
+	router := setupEmailChangeRouter(mockEmailChangeService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(mockChangeRequest)
+	httpRequest, _ := http.NewRequest("POST", "/users/1/email", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	mockEmailChangeService.AssertExpectations(t)
+}
+
+func TestRequestEmailChangeForOtherUserReturnsForbidden(t *testing.T) {
+	// Arrange
+	mockEmailChangeService := new(mock_repositories.MockEmailChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupEmailChangeRouter(mockEmailChangeService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(request.ChangeEmailRequest{NewEmail: "john@doe.com"})
+	httpRequest, _ := http.NewRequest("POST", "/users/2/email", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockEmailChangeService.AssertNotCalled(t, "RequestChange", mock.Anything, mock.Anything)
+}
+
+func TestConfirmEmailChangeToAddressInUseReturnsConflict(t *testing.T) {
+	// Arrange
+	mockEmailChangeService := new(mock_repositories.MockEmailChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockEmailChangeService.On("Confirm", "Confirm-Token-Mock-1234").Return(errors.NewEmailInUseError(409))
+
+	router := setupEmailChangeRouter(mockEmailChangeService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/users/email/confirm?token=Confirm-Token-Mock-1234", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+}
+
+func TestRevertEmailChangeReturnsOK(t *testing.T) {
+	// Arrange
+	mockEmailChangeService := new(mock_repositories.MockEmailChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockEmailChangeService.On("Revert", "Revert-Token-Mock-1234").Return(nil)
+
+	router := setupEmailChangeRouter(mockEmailChangeService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/users/email/revert?token=Revert-Token-Mock-1234", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockEmailChangeService.AssertExpectations(t)
+}
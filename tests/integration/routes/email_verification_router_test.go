@@ -0,0 +1,85 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestEmailVerificationRoute struct {
+}
+
+// Setup
+func setupEmailVerificationRouter(mockEmailVerificationService *mock_repositories.MockEmailVerificationService) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterEmailVerificationRoutes(router, mockEmailVerificationService)
+
+	return router
+}
+
+// Router Integration Tests
+func TestVerifyEmailUsingValidTokenReturnsOK(t *testing.T) {
+	// Arrange
+	mockEmailVerificationService := new(mock_repositories.MockEmailVerificationService)
+	mockEmailVerificationService.On("Verify", "Verification-Token-Mock-1234").Return(nil)
+
+	router := setupEmailVerificationRouter(mockEmailVerificationService)
+
+	httpRequest, _ := http.NewRequest("GET", "/users/verify?token=Verification-Token-Mock-1234", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockEmailVerificationService.AssertExpectations(t)
+}
+
+func TestVerifyEmailUsingInvalidTokenReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockEmailVerificationService := new(mock_repositories.MockEmailVerificationService)
+	mockEmailVerificationService.On("Verify", "Verification-Token-Mock-1234").Return(errors.NewInvalidEmailVerificationTokenError(400))
+
+	router := setupEmailVerificationRouter(mockEmailVerificationService)
+
+	httpRequest, _ := http.NewRequest("GET", "/users/verify?token=Verification-Token-Mock-1234", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+}
+
+func TestResendVerificationReturnsAccepted(t *testing.T) {
+	// Arrange
+	mockEmailVerificationService := new(mock_repositories.MockEmailVerificationService)
+	mockResendRequest := request.ResendVerificationRequest{Email: "john@doe.it"}
+	mockEmailVerificationService.On("Resend", mockResendRequest).Return()
+
+	router := setupEmailVerificationRouter(mockEmailVerificationService)
+
+	requestBody, _ := json.Marshal(mockResendRequest)
+	httpRequest, _ := http.NewRequest("POST", "/users/verify/resend", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	mockEmailVerificationService.AssertExpectations(t)
+}
@@ -0,0 +1,89 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestInternalRoute struct {
+}
+
+// Setup
+func setupInternalRouter(mockService *mock_repositories.MockUserService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterInternalRoutes(router, mockService, gatewayAuthMiddleware)
+
+	return router
+}
+
+// Router Integration Tests
+func TestGetUserAsServiceWithScopeReturnsUserJSON(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockServiceGatewayAuthMiddleware("booking-service", "bookings:write users:read")
+	mockUser := getUserProfiles()[0]
+	mockService.On("GetByID", mockUser.ID).Return(&mockUser, nil)
+
+	router := setupInternalRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/internal/users/1", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var user response.UserProfileResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
+	assert.NoError(t, err)
+	assert.Equal(t, mockUser, user)
+}
+
+func TestGetUserAsServiceWithoutScopeReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockServiceGatewayAuthMiddleware("email-service", "emails:send")
+
+	router := setupInternalRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/internal/users/1", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "GetByID", 1)
+}
+
+func TestGetUserAsHumanUserReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+
+	router := setupInternalRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/internal/users/1", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
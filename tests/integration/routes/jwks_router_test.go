@@ -0,0 +1,45 @@
+package routes_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/authentication"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestJWKSRoute struct {
+}
+
+// Router Integration Tests
+func TestGetJWKSReturnsPublicKeys(t *testing.T) {
+	// Arrange
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	signingKey, _ := authentication.NewSigningKey(authentication.AlgorithmEdDSA, "key-1", privateKey)
+	router := gin.Default()
+	routes.RegisterJWKSRoutes(router, authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour)))
+
+	httpRequest, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var keySet response.JSONWebKeySet
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &keySet)
+	assert.NoError(t, err)
+	assert.Len(t, keySet.Keys, 1)
+	assert.Equal(t, "OKP", keySet.Keys[0].KeyType)
+	assert.Equal(t, "key-1", keySet.Keys[0].KeyID)
+}
@@ -0,0 +1,92 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestKeyRoute struct {
+}
+
+// Setup
+func setupKeyRouter(mockRotator *mock_repositories.MockKeyRotator, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterKeyRoutes(router, mockRotator, gatewayAuthMiddleware)
+
+	return router
+}
+
+// Router Integration Tests
+func TestRotateKeysAsAdminReturnsNewKey(t *testing.T) {
+	// Arrange
+	mockRotator := new(mock_repositories.MockKeyRotator)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockKey := &response.SigningKeyResponse{KeyID: "key-2", Algorithm: "ES256", Status: "active"}
+	mockRotator.On("Rotate").Return(mockKey, nil)
+
+	router := setupKeyRouter(mockRotator, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/keys/rotate", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var signingKey response.SigningKeyResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &signingKey)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockKey, signingKey)
+	mockRotator.AssertExpectations(t)
+}
+
+func TestRotateKeysAsUserReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockRotator := new(mock_repositories.MockKeyRotator)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupKeyRouter(mockRotator, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/keys/rotate", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockRotator.AssertNotCalled(t, "Rotate")
+}
+
+func TestRotateSymmetricKeyReturnsHTTPStatusConflict(t *testing.T) {
+	// Arrange
+	mockRotator := new(mock_repositories.MockKeyRotator)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockRotator.On("Rotate").Return(nil, errors.NewKeyRotationNotSupportedError("HS256", 409))
+
+	router := setupKeyRouter(mockRotator, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/keys/rotate", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+}
@@ -0,0 +1,85 @@
+package routes_test
+
+import (
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestLockoutRoute struct {
+}
+
+// Setup
+func setupLockoutRouter(mockLockoutService *mock_repositories.MockAccountLockoutService, mockAPIGatewayMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterLockoutRoutes(router, mockLockoutService, mockAPIGatewayMiddleware)
+
+	return router
+}
+
+// Router Integration Tests
+func TestUnlockAccountAsAdminReturnsOK(t *testing.T) {
+	// Arrange
+	mockLockoutService := new(mock_repositories.MockAccountLockoutService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 2)
+	mockLockoutService.On("Unlock", 1).Return(nil)
+
+	router := setupLockoutRouter(mockLockoutService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/users/1/unlock", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockLockoutService.AssertExpectations(t)
+}
+
+func TestUnlockAccountAsUserReturnsForbidden(t *testing.T) {
+	// Arrange
+	mockLockoutService := new(mock_repositories.MockAccountLockoutService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupLockoutRouter(mockLockoutService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/users/1/unlock", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockLockoutService.AssertNotCalled(t, "Unlock", mock.Anything)
+}
+
+func TestUnlockUnknownAccountReturnsNotFound(t *testing.T) {
+	// Arrange
+	mockLockoutService := new(mock_repositories.MockAccountLockoutService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 2)
+	mockLockoutService.On("Unlock", 99).Return(errors.NewUserNotFoundError(99, 404))
+
+	router := setupLockoutRouter(mockLockoutService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/users/99/unlock", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -96,3 +97,219 @@ func TestLoginUsingIncorrectCredentialsReturnsAccessDenied(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestRefreshUsingValidRefreshTokenReturnsNewTokens(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockRefreshRequest := request.RefreshTokenRequest{RefreshToken: "Refresh-Token-Mock-1234"}
+	mockLoginResponse := &response.LoginResponse{AccessToken: "Access-Token-Mock-5678", TokenType: "Bearer", RefreshToken: "Refresh-Token-Mock-5678"}
+	mockService.On("Refresh", mockRefreshRequest).Return(mockLoginResponse, nil)
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	// Make the JSON to create the refresh request
+	requestBody, _ := json.Marshal(mockRefreshRequest)
+	httpRequest, _ := http.NewRequest("POST", "/token/refresh", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var responseBody response.LoginResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseBody)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockLoginResponse, responseBody)
+	mockService.AssertExpectations(t)
+}
+
+func TestRefreshUsingReusedRefreshTokenReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockRefreshRequest := request.RefreshTokenRequest{RefreshToken: "Refresh-Token-Mock-1234"}
+	mockService.On("Refresh", mockRefreshRequest).Return(nil, errors.NewRefreshTokenReusedError(401))
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	// Make the JSON to create the refresh request
+	requestBody, _ := json.Marshal(mockRefreshRequest)
+	httpRequest, _ := http.NewRequest("POST", "/token/refresh", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestVerifyMFAUsingValidCodeReturnsTokens(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockMFARequest := request.MFALoginRequest{MFAToken: "MFA-Token-Mock-1234", Code: "123456"}
+	mockLoginResponse := &response.LoginResponse{AccessToken: "Access-Token-Mock-5678", TokenType: "Bearer", RefreshToken: "Refresh-Token-Mock-5678"}
+	mockService.On("VerifyMFA", mockMFARequest).Return(mockLoginResponse, nil)
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	// Make the JSON to create the MFA request
+	requestBody, _ := json.Marshal(mockMFARequest)
+	httpRequest, _ := http.NewRequest("POST", "/login/mfa", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var responseBody response.LoginResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseBody)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockLoginResponse, responseBody)
+}
+
+func TestVerifyMFAUsingInvalidCodeReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockMFARequest := request.MFALoginRequest{MFAToken: "MFA-Token-Mock-1234", Code: "000000"}
+	mockService.On("VerifyMFA", mockMFARequest).Return(nil, errors.NewInvalidMFACodeError(401))
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	// Make the JSON to create the MFA request
+	requestBody, _ := json.Marshal(mockMFARequest)
+	httpRequest, _ := http.NewRequest("POST", "/login/mfa", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLoginWithPasskeyReturnsTokens(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockPasskeyRequest := request.PasskeyLoginRequest{
+		ID:   "Credential-ID-Mock-1234",
+		Type: "public-key",
+		Response: request.PasskeyAssertionResponse{
+			ClientDataJSON:    "Client-Data-Mock",
+			AuthenticatorData: "Authenticator-Data-Mock",
+			Signature:         "Signature-Mock",
+		},
+	}
+	mockLoginResponse := &response.LoginResponse{AccessToken: "Access-Token-Mock-5678", TokenType: "Bearer", RefreshToken: "Refresh-Token-Mock-5678"}
+	mockService.On("LoginWithPasskey", mockPasskeyRequest).Return(mockLoginResponse, nil)
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	// Make the JSON to create the passkey request
+	requestBody, _ := json.Marshal(mockPasskeyRequest)
+	httpRequest, _ := http.NewRequest("POST", "/login/passkey", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var responseBody response.LoginResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseBody)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockLoginResponse, responseBody)
+}
+
+func TestLoginWithInvalidPasskeyReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockPasskeyRequest := request.PasskeyLoginRequest{
+		ID:   "Credential-ID-Mock-1234",
+		Type: "public-key",
+		Response: request.PasskeyAssertionResponse{
+			ClientDataJSON:    "Client-Data-Mock",
+			AuthenticatorData: "Authenticator-Data-Mock",
+			Signature:         "Signature-Mock",
+		},
+	}
+	mockService.On("LoginWithPasskey", mockPasskeyRequest).Return(nil, errors.NewInvalidPasskeyError(401))
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	// Make the JSON to create the passkey request
+	requestBody, _ := json.Marshal(mockPasskeyRequest)
+	httpRequest, _ := http.NewRequest("POST", "/login/passkey", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+}
+
+func TestLoginUsingUnverifiedEmailReturnsForbidden(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockLoginRequest := getIncorrectLoginCredentials()
+	mockService.On("Login", mockLoginRequest).Return(nil, errors.NewEmailNotVerifiedError(403))
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(mockLoginRequest)
+	httpRequest, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestLoginUsingLockedAccountReturnsLockedWithRetryAfter(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockLoginService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockLoginRequest := getIncorrectLoginCredentials()
+	mockService.On("Login", mockLoginRequest).Return(nil, errors.NewAccountLockedError(90*time.Second, 423))
+
+	router := setupLoginRouter(mockService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(mockLoginRequest)
+	httpRequest, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusLocked, responseRecorder.Code)
+	assert.Equal(t, "90", responseRecorder.Header().Get("Retry-After"))
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,170 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestMFARoute struct {
+}
+
+// Setup
+func setupMFARouter(mockMFAService *mock_repositories.MockMFAService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterMFARoutes(router, mockMFAService, gatewayAuthMiddleware)
+
+	return router
+}
+
+// Router Integration Tests
+func TestEnrollMFAAsUserReturnsSecret(t *testing.T) {
+	// Arrange
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockEnrollment := &response.MFAEnrollmentResponse{Secret: "JBSWY3DPEHPK3PXP", OTPAuthURI: "otpauth://totp/FlyHorizons:test%40email.com?secret=JBSWY3DPEHPK3PXP"}
+	mockMFAService.On("Enroll", 1).Return(mockEnrollment, nil)
+
+	router := setupMFARouter(mockMFAService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/mfa/enroll", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var enrollment response.MFAEnrollmentResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &enrollment)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockEnrollment, enrollment)
+}
+
+func TestActivateMFAUsingInvalidCodeReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockMFAService.On("Activate", 1, "000000").Return(errors.NewInvalidMFACodeError(400))
+
+	router := setupMFARouter(mockMFAService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(request.MFACodeRequest{Code: "000000"})
+	httpRequest, _ := http.NewRequest("POST", "/mfa/activate", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockMFAService.AssertExpectations(t)
+}
+
+func TestDisableMFAAsAdminRequiredByPolicyReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockMFAService.On("Disable", 1, "123456").Return(errors.NewMFARequiredByPolicyError(403))
+
+	router := setupMFARouter(mockMFAService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(request.MFACodeRequest{Code: "123456"})
+	httpRequest, _ := http.NewRequest("POST", "/mfa/disable", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func TestEnrollMFAWithChallengeUsingExpiredTokenReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockMFAService.On("EnrollWithChallenge", "MFA-Token-Mock-1234").Return(nil, errors.NewInvalidMFATokenError(401))
+
+	router := setupMFARouter(mockMFAService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(request.MFATokenRequest{MFAToken: "MFA-Token-Mock-1234"})
+	httpRequest, _ := http.NewRequest("POST", "/login/mfa/enroll", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+}
+
+func TestRegenerateRecoveryCodesAsUserReturnsCodes(t *testing.T) {
+	// Arrange
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockRecoveryCodes := &response.MFARecoveryCodesResponse{RecoveryCodes: []string{"abcd-efgh-ijkl-mnop"}}
+	mockMFAService.On("RegenerateRecoveryCodes", 1, "123456").Return(mockRecoveryCodes, nil)
+
+	router := setupMFARouter(mockMFAService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(request.MFACodeRequest{Code: "123456"})
+	httpRequest, _ := http.NewRequest("POST", "/mfa/recovery-codes", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var recoveryCodes response.MFARecoveryCodesResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &recoveryCodes)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockRecoveryCodes, recoveryCodes)
+}
+
+func TestMFAStatusAsUserReturnsRemainingRecoveryCodes(t *testing.T) {
+	// Arrange
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockStatus := response.MFAStatusResponse{Enabled: true, RecoveryCodesRemaining: 7}
+	mockMFAService.On("Status", 1).Return(mockStatus)
+
+	router := setupMFARouter(mockMFAService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/mfa/", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var status response.MFAStatusResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &status)
+	assert.NoError(t, err)
+	assert.Equal(t, mockStatus, status)
+}
@@ -0,0 +1,277 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestOAuthRoute struct {
+}
+
+// Setup
+func setupOAuthRouter(mockIntrospectionService *mock_repositories.MockIntrospectionService, mockClientService *mock_repositories.MockOAuthClientService, mockAuthorizationService *mock_repositories.MockAuthorizationService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterOAuthRoutes(router, mockIntrospectionService, mockClientService, mockAuthorizationService, gatewayAuthMiddleware)
+
+	return router
+}
+
+func newFormRequest(url string, form url.Values) *http.Request {
+	httpRequest, _ := http.NewRequest("POST", url, strings.NewReader(form.Encode()))
+	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return httpRequest
+}
+
+// Router Integration Tests
+func TestIntrospectAsAuthenticatedClientReturnsIntrospection(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockIntrospection := response.IntrospectionResponse{Active: true, Subject: "1", Role: "user"}
+	mockClientService.On("AuthorizeIntrospection", "booking-service", "secret").Return(nil)
+	mockIntrospectionService.On("Introspect", "Access-Token-Mock-1234").Return(mockIntrospection)
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/introspect", url.Values{"token": {"Access-Token-Mock-1234"}})
+	httpRequest.SetBasicAuth("booking-service", "secret")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var introspection response.IntrospectionResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &introspection)
+	assert.NoError(t, err)
+	assert.Equal(t, mockIntrospection, introspection)
+	mockIntrospectionService.AssertExpectations(t)
+}
+
+func TestIntrospectAsUnauthenticatedClientReturnsUnauthorized(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockClientService.On("AuthorizeIntrospection", "booking-service", "wrong-secret").Return(errors.NewInvalidClientError(401))
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/introspect", url.Values{"token": {"Access-Token-Mock-1234"}})
+	httpRequest.SetBasicAuth("booking-service", "wrong-secret")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+	mockIntrospectionService.AssertNotCalled(t, "Introspect", "Access-Token-Mock-1234")
+}
+
+func TestIntrospectAsClientWithoutIntrospectScopeReturnsForbidden(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockClientService.On("AuthorizeIntrospection", "partner-app", "secret").Return(errors.NewInsufficientScopeError("introspect", 403))
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/introspect", url.Values{"token": {"Access-Token-Mock-1234"}})
+	httpRequest.SetBasicAuth("partner-app", "secret")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockIntrospectionService.AssertNotCalled(t, "Introspect", mock.Anything)
+}
+
+func TestClientCredentialsGrantReturnsAccessToken(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockTokenResponse := &response.OAuthTokenResponse{AccessToken: "Access-Token-Mock-1234", TokenType: "Bearer", ExpiresIn: 900, Scope: "users:read"}
+	mockClientService.On("IssueClientCredentialsToken", "booking-service", "secret", "users:read").Return(mockTokenResponse, nil)
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/token", url.Values{"grant_type": {"client_credentials"}, "scope": {"users:read"}})
+	httpRequest.SetBasicAuth("booking-service", "secret")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, "no-store", responseRecorder.Header().Get("Cache-Control"))
+
+	var tokenResponse response.OAuthTokenResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &tokenResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockTokenResponse, tokenResponse)
+}
+
+func TestClientCredentialsGrantUsingInvalidClientReturnsUnauthorized(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockClientService.On("IssueClientCredentialsToken", "booking-service", "wrong-secret", "").Return(nil, errors.NewInvalidClientError(401))
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/token", url.Values{"grant_type": {"client_credentials"}, "client_id": {"booking-service"}, "client_secret": {"wrong-secret"}})
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+}
+
+func TestTokenUsingUnsupportedGrantTypeReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/token", url.Values{"grant_type": {"password"}})
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+}
+
+func TestRegisterClientAsAdminReturnsClientSecret(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	registerRequest := request.RegisterClientRequest{Name: "Booking Service", Scopes: []string{"users:read"}}
+	mockClient := &response.RegisterClientResponse{ClientID: "client-1", ClientSecret: "secret", Name: "Booking Service", Scopes: []string{"users:read"}}
+	mockClientService.On("Register", registerRequest).Return(mockClient, nil)
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(registerRequest)
+	httpRequest, _ := http.NewRequest("POST", "/admin/oauth/clients/", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var client response.RegisterClientResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &client)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockClient, client)
+}
+
+func TestRegisterClientAsUserReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, new(mock_repositories.MockAuthorizationService), mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(request.RegisterClientRequest{Name: "Booking Service"})
+	httpRequest, _ := http.NewRequest("POST", "/admin/oauth/clients/", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func TestAuthorizationCodeGrantReturnsAccessAndIDToken(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	codeRequest := request.AuthorizationCodeRequest{Code: "Code-Mock-1234", RedirectURI: "https://partner.example/callback", ClientID: "partner-app", CodeVerifier: "Verifier-Mock-1234"}
+	mockTokenResponse := &response.OAuthTokenResponse{AccessToken: "Access-Token-Mock-1234", TokenType: "Bearer", ExpiresIn: 900, Scope: "openid", IDToken: "ID-Token-Mock-1234"}
+	mockAuthorizationService.On("ExchangeAuthorizationCode", codeRequest).Return(mockTokenResponse, nil)
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/token", url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"Code-Mock-1234"},
+		"redirect_uri":  {"https://partner.example/callback"},
+		"client_id":     {"partner-app"},
+		"code_verifier": {"Verifier-Mock-1234"},
+	})
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var tokenResponse response.OAuthTokenResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &tokenResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockTokenResponse, tokenResponse)
+}
+
+func TestAuthorizationCodeGrantUsingInvalidCodeReturnsInvalidGrant(t *testing.T) {
+	// Arrange
+	mockIntrospectionService := new(mock_repositories.MockIntrospectionService)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockAuthorizationService.On("ExchangeAuthorizationCode", mock.Anything).Return(nil, errors.NewInvalidGrantError(400))
+
+	router := setupOAuthRouter(mockIntrospectionService, mockClientService, mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	httpRequest := newFormRequest("/oauth/token", url.Values{"grant_type": {"authorization_code"}, "code": {"Code-Mock-1234"}, "client_id": {"partner-app"}})
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "invalid_grant")
+}
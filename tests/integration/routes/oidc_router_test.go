@@ -0,0 +1,187 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestOIDCRoute struct {
+}
+
+// Setup
+func setupOIDCRouter(mockAuthorizationService *mock_repositories.MockAuthorizationService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterOIDCRoutes(router, mockAuthorizationService, gatewayAuthMiddleware)
+
+	return router
+}
+
+const authorizeURL = "/oauth/authorize?response_type=code&client_id=partner-app&redirect_uri=https%3A%2F%2Fpartner.example%2Fcallback&scope=openid&state=xyz&code_challenge=E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM&code_challenge_method=S256"
+
+func getRouteAuthorizeRequest() request.AuthorizeRequest {
+	return request.AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            "partner-app",
+		RedirectURI:         "https://partner.example/callback",
+		Scope:               "openid",
+		State:               "xyz",
+		CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		CodeChallengeMethod: "S256",
+	}
+}
+
+// The consent page posts the parameters of the authorize request back with the decision of the user
+func newConsentRequest() *http.Request {
+	authorizeQuery, _ := url.ParseQuery(strings.SplitN(authorizeURL, "?", 2)[1])
+	authorizeQuery.Set("approved", "true")
+	return newFormRequest("/oauth/authorize/consent", authorizeQuery)
+}
+
+// Router Integration Tests
+func TestAuthorizeRedirectsToConsentPage(t *testing.T) {
+	// Arrange
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockAuthorizationService.On("Authorize", getRouteAuthorizeRequest()).Return("https://flyhorizons.example/oauth/consent?client_id=partner-app", nil)
+
+	router := setupOIDCRouter(mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	// Browsers are sent here by the client, without an Authorization header
+	httpRequest, _ := http.NewRequest("GET", authorizeURL, nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusFound, responseRecorder.Code)
+	assert.Equal(t, "https://flyhorizons.example/oauth/consent?client_id=partner-app", responseRecorder.Header().Get("Location"))
+	mockAuthorizationService.AssertExpectations(t)
+}
+
+func TestAuthorizeUsingUnregisteredRedirectURIReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockAuthorizationService.On("Authorize", mock.Anything).Return("", errors.NewInvalidRedirectURIError(400))
+
+	router := setupOIDCRouter(mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", authorizeURL, nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Empty(t, responseRecorder.Header().Get("Location"))
+}
+
+func TestGrantConsentAsUserReturnsRedirectWithCode(t *testing.T) {
+	// Arrange
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	expectedRequest := request.ConsentRequest{AuthorizeRequest: getRouteAuthorizeRequest(), Approved: true}
+	mockAuthorizationService.On("GrantConsent", expectedRequest, 1).Return("https://partner.example/callback?code=Code-Mock-1234&state=xyz", nil)
+
+	router := setupOIDCRouter(mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	httpRequest := newConsentRequest()
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var consentResponse map[string]string
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &consentResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://partner.example/callback?code=Code-Mock-1234&state=xyz", consentResponse["redirect_to"])
+	mockAuthorizationService.AssertExpectations(t)
+}
+
+func TestGrantConsentAsServiceReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockServiceGatewayAuthMiddleware("booking-service", "users:read")
+
+	router := setupOIDCRouter(mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	httpRequest := newConsentRequest()
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockAuthorizationService.AssertNotCalled(t, "GrantConsent", mock.Anything, mock.Anything)
+}
+
+func TestUserInfoAsUserReturnsClaims(t *testing.T) {
+	// Arrange
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockUserInfo := &response.UserInfoResponse{Subject: "1", Name: "John Doe", Email: "john@doe.it"}
+	mockAuthorizationService.On("UserInfo", 1, "").Return(mockUserInfo, nil)
+
+	router := setupOIDCRouter(mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/userinfo", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var userInfo response.UserInfoResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &userInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockUserInfo, userInfo)
+}
+
+func TestOpenIDConfigurationReturnsDiscoveryDocument(t *testing.T) {
+	// Arrange
+	mockAuthorizationService := new(mock_repositories.MockAuthorizationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockDiscovery := response.OpenIDConfigurationResponse{Issuer: "http://localhost:8081", JWKSURI: "http://localhost:8081/.well-known/jwks.json"}
+	mockAuthorizationService.On("Discovery").Return(mockDiscovery)
+
+	router := setupOIDCRouter(mockAuthorizationService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/.well-known/openid-configuration", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var discovery response.OpenIDConfigurationResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &discovery)
+	assert.NoError(t, err)
+	assert.Equal(t, mockDiscovery, discovery)
+}
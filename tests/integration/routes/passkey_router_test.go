@@ -0,0 +1,160 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestPasskeyRoute struct {
+}
+
+// Setup
+func setupPasskeyRouter(mockWebAuthnService *mock_repositories.MockWebAuthnService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterPasskeyRoutes(router, mockWebAuthnService, gatewayAuthMiddleware)
+
+	return router
+}
+
+// Router Integration Tests
+func TestBeginPasskeyLoginReturnsOptions(t *testing.T) {
+	// Arrange
+	mockWebAuthnService := new(mock_repositories.MockWebAuthnService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockOptions := &response.PasskeyLoginOptionsResponse{Challenge: "Challenge-Mock-1234", RelyingPartyID: "localhost", Timeout: 300000, UserVerification: "required"}
+	mockWebAuthnService.On("BeginLogin").Return(mockOptions, nil)
+
+	router := setupPasskeyRouter(mockWebAuthnService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/login/passkey/options", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var options response.PasskeyLoginOptionsResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &options)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockOptions, options)
+}
+
+func TestBeginPasskeyRegistrationAsUserReturnsOptions(t *testing.T) {
+	// Arrange
+	mockWebAuthnService := new(mock_repositories.MockWebAuthnService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockOptions := &response.PasskeyRegistrationOptionsResponse{Challenge: "Challenge-Mock-1234", Attestation: "none"}
+	mockWebAuthnService.On("BeginRegistration", 1).Return(mockOptions, nil)
+
+	router := setupPasskeyRouter(mockWebAuthnService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/passkeys/options", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockWebAuthnService.AssertCalled(t, "BeginRegistration", 1)
+}
+
+func TestFinishPasskeyRegistrationAsUserReturnsPasskey(t *testing.T) {
+	// Arrange
+	mockWebAuthnService := new(mock_repositories.MockWebAuthnService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockRegistrationRequest := request.PasskeyRegistrationRequest{
+		ID:   "Credential-ID-Mock-1234",
+		Type: "public-key",
+		Response: request.PasskeyAttestationResponse{
+			ClientDataJSON:    "Client-Data-Mock",
+			AttestationObject: "Attestation-Object-Mock",
+		},
+		Name: "Phone",
+	}
+	mockPasskey := &response.PasskeyResponse{CredentialID: "Credential-ID-Mock-1234", Name: "Phone", CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	mockWebAuthnService.On("FinishRegistration", 1, mockRegistrationRequest).Return(mockPasskey, nil)
+
+	router := setupPasskeyRouter(mockWebAuthnService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(mockRegistrationRequest)
+	httpRequest, _ := http.NewRequest("POST", "/passkeys/", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var passkey response.PasskeyResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &passkey)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockPasskey, passkey)
+}
+
+func TestFinishPasskeyRegistrationUsingInvalidAttestationReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockWebAuthnService := new(mock_repositories.MockWebAuthnService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockRegistrationRequest := request.PasskeyRegistrationRequest{
+		ID:   "Credential-ID-Mock-1234",
+		Type: "public-key",
+		Response: request.PasskeyAttestationResponse{
+			ClientDataJSON:    "Client-Data-Mock",
+			AttestationObject: "Attestation-Object-Mock",
+		},
+	}
+	mockWebAuthnService.On("FinishRegistration", 1, mockRegistrationRequest).Return(nil, errors.NewInvalidPasskeyError(400))
+
+	router := setupPasskeyRouter(mockWebAuthnService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(mockRegistrationRequest)
+	httpRequest, _ := http.NewRequest("POST", "/passkeys/", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+}
+
+func TestDeleteUnknownPasskeyReturnsNotFound(t *testing.T) {
+	// Arrange
+	mockWebAuthnService := new(mock_repositories.MockWebAuthnService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockWebAuthnService.On("DeleteCredential", 1, "unknown").Return(errors.NewPasskeyNotFoundError(404))
+
+	router := setupPasskeyRouter(mockWebAuthnService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("DELETE", "/passkeys/unknown", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
@@ -0,0 +1,141 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestPasswordChangeRoute struct {
+}
+
+// Setup
+func setupPasswordChangeRouter(mockPasswordChangeService *mock_repositories.MockPasswordChangeService, mockAPIGatewayMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterPasswordChangeRoutes(router, mockPasswordChangeService, mockAPIGatewayMiddleware)
+
+	return router
+}
+
+func newChangePasswordHTTPRequest(path string, changeRequest request.ChangePasswordRequest) *http.Request {
+	requestBody, _ := json.Marshal(changeRequest)
+	httpRequest, _ := http.NewRequest("POST", path, bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	return httpRequest
+}
+
+// Router Integration Tests
+func TestChangePasswordReturnsOK(t *testing.T) {
+	// Arrange
+	mockPasswordChangeService := new(mock_repositories.MockPasswordChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	changeRequest := request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd"}
+	mockPasswordChangeService.On("ChangePassword", 1, changeRequest).Return(nil)
+
+	router := setupPasswordChangeRouter(mockPasswordChangeService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newChangePasswordHTTPRequest("/users/1/password", changeRequest))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockPasswordChangeService.AssertExpectations(t)
+}
+
+func TestChangePasswordForOtherUserReturnsForbidden(t *testing.T) {
+	// Arrange
+	mockPasswordChangeService := new(mock_repositories.MockPasswordChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupPasswordChangeRouter(mockPasswordChangeService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newChangePasswordHTTPRequest("/users/2/password", request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd"}))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockPasswordChangeService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything)
+}
+
+func TestChangePasswordWithoutCurrentPasswordReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockPasswordChangeService := new(mock_repositories.MockPasswordChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupPasswordChangeRouter(mockPasswordChangeService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newChangePasswordHTTPRequest("/users/1/password", request.ChangePasswordRequest{NewPassword: "Sup3r$ecurePassw0rd"}))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockPasswordChangeService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything)
+}
+
+func TestChangePasswordUsingWrongCurrentPasswordReturnsForbidden(t *testing.T) {
+	// Arrange
+	mockPasswordChangeService := new(mock_repositories.MockPasswordChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	changeRequest := request.ChangePasswordRequest{CurrentPassword: "4321!", NewPassword: "Sup3r$ecurePassw0rd"}
+	mockPasswordChangeService.On("ChangePassword", 1, changeRequest).Return(errors.NewInvalidCredentialsError(403))
+
+	router := setupPasswordChangeRouter(mockPasswordChangeService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newChangePasswordHTTPRequest("/users/1/password", changeRequest))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func TestChangePasswordOfLockedAccountReturnsLocked(t *testing.T) {
+	// Arrange
+	mockPasswordChangeService := new(mock_repositories.MockPasswordChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	changeRequest := request.ChangePasswordRequest{CurrentPassword: "4321!", NewPassword: "Sup3r$ecurePassw0rd"}
+	mockPasswordChangeService.On("ChangePassword", 1, changeRequest).Return(errors.NewAccountLockedError(time.Minute, 423))
+
+	router := setupPasswordChangeRouter(mockPasswordChangeService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newChangePasswordHTTPRequest("/users/1/password", changeRequest))
+
+	// Assert
+	assert.Equal(t, http.StatusLocked, responseRecorder.Code)
+	assert.Equal(t, "60", responseRecorder.Header().Get("Retry-After"))
+}
+
+func TestChangePasswordUsingRecentPasswordReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockPasswordChangeService := new(mock_repositories.MockPasswordChangeService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	changeRequest := request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd"}
+	mockPasswordChangeService.On("ChangePassword", 1, changeRequest).Return(errors.NewPasswordReusedError(400))
+
+	router := setupPasswordChangeRouter(mockPasswordChangeService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newChangePasswordHTTPRequest("/users/1/password", changeRequest))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+}
@@ -0,0 +1,172 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/validation"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestPasswordRoute struct {
+}
+
+// Setup
+func setupPasswordRouter(mockPasswordResetService *mock_repositories.MockPasswordResetService) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterPasswordRoutes(router, mockPasswordResetService, new(mock_repositories.MockPasswordCheckService))
+
+	return router
+}
+
+func setupPasswordCheckRouter(mockPasswordCheckService *mock_repositories.MockPasswordCheckService) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterPasswordRoutes(router, new(mock_repositories.MockPasswordResetService), mockPasswordCheckService)
+
+	return router
+}
+
+// Router Integration Tests
+func TestForgotPasswordReturnsSameResponseForKnownAndUnknownEmail(t *testing.T) {
+	// Arrange
+	mockPasswordResetService := new(mock_repositories.MockPasswordResetService)
+	mockPasswordResetService.On("Forgot", request.ForgotPasswordRequest{Email: "john@doe.it"}).Return()
+	mockPasswordResetService.On("Forgot", request.ForgotPasswordRequest{Email: "unknown@doe.it"}).Return()
+
+	router := setupPasswordRouter(mockPasswordResetService)
+
+	var responses []*httptest.ResponseRecorder
+	for _, email := range []string{"john@doe.it", "unknown@doe.it"} {
+		requestBody, _ := json.Marshal(request.ForgotPasswordRequest{Email: email})
+		httpRequest, _ := http.NewRequest("POST", "/password/forgot", bytes.NewBuffer(requestBody))
+		httpRequest.Header.Set("Content-Type", "application/json")
+		responseRecorder := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(responseRecorder, httpRequest)
+		responses = append(responses, responseRecorder)
+	}
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, responses[0].Code)
+	assert.Equal(t, responses[0].Code, responses[1].Code)
+	assert.Equal(t, responses[0].Body.String(), responses[1].Body.String())
+}
+
+func TestResetPasswordUsingValidTokenReturnsOK(t *testing.T) {
+	// Arrange
+	mockPasswordResetService := new(mock_repositories.MockPasswordResetService)
+	mockResetRequest := request.ResetPasswordRequest{Token: "Reset-Token-Mock-1234", NewPassword: "Sup3r$ecurePassw0rd"}
+	mockPasswordResetService.On("Reset", mockResetRequest).Return(nil)
+
+	router := setupPasswordRouter(mockPasswordResetService)
+
+	requestBody, _ := json.Marshal(mockResetRequest)
+	httpRequest, _ := http.NewRequest("POST", "/password/reset", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+}
+
+func TestResetPasswordUsingInvalidTokenReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockPasswordResetService := new(mock_repositories.MockPasswordResetService)
+	mockResetRequest := request.ResetPasswordRequest{Token: "Reset-Token-Mock-1234", NewPassword: "Sup3r$ecurePassw0rd"}
+	mockPasswordResetService.On("Reset", mockResetRequest).Return(errors.NewInvalidPasswordResetTokenError(400))
+
+	router := setupPasswordRouter(mockPasswordResetService)
+
+	requestBody, _ := json.Marshal(mockResetRequest)
+	httpRequest, _ := http.NewRequest("POST", "/password/reset", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+}
+
+func TestCheckPasswordReturnsScoreAndSuggestions(t *testing.T) {
+	// Arrange
+	mockPasswordCheckService := new(mock_repositories.MockPasswordCheckService)
+	mockCheckRequest := request.PasswordCheckRequest{Password: "John1990!", FullName: "John Doe", Email: "john@doe.it"}
+	mockCheckResponse := response.PasswordCheckResponse{
+		Score:       0,
+		Entropy:     16.2,
+		Valid:       false,
+		Violations:  []string{errors.NewInsufficientPasswordLengthError(13, 400).Error()},
+		Suggestions: []string{validation.SuggestionPersonal, validation.SuggestionDate},
+	}
+	mockPasswordCheckService.On("Check", mockCheckRequest).Return(mockCheckResponse)
+
+	router := setupPasswordCheckRouter(mockPasswordCheckService)
+
+	requestBody, _ := json.Marshal(mockCheckRequest)
+	httpRequest, _ := http.NewRequest("POST", "/password/check", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	var checkResponse response.PasswordCheckResponse
+	json.Unmarshal(responseRecorder.Body.Bytes(), &checkResponse)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, mockCheckResponse, checkResponse)
+}
+
+func TestCheckPasswordWithoutPasswordReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockPasswordCheckService := new(mock_repositories.MockPasswordCheckService)
+	router := setupPasswordCheckRouter(mockPasswordCheckService)
+
+	httpRequest, _ := http.NewRequest("POST", "/password/check", bytes.NewBufferString(`{"email":"john@doe.it"}`))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockPasswordCheckService.AssertNotCalled(t, "Check", mock.Anything)
+}
+
+func TestCheckPasswordOverMaximumLengthReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockPasswordCheckService := new(mock_repositories.MockPasswordCheckService)
+	router := setupPasswordCheckRouter(mockPasswordCheckService)
+
+	requestBody, _ := json.Marshal(request.PasswordCheckRequest{Password: strings.Repeat("a", 257)})
+	httpRequest, _ := http.NewRequest("POST", "/password/check", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockPasswordCheckService.AssertNotCalled(t, "Check", mock.Anything)
+}
@@ -0,0 +1,90 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/routes"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestSessionRoute struct {
+}
+
+// Setup
+func setupSessionRouter(mockService *mock_repositories.MockTokenRevocationService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterSessionRoutes(router, mockService, gatewayAuthMiddleware)
+
+	return router
+}
+
+// Router Integration Tests
+func TestLogoutReturnsHTTPStatusOk(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockTokenRevocationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	logoutRequest := request.LogoutRequest{RefreshToken: "Refresh-Token-Mock-1234"}
+	mockService.On("Logout", logoutRequest, "", 1).Return()
+
+	router := setupSessionRouter(mockService, mockAPIGatewayMiddleware)
+
+	requestBody, _ := json.Marshal(logoutRequest)
+	httpRequest, _ := http.NewRequest("POST", "/logout", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRevokeSessionsAsAdminReturnsHTTPStatusOk(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockTokenRevocationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 2)
+	mockService.On("RevokeAllSessions", 1).Return()
+
+	router := setupSessionRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/users/1/sessions/revoke", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRevokeSessionsAsUserReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockTokenRevocationService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupSessionRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/users/1/sessions/revoke", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "RevokeAllSessions", 1)
+}
@@ -2,18 +2,29 @@ package routes_test
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
-	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/routes"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/converter"
 	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/validation"
 	mock_repositories "flyhorizons-userservice/tests/mocks"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type TestUserRoute struct {
@@ -28,32 +39,86 @@ func setupUserRouter(mockService *mock_repositories.MockUserService, gatewayAuth
 	return router
 }
 
-func getUsers() []models.User {
-	return []models.User{
+// Routes behind the real middleware, with an access token issued to a partner app through the authorization code flow
+func setupUserRouterWithDelegatedToken(t *testing.T, mockService *mock_repositories.MockUserService) (*gin.Engine, string) {
+	signingKey, _ := authentication.NewHMACSigningKey("key-1", []byte("secret"))
+	signer := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+	mockRevocationService := new(mock_repositories.MockTokenRevocationService)
+	mockRevocationService.On("IsRevoked", mock.Anything, mock.Anything, mock.Anything).Return(false)
+
+	codeVerifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	codeChallenge := sha256.Sum256([]byte(codeVerifier))
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockClientRepo := new(mock_repositories.MockOAuthClientRepository)
+	mockCodeRepo := new(mock_repositories.MockAuthorizationCodeRepository)
+	mockClientRepo.On("GetByClientID", "partner-app").Return(entities.OAuthClientEntity{ID: 2, ClientID: "partner-app", Public: true})
+	mockCodeRepo.On("GetByCodeHash", authentication.HashOpaqueToken("code-1")).Return(entities.AuthorizationCodeEntity{
+		ID:            1,
+		ClientID:      "partner-app",
+		UserID:        2,
+		RedirectURI:   "https://partner.example/callback",
+		Scope:         "openid",
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(codeChallenge[:]),
+		ExpiresAt:     time.Now().Add(time.Minute),
+	})
+	mockCodeRepo.On("MarkAsUsed", 1, mock.Anything).Return(true)
+	// The signed in account is an admin
+	mockRepo.On("GetByID", 2).Return(entities.UserEntity{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: int(enums.Admin)})
+
+	authorizationService := services.NewAuthorizationService(mockRepo, mockClientRepo, mockCodeRepo, new(mock_repositories.MockTokenRevocationRepository), new(mock_repositories.MockOAuthClientService), converter.UserConverter{}, signer, signer, "https://auth.flyhorizons.example", "https://flyhorizons.example/oauth/consent")
+	tokenResponse, err := authorizationService.ExchangeAuthorizationCode(request.AuthorizationCodeRequest{
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example/callback",
+		ClientID:     "partner-app",
+		CodeVerifier: codeVerifier,
+	})
+	assert.NoError(t, err)
+
+	router := gin.Default()
+	routes.RegisterUserRoutes(router, mockService, authentication.NewGatewayAuthMiddleware(signer, mockRevocationService))
+
+	return router, tokenResponse.AccessToken
+}
+
+func getUserProfiles() []response.UserProfileResponse {
+	return []response.UserProfileResponse{
 		{
 			ID:          1,
 			FullName:    "John Doe",
 			Email:       "john@doe.it",
-			AccountType: 1,
-			Password:    "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", // 1234!
+			AccountType: enums.User,
 		},
 		{
 			ID:          2,
 			FullName:    "Jane Doe",
 			Email:       "jane@doe.nl",
-			AccountType: 0,
-			Password:    "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", // 4321!
+			AccountType: enums.Admin,
 		},
 	}
 }
 
+func getAdminUserResponses() []response.AdminUserResponse {
+	return []response.AdminUserResponse{
+		{ID: 1, FullName: "John Doe", Email: "john@doe.it", AccountType: enums.User, CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+		{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: enums.Admin, CreatedAt: time.Date(2025, time.March, 31, 10, 30, 0, 0, time.UTC)},
+	}
+}
+
+func getCreateUserRequest() request.CreateUserRequest {
+	return request.CreateUserRequest{
+		FullName: "John Doe",
+		Email:    "john@doe.it",
+		Password: "Sup3r$ecurePassw0rd",
+	}
+}
+
 // Router Integration Tests
 func TestGetAllAsAdminReturnsUsersJSON(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
 	bearerToken := "Bearer mocktoken12345"
-	mockUsers := getUsers()
+	mockUsers := getAdminUserResponses()
 	mockService.On("GetAll").Return(mockUsers)
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
@@ -71,7 +136,7 @@ func TestGetAllAsAdminReturnsUsersJSON(t *testing.T) {
 	assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 	// Unmarshal the JSON response
-	var users []models.User
+	var users []response.AdminUserResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &users)
 
 	assert.NoError(t, err)
@@ -105,12 +170,48 @@ func TestGetAllAsNonAdminRoleReturnsAccessDenied(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetAllUsingDelegatedAdminTokenReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	router, accessToken := setupUserRouterWithDelegatedToken(t, mockService)
+
+	httpRequest, _ := http.NewRequest("GET", "/users/", nil)
+	httpRequest.Header.Set("Authorization", "Bearer "+accessToken)
+
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "GetAll")
+}
+
+func TestGetOwnUserUsingDelegatedTokenReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	router, accessToken := setupUserRouterWithDelegatedToken(t, mockService)
+
+	httpRequest, _ := http.NewRequest("GET", "/users/2", nil)
+	httpRequest.Header.Set("Authorization", "Bearer "+accessToken)
+
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "GetByID", mock.Anything)
+}
+
 func TestGetByValidIDUsingLoggedInIDReturnsUserJSON(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
 	bearerToken := "Bearer mocktoken12345"
-	mockUser := getUsers()[0]
+	mockUser := getUserProfiles()[0]
 	mockUserID := mockUser.ID
 	userPtr := &mockUser
 	mockService.On("GetByID", mockUserID).Return(userPtr, nil)
@@ -130,7 +231,7 @@ func TestGetByValidIDUsingLoggedInIDReturnsUserJSON(t *testing.T) {
 	assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
 	// Unmarshal the JSON response
-	var user models.User
+	var user response.UserProfileResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
 
 	assert.NoError(t, err)
@@ -143,7 +244,7 @@ func TestGetByInvalidIDUsingLoggedInIDReturnsHTTPStatusError(t *testing.T) {
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 999)
 	bearerToken := "Bearer mocktoken12345"
-	mockUser := getUsers()[0]
+	mockUser := getUserProfiles()[0]
 	mockUserID := mockUser.ID
 	userPtr := &mockUser
 	mockService.On("GetByID", mockUserID).Return(userPtr, nil)
@@ -167,13 +268,14 @@ func TestCreateNonExistingUserReturnsCreatedUser(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := new(mock_repositories.MockGatewayAuthMiddleware)
-	mockUser := getUsers()[0]
-	mockService.On("Create", mockUser).Return(&mockUser, nil)
+	createRequest := getCreateUserRequest()
+	mockUser := getUserProfiles()[0]
+	mockService.On("Create", createRequest).Return(&mockUser, nil)
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	// Make the JSON to create the user
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(createRequest)
 	httpRequest, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(requestBody)) // JSON body
 	httpRequest.Header.Set("Content-Type", "application/json")                        // Set the Content-Type header
 	responseRecorder := httptest.NewRecorder()
@@ -184,7 +286,7 @@ func TestCreateNonExistingUserReturnsCreatedUser(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
 
-	var user models.User
+	var user response.UserProfileResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
 	assert.NoError(t, err)
 	assert.Equal(t, mockUser, user)
@@ -195,14 +297,14 @@ func TestCreateExistingUserReturnsHTTPStatusError(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := new(mock_repositories.MockGatewayAuthMiddleware)
-	mockUser := getUsers()[0]
+	createRequest := getCreateUserRequest()
 	errorCode := 409
-	mockService.On("Create", mockUser).Return(nil, errors.NewUserExistsError(mockUser.ID, errorCode))
+	mockService.On("Create", createRequest).Return(nil, errors.NewEmailInUseError(errorCode))
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	// Make the JSON to create the user
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(createRequest)
 	httpRequest, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(requestBody)) // JSON body
 	httpRequest.Header.Set("Content-Type", "application/json")                        // Set the Content-Type header
 	responseRecorder := httptest.NewRecorder()
@@ -218,14 +320,14 @@ func TestCreateUserWithUnsufficientPasswordLengthReturnsHTTPStatusError(t *testi
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := new(mock_repositories.MockGatewayAuthMiddleware)
-	mockUser := getUsers()[0]
+	createRequest := getCreateUserRequest()
 	errorCode := 400
-	mockService.On("Create", mockUser).Return(nil, errors.NewInsufficientPasswordLengthError(errorCode))
+	mockService.On("Create", createRequest).Return(nil, errors.NewInsufficientPasswordLengthError(13, errorCode))
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	// Make the JSON to create the user
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(createRequest)
 	httpRequest, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(requestBody)) // JSON body
 	httpRequest.Header.Set("Content-Type", "application/json")                        // Set the Content-Type header
 	responseRecorder := httptest.NewRecorder()
@@ -241,14 +343,14 @@ func TestCreateUserWithInsufficientPasswordComplexityReturnsHTTPStatusError(t *t
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := new(mock_repositories.MockGatewayAuthMiddleware)
-	mockUser := getUsers()[0]
+	createRequest := getCreateUserRequest()
 	errorCode := 400
-	mockService.On("Create", mockUser).Return(nil, errors.NewInsufficientPasswordComplexityError(errorCode))
+	mockService.On("Create", createRequest).Return(nil, errors.NewInsufficientPasswordComplexityError(validation.DefaultPasswordPolicy().RequiredClasses(), errorCode))
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	// Make the JSON to create the user
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(createRequest)
 	httpRequest, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(requestBody)) // JSON body
 	httpRequest.Header.Set("Content-Type", "application/json")                        // Set the Content-Type header
 	responseRecorder := httptest.NewRecorder()
@@ -264,14 +366,14 @@ func TestCreateUserWithCommonPasswordReturnsHTTPStatusError(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := new(mock_repositories.MockGatewayAuthMiddleware)
-	mockUser := getUsers()[0]
+	createRequest := getCreateUserRequest()
 	errorCode := 400
-	mockService.On("Create", mockUser).Return(nil, errors.NewCommonPasswordError(errorCode))
+	mockService.On("Create", createRequest).Return(nil, errors.NewCommonPasswordError(errorCode))
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	// Make the JSON to create the user
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(createRequest)
 	httpRequest, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(requestBody)) // JSON body
 	httpRequest.Header.Set("Content-Type", "application/json")                        // Set the Content-Type header
 	responseRecorder := httptest.NewRecorder()
@@ -283,19 +385,44 @@ func TestCreateUserWithCommonPasswordReturnsHTTPStatusError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
 }
 
+func TestGetByIDReturnsVersionAsETag(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockUser := getUserProfiles()[0]
+	mockUser.Version = 3
+	mockService.On("GetByID", mockUser.ID).Return(&mockUser, nil)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/users/1", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, `"3"`, responseRecorder.Header().Get("ETag"))
+	assert.NotContains(t, responseRecorder.Body.String(), "version")
+}
+
 func TestDeleteExistingUserAsMatchingRoleReturnsHTTPStatusOk(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
 	userID := 1
 	bearerToken := "Bearer mocktoken12345"
-	mockService.On("DeleteByID", userID).Return(true, nil)
+	mockService.On("DeleteByID", userID, 1).Return(true, nil)
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	url := fmt.Sprintf("/users/%d", userID)
 	httpRequest, _ := http.NewRequest("DELETE", url, nil)
 	httpRequest.Header.Set("Authorization", bearerToken)
+	httpRequest.Header.Set("If-Match", `"1"`)
 
 	responseRecorder := httptest.NewRecorder()
 
@@ -313,7 +440,7 @@ func TestDeleteNonExistingUserAsMatchingRoleReturnsHTTPStatusError(t *testing.T)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 999)
 	bearerToken := "Bearer mocktoken12345"
 	userID := 1
-	mockService.On("DeleteByID", userID).Return(false, errors.NewUserNotFoundError(userID, 404))
+	mockService.On("DeleteByID", userID, 1).Return(false, errors.NewUserNotFoundError(userID, 404))
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
@@ -334,22 +461,66 @@ func TestDeleteNonExistingUserAsMatchingRoleReturnsHTTPStatusError(t *testing.T)
 	assert.NoError(t, err)
 }
 
+func TestDeleteUserWithoutIfMatchReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("DELETE", "/users/1", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusPreconditionRequired, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestDeleteUserUsingStaleETagReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockService.On("DeleteByID", 1, 1).Return(false, errors.NewConcurrentModificationError(1, 412))
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("DELETE", "/users/1", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("If-Match", `"1"`)
+
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusPreconditionFailed, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestUpdateExistingUserAsMatchingRoleReturnsUpdatedUser(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
 	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
-	mockUser := getUsers()[0]
+	updateRequest := request.UpdateUserRequest{ID: 1, FullName: "John Doe"}
+	mockUser := getUserProfiles()[0]
 	userPtr := &mockUser
-	mockService.On("Update", mockUser).Return(userPtr, nil)
+	mockService.On("Update", updateRequest, 1).Return(userPtr, nil)
 	bearerToken := "Bearer mocktoken12345"
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	// Make the JSON to create the airport
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(updateRequest)
 	httpRequest, _ := http.NewRequest("PUT", "/users/", bytes.NewBuffer(requestBody))
 	httpRequest.Header.Set("Content-Type", "application/json") // Set the Content-Type header
 	httpRequest.Header.Set("Authorization", bearerToken)       // Set the Bearer token
+	httpRequest.Header.Set("If-Match", `"1"`)                  // Set the ETag of the user
 
 	responseRecorder := httptest.NewRecorder()
 
@@ -359,28 +530,31 @@ func TestUpdateExistingUserAsMatchingRoleReturnsUpdatedUser(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, responseRecorder.Code)
 
-	var user models.User
+	var user response.UserProfileResponse
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
 	assert.NoError(t, err)
 	assert.Equal(t, mockUser, user)
 	mockService.AssertExpectations(t)
 }
 
-func TestUpdateUserWithUnsufficientPasswordLengthReturnsHTTPStatusError(t *testing.T) {
+func TestUpdateNonMatchingUserReturnsAccessDenied(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
-	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
-	mockUser := getUsers()[0]
-	mockService.On("Update", mockUser).Return(nil, errors.NewInsufficientPasswordLengthError(400))
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 999)
+	updateRequest := request.UpdateUserRequest{ID: 1, FullName: "John Doe"}
+	mockUser := getUserProfiles()[0]
+	userPtr := &mockUser
+	mockService.On("Update", updateRequest, 1).Return(userPtr, nil)
 	bearerToken := "Bearer mocktoken12345"
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
 	// Make the JSON to create the airport
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(updateRequest)
 	httpRequest, _ := http.NewRequest("PUT", "/users/", bytes.NewBuffer(requestBody))
 	httpRequest.Header.Set("Content-Type", "application/json") // Set the Content-Type header
 	httpRequest.Header.Set("Authorization", bearerToken)       // Set the Bearer token
+	httpRequest.Header.Set("If-Match", `"1"`)                  // Set the ETag of the user
 
 	responseRecorder := httptest.NewRecorder()
 
@@ -388,24 +562,24 @@ func TestUpdateUserWithUnsufficientPasswordLengthReturnsHTTPStatusError(t *testi
 	router.ServeHTTP(responseRecorder, httpRequest)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+
+	var errResponse map[string]interface{}
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errResponse)
+	assert.NoError(t, err)
 }
 
-func TestUpdateUserWithUnsufficientPasswordComplexityReturnsHTTPStatusError(t *testing.T) {
+func TestUpdateUserWithoutIfMatchReturnsHTTPStatusError(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
-	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
-	mockUser := getUsers()[0]
-	mockService.On("Update", mockUser).Return(nil, errors.NewInsufficientPasswordComplexityError(400))
-	bearerToken := "Bearer mocktoken12345"
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
-	// Make the JSON to create the airport
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(request.UpdateUserRequest{ID: 1, FullName: "John Smith"})
 	httpRequest, _ := http.NewRequest("PUT", "/users/", bytes.NewBuffer(requestBody))
-	httpRequest.Header.Set("Content-Type", "application/json") // Set the Content-Type header
-	httpRequest.Header.Set("Authorization", bearerToken)       // Set the Bearer token
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
 
 	responseRecorder := httptest.NewRecorder()
 
@@ -413,60 +587,236 @@ func TestUpdateUserWithUnsufficientPasswordComplexityReturnsHTTPStatusError(t *t
 	router.ServeHTTP(responseRecorder, httpRequest)
 
 	// Assert
-	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Equal(t, http.StatusPreconditionRequired, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 }
 
-func TestUpdateUserWithCommonPasswordReturnsHTTPStatusError(t *testing.T) {
+func TestUpdateUserUsingStaleETagReturnsHTTPStatusError(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
-	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
-	mockUser := getUsers()[0]
-	mockService.On("Update", mockUser).Return(nil, errors.NewCommonPasswordError(400))
-	bearerToken := "Bearer mocktoken12345"
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	updateRequest := request.UpdateUserRequest{ID: 1, FullName: "John Smith"}
+	mockService.On("Update", updateRequest, 1).Return(nil, errors.NewConcurrentModificationError(1, 412))
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 
-	// Make the JSON to create the airport
-	requestBody, _ := json.Marshal(mockUser)
+	requestBody, _ := json.Marshal(updateRequest)
 	httpRequest, _ := http.NewRequest("PUT", "/users/", bytes.NewBuffer(requestBody))
-	httpRequest.Header.Set("Content-Type", "application/json") // Set the Content-Type header
-	httpRequest.Header.Set("Authorization", bearerToken)       // Set the Bearer token
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("If-Match", `"1"`)
 
 	responseRecorder := httptest.NewRecorder()
 
 	// Act
 	router.ServeHTTP(responseRecorder, httpRequest)
 
+	// Assert
+	assert.Equal(t, http.StatusPreconditionFailed, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func newPatchUserHTTPRequest(path string, contentType string, body string) *http.Request {
+	httpRequest, _ := http.NewRequest("PATCH", path, bytes.NewBufferString(body))
+	httpRequest.Header.Set("Content-Type", contentType)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("If-Match", `"1"`)
+	return httpRequest
+}
+
+func TestPatchUserUsingMergePatchReturnsPatchedUser(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockUser := getUserProfiles()[0]
+	mockUser.FullName = "John Smith"
+	changes := map[string]json.RawMessage{"full_name": json.RawMessage(`"John Smith"`)}
+	mockService.On("Patch", 1, changes, "user", 1).Return(&mockUser, nil)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "application/merge-patch+json", `{"full_name":"John Smith"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var user response.UserProfileResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &user)
+	assert.NoError(t, err)
+	assert.Equal(t, mockUser, user)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchUserUsingJSONPatchReturnsPatchedUser(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 2)
+	mockUser := getUserProfiles()[0]
+	changes := map[string]json.RawMessage{"account_type": json.RawMessage(`0`)}
+	mockService.On("Patch", 1, changes, "admin", 1).Return(&mockUser, nil)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "application/json-patch+json", `[{"op":"replace","path":"/account_type","value":0}]`))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchOtherUserAsUserReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 2)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "application/merge-patch+json", `{"full_name":"John Smith"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchUserUsingUnsupportedContentTypeReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "text/plain", `full_name=John Smith`))
+
+	// Assert
+	assert.Equal(t, http.StatusUnsupportedMediaType, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchUserUsingMalformedPatchReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "application/json-patch+json", `[{"op":"move","from":"/email","path":"/full_name"}]`))
+
 	// Assert
 	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestUpdateNonMatchingUserReturnsAccessDenied(t *testing.T) {
+func TestPatchFieldNotOnAllowListReturnsHTTPStatusError(t *testing.T) {
 	// Arrange
 	mockService := new(mock_repositories.MockUserService)
-	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 999)
-	mockUser := getUsers()[0]
-	userPtr := &mockUser
-	mockService.On("Update", mockUser).Return(userPtr, nil)
-	bearerToken := "Bearer mocktoken12345"
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	changes := map[string]json.RawMessage{"account_type": json.RawMessage(`0`)}
+	mockService.On("Patch", 1, changes, "user", 1).Return(nil, errors.NewFieldNotPatchableError("account_type", 403))
 
 	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
 
-	// Make the JSON to create the airport
-	requestBody, _ := json.Marshal(mockUser)
-	httpRequest, _ := http.NewRequest("PUT", "/users/", bytes.NewBuffer(requestBody))
-	httpRequest.Header.Set("Content-Type", "application/json") // Set the Content-Type header
-	httpRequest.Header.Set("Authorization", bearerToken)       // Set the Bearer token
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "application/merge-patch+json", `{"account_type":0}`))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func TestPatchUserReturnsNewETag(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	mockUser := getUserProfiles()[0]
+	mockUser.Version = 2
+	changes := map[string]json.RawMessage{"full_name": json.RawMessage(`"John Smith"`)}
+	mockService.On("Patch", 1, changes, "user", 1).Return(&mockUser, nil)
 
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
 	responseRecorder := httptest.NewRecorder()
 
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "application/merge-patch+json", `{"full_name":"John Smith"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, `"2"`, responseRecorder.Header().Get("ETag"))
+}
+
+func TestPatchOtherUserAsAdminUsingETagFromGetReturnsPatchedUser(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 2)
+	storedUser := getUserProfiles()[0]
+	storedUser.Version = 3
+	patchedUser := storedUser
+	patchedUser.FullName = "John Smith"
+	patchedUser.Version = 4
+	changes := map[string]json.RawMessage{"full_name": json.RawMessage(`"John Smith"`)}
+	mockService.On("GetByID", 1).Return(&storedUser, nil)
+	mockService.On("Patch", 1, changes, "admin", 3).Return(&patchedUser, nil)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	getRequest, _ := http.NewRequest("GET", "/users/1", nil)
+	getRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	getRecorder := httptest.NewRecorder()
+	router.ServeHTTP(getRecorder, getRequest)
+	patchRequest := newPatchUserHTTPRequest("/users/1", "application/merge-patch+json", `{"full_name":"John Smith"}`)
+	patchRequest.Header.Set("If-Match", getRecorder.Header().Get("ETag"))
+	patchRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(patchRecorder, patchRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, getRecorder.Code)
+	assert.Equal(t, `"3"`, getRecorder.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, patchRecorder.Code)
+	assert.Equal(t, `"4"`, patchRecorder.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchUserUsingWeakETagReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+	httpRequest := newPatchUserHTTPRequest("/users/1", "application/merge-patch+json", `{"full_name":"John Smith"}`)
+	httpRequest.Header.Set("If-Match", `W/"1"`)
+
 	// Act
 	router.ServeHTTP(responseRecorder, httpRequest)
 
 	// Assert
-	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	assert.Equal(t, http.StatusPreconditionFailed, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
 
-	var errResponse map[string]interface{}
-	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errResponse)
-	assert.NoError(t, err)
+func TestPatchUserUsingStaleETagReturnsHTTPStatusError(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockUserService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 1)
+	changes := map[string]json.RawMessage{"full_name": json.RawMessage(`"John Smith"`)}
+	mockService.On("Patch", 1, changes, "user", 1).Return(nil, errors.NewConcurrentModificationError(1, 412))
+
+	router := setupUserRouter(mockService, mockAPIGatewayMiddleware)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newPatchUserHTTPRequest("/users/1", "application/merge-patch+json", `{"full_name":"John Smith"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusPreconditionFailed, responseRecorder.Code)
+	mockService.AssertExpectations(t)
 }
@@ -0,0 +1,33 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAccountLockoutRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.AccountLockoutRepository = (*MockAccountLockoutRepository)(nil)
+
+func (m *MockAccountLockoutRepository) GetByUserID(userID int) entities.AccountLockoutEntity {
+	args := m.Called(userID)
+	return args.Get(0).(entities.AccountLockoutEntity)
+}
+
+func (m *MockAccountLockoutRepository) IncrementFailedAttempts(userID int) entities.AccountLockoutEntity {
+	args := m.Called(userID)
+	return args.Get(0).(entities.AccountLockoutEntity)
+}
+
+func (m *MockAccountLockoutRepository) Lock(userID int, lockedUntil time.Time) {
+	m.Called(userID, lockedUntil)
+}
+
+func (m *MockAccountLockoutRepository) Reset(userID int) {
+	m.Called(userID)
+}
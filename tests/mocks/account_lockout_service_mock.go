@@ -0,0 +1,31 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAccountLockoutService struct {
+	mock.Mock
+}
+
+var _ interfaces.AccountLockoutService = (*MockAccountLockoutService)(nil)
+
+func (m *MockAccountLockoutService) Check(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountLockoutService) RecordFailure(userID int) {
+	m.Called(userID)
+}
+
+func (m *MockAccountLockoutService) RecordSuccess(userID int) {
+	m.Called(userID)
+}
+
+func (m *MockAccountLockoutService) Unlock(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
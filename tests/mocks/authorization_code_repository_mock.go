@@ -0,0 +1,29 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthorizationCodeRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.AuthorizationCodeRepository = (*MockAuthorizationCodeRepository)(nil)
+
+func (m *MockAuthorizationCodeRepository) Create(code entities.AuthorizationCodeEntity) entities.AuthorizationCodeEntity {
+	args := m.Called(code)
+	return args.Get(0).(entities.AuthorizationCodeEntity)
+}
+
+func (m *MockAuthorizationCodeRepository) GetByCodeHash(codeHash string) entities.AuthorizationCodeEntity {
+	args := m.Called(codeHash)
+	return args.Get(0).(entities.AuthorizationCodeEntity)
+}
+
+func (m *MockAuthorizationCodeRepository) MarkAsUsed(id int, accessTokenID string) bool {
+	args := m.Called(id, accessTokenID)
+	return args.Bool(0)
+}
@@ -0,0 +1,46 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthorizationService struct {
+	mock.Mock
+}
+
+var _ interfaces.AuthorizationService = (*MockAuthorizationService)(nil)
+
+func (m *MockAuthorizationService) Authorize(authorizeRequest request.AuthorizeRequest) (string, error) {
+	args := m.Called(authorizeRequest)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthorizationService) GrantConsent(consentRequest request.ConsentRequest, userID int) (string, error) {
+	args := m.Called(consentRequest, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthorizationService) ExchangeAuthorizationCode(codeRequest request.AuthorizationCodeRequest) (*response.OAuthTokenResponse, error) {
+	args := m.Called(codeRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.OAuthTokenResponse), args.Error(1)
+}
+
+func (m *MockAuthorizationService) UserInfo(userID int, scope string) (*response.UserInfoResponse, error) {
+	args := m.Called(userID, scope)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserInfoResponse), args.Error(1)
+}
+
+func (m *MockAuthorizationService) Discovery() response.OpenIDConfigurationResponse {
+	args := m.Called()
+	return args.Get(0).(response.OpenIDConfigurationResponse)
+}
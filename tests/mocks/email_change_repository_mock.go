@@ -0,0 +1,43 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmailChangeRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.EmailChangeRepository = (*MockEmailChangeRepository)(nil)
+
+func (m *MockEmailChangeRepository) Create(change entities.EmailChangeRequestEntity) entities.EmailChangeRequestEntity {
+	args := m.Called(change)
+	return args.Get(0).(entities.EmailChangeRequestEntity)
+}
+
+func (m *MockEmailChangeRepository) GetByConfirmTokenHash(tokenHash string) entities.EmailChangeRequestEntity {
+	args := m.Called(tokenHash)
+	return args.Get(0).(entities.EmailChangeRequestEntity)
+}
+
+func (m *MockEmailChangeRepository) GetByRevertTokenHash(tokenHash string) entities.EmailChangeRequestEntity {
+	args := m.Called(tokenHash)
+	return args.Get(0).(entities.EmailChangeRequestEntity)
+}
+
+func (m *MockEmailChangeRepository) MarkAsConfirmed(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+func (m *MockEmailChangeRepository) MarkAsReverted(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+func (m *MockEmailChangeRepository) CancelPendingForUser(userID int) {
+	m.Called(userID)
+}
@@ -0,0 +1,29 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmailChangeService struct {
+	mock.Mock
+}
+
+var _ interfaces.EmailChangeService = (*MockEmailChangeService)(nil)
+
+func (m *MockEmailChangeService) RequestChange(userID int, changeRequest request.ChangeEmailRequest) error {
+	args := m.Called(userID, changeRequest)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeService) Confirm(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeService) Revert(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
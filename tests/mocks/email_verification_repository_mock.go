@@ -0,0 +1,39 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmailVerificationRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.EmailVerificationRepository = (*MockEmailVerificationRepository)(nil)
+
+func (m *MockEmailVerificationRepository) Create(token entities.EmailVerificationTokenEntity) entities.EmailVerificationTokenEntity {
+	args := m.Called(token)
+	return args.Get(0).(entities.EmailVerificationTokenEntity)
+}
+
+func (m *MockEmailVerificationRepository) GetByTokenHash(tokenHash string) entities.EmailVerificationTokenEntity {
+	args := m.Called(tokenHash)
+	return args.Get(0).(entities.EmailVerificationTokenEntity)
+}
+
+func (m *MockEmailVerificationRepository) MarkAsUsed(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+func (m *MockEmailVerificationRepository) InvalidateAllForUser(userID int) {
+	m.Called(userID)
+}
+
+func (m *MockEmailVerificationRepository) CountCreatedSince(userID int, since time.Time) int {
+	args := m.Called(userID, since)
+	return args.Int(0)
+}
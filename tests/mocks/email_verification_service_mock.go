@@ -0,0 +1,29 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+var _ interfaces.EmailVerificationService = (*MockEmailVerificationService)(nil)
+
+func (m *MockEmailVerificationService) SendVerification(user models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) Verify(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) Resend(resendRequest request.ResendVerificationRequest) {
+	m.Called(resendRequest)
+}
@@ -0,0 +1,18 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+var _ interfaces.EventPublisher = (*MockEventPublisher)(nil)
+
+func (m *MockEventPublisher) Publish(queue string, event interface{}) error {
+	args := m.Called(queue, event)
+	return args.Error(0)
+}
@@ -7,8 +7,10 @@ import (
 
 type MockGatewayAuthMiddleware struct {
 	mock.Mock
-	Role string
-	ID   int
+	Role     string
+	ID       int
+	ClientID string
+	Scope    string
 }
 
 // Constructor to initialize MockGatewayAuthMiddleware with a role
@@ -19,9 +21,26 @@ func NewMockGatewayAuthMiddleware(role string, id int) *MockGatewayAuthMiddlewar
 	}
 }
 
+// Constructor to initialize MockGatewayAuthMiddleware for a client credentials token
+func NewMockServiceGatewayAuthMiddleware(clientID string, scope string) *MockGatewayAuthMiddleware {
+	return &MockGatewayAuthMiddleware{
+		Role:     "service",
+		ClientID: clientID,
+		Scope:    scope,
+	}
+}
+
 // Middleware function now uses the role from the struct
 func (m *MockGatewayAuthMiddleware) GatewayAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if m.ClientID != "" {
+			c.Set("client_id", m.ClientID)
+			c.Set("scope", m.Scope)
+			c.Set("role", m.Role)
+			c.Next()
+			return
+		}
+
 		c.Set("user_id", m.ID)
 		c.Set("sub", m.ID)
 		c.Set("role", m.Role)
@@ -30,3 +49,8 @@ func (m *MockGatewayAuthMiddleware) GatewayAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Delegated tokens are not simulated, the mock accepts the same identity on every route
+func (m *MockGatewayAuthMiddleware) DelegatedAuthMiddleware() gin.HandlerFunc {
+	return m.GatewayAuthMiddleware()
+}
@@ -0,0 +1,19 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIntrospectionService struct {
+	mock.Mock
+}
+
+var _ interfaces.IntrospectionService = (*MockIntrospectionService)(nil)
+
+func (m *MockIntrospectionService) Introspect(token string) response.IntrospectionResponse {
+	args := m.Called(token)
+	return args.Get(0).(response.IntrospectionResponse)
+}
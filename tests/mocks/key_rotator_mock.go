@@ -0,0 +1,27 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockKeyRotator struct {
+	mock.Mock
+}
+
+var _ interfaces.KeyRotator = (*MockKeyRotator)(nil)
+
+func (m *MockKeyRotator) Rotate() (*response.SigningKeyResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.SigningKeyResponse), args.Error(1)
+}
+
+func (m *MockKeyRotator) Keys() []response.SigningKeyResponse {
+	args := m.Called()
+	return args.Get(0).([]response.SigningKeyResponse)
+}
@@ -14,10 +14,34 @@ type MockLoginService struct {
 
 var _ interfaces.LoginService = (*MockLoginService)(nil)
 
-func (m *MockLoginService) Login(loginRequest request.LoginRequest) (*response.LoginResponse, error) {
+func (m *MockLoginService) Login(loginRequest request.LoginRequest, ip string) (*response.LoginResponse, error) {
 	args := m.Called(loginRequest)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*response.LoginResponse), args.Error(1)
 }
+
+func (m *MockLoginService) Refresh(refreshRequest request.RefreshTokenRequest, ip string) (*response.LoginResponse, error) {
+	args := m.Called(refreshRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.LoginResponse), args.Error(1)
+}
+
+func (m *MockLoginService) VerifyMFA(mfaRequest request.MFALoginRequest, ip string) (*response.LoginResponse, error) {
+	args := m.Called(mfaRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.LoginResponse), args.Error(1)
+}
+
+func (m *MockLoginService) LoginWithPasskey(passkeyRequest request.PasskeyLoginRequest, ip string) (*response.LoginResponse, error) {
+	args := m.Called(passkeyRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.LoginResponse), args.Error(1)
+}
@@ -0,0 +1,18 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailer struct {
+	mock.Mock
+}
+
+var _ interfaces.Mailer = (*MockMailer)(nil)
+
+func (m *MockMailer) Send(to string, subject string, body string) error {
+	args := m.Called(to, subject, body)
+	return args.Error(0)
+}
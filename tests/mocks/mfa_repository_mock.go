@@ -0,0 +1,75 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMFARepository struct {
+	mock.Mock
+}
+
+var _ interfaces.MFARepository = (*MockMFARepository)(nil)
+
+func (m *MockMFARepository) GetFactor(userID int) entities.MFAFactorEntity {
+	args := m.Called(userID)
+	return args.Get(0).(entities.MFAFactorEntity)
+}
+
+func (m *MockMFARepository) SaveFactor(factor entities.MFAFactorEntity) {
+	m.Called(factor)
+}
+
+func (m *MockMFARepository) DeleteFactor(userID int) {
+	m.Called(userID)
+}
+
+func (m *MockMFARepository) UseTimeStep(userID int, step int64) bool {
+	args := m.Called(userID, step)
+	return args.Bool(0)
+}
+
+func (m *MockMFARepository) CreateChallenge(challenge entities.MFAChallengeEntity) entities.MFAChallengeEntity {
+	args := m.Called(challenge)
+	return args.Get(0).(entities.MFAChallengeEntity)
+}
+
+func (m *MockMFARepository) GetChallengeByTokenHash(tokenHash string) entities.MFAChallengeEntity {
+	args := m.Called(tokenHash)
+	return args.Get(0).(entities.MFAChallengeEntity)
+}
+
+func (m *MockMFARepository) IncrementChallengeAttempts(id int) {
+	m.Called(id)
+}
+
+func (m *MockMFARepository) MarkChallengeAsUsed(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+func (m *MockMFARepository) CountChallengeFailures(userID int, since time.Time) int {
+	args := m.Called(userID, since)
+	return args.Int(0)
+}
+
+func (m *MockMFARepository) RevokeChallenges(userID int) {
+	m.Called(userID)
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(userID int, recoveryCodes []entities.MFARecoveryCodeEntity) {
+	m.Called(userID, recoveryCodes)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID int, codeHash string) bool {
+	args := m.Called(userID, codeHash)
+	return args.Bool(0)
+}
+
+func (m *MockMFARepository) CountRecoveryCodes(userID int) int {
+	args := m.Called(userID)
+	return args.Int(0)
+}
@@ -0,0 +1,67 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMFAService struct {
+	mock.Mock
+}
+
+var _ interfaces.MFAService = (*MockMFAService)(nil)
+
+func (m *MockMFAService) Enroll(userID int) (*response.MFAEnrollmentResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.MFAEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockMFAService) EnrollWithChallenge(mfaToken string) (*response.MFAEnrollmentResponse, error) {
+	args := m.Called(mfaToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.MFAEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockMFAService) Activate(userID int, code string) error {
+	args := m.Called(userID, code)
+	return args.Error(0)
+}
+
+func (m *MockMFAService) Disable(userID int, code string) error {
+	args := m.Called(userID, code)
+	return args.Error(0)
+}
+
+func (m *MockMFAService) RegenerateRecoveryCodes(userID int, code string) (*response.MFARecoveryCodesResponse, error) {
+	args := m.Called(userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.MFARecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockMFAService) Status(userID int) response.MFAStatusResponse {
+	args := m.Called(userID)
+	return args.Get(0).(response.MFAStatusResponse)
+}
+
+func (m *MockMFAService) BeginChallenge(account models.User) (*response.LoginResponse, error) {
+	args := m.Called(account)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.LoginResponse), args.Error(1)
+}
+
+func (m *MockMFAService) VerifyChallenge(mfaToken string, code string) (int, error) {
+	args := m.Called(mfaToken, code)
+	return args.Int(0), args.Error(1)
+}
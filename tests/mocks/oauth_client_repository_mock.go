@@ -0,0 +1,24 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOAuthClientRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.OAuthClientRepository = (*MockOAuthClientRepository)(nil)
+
+func (m *MockOAuthClientRepository) GetByClientID(clientID string) entities.OAuthClientEntity {
+	args := m.Called(clientID)
+	return args.Get(0).(entities.OAuthClientEntity)
+}
+
+func (m *MockOAuthClientRepository) Create(client entities.OAuthClientEntity) entities.OAuthClientEntity {
+	args := m.Called(client)
+	return args.Get(0).(entities.OAuthClientEntity)
+}
@@ -0,0 +1,41 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOAuthClientService struct {
+	mock.Mock
+}
+
+var _ interfaces.OAuthClientService = (*MockOAuthClientService)(nil)
+
+func (m *MockOAuthClientService) Authenticate(clientID string, clientSecret string) bool {
+	args := m.Called(clientID, clientSecret)
+	return args.Bool(0)
+}
+
+func (m *MockOAuthClientService) Register(registerRequest request.RegisterClientRequest) (*response.RegisterClientResponse, error) {
+	args := m.Called(registerRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.RegisterClientResponse), args.Error(1)
+}
+
+func (m *MockOAuthClientService) IssueClientCredentialsToken(clientID string, clientSecret string, scope string) (*response.OAuthTokenResponse, error) {
+	args := m.Called(clientID, clientSecret, scope)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.OAuthTokenResponse), args.Error(1)
+}
+
+func (m *MockOAuthClientService) AuthorizeIntrospection(clientID string, clientSecret string) error {
+	args := m.Called(clientID, clientSecret)
+	return args.Error(0)
+}
@@ -0,0 +1,19 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordChangeService struct {
+	mock.Mock
+}
+
+var _ interfaces.PasswordChangeService = (*MockPasswordChangeService)(nil)
+
+func (m *MockPasswordChangeService) ChangePassword(userID int, changeRequest request.ChangePasswordRequest) error {
+	args := m.Called(userID, changeRequest)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordCheckService struct {
+	mock.Mock
+}
+
+var _ interfaces.PasswordCheckService = (*MockPasswordCheckService)(nil)
+
+func (m *MockPasswordCheckService) Check(checkRequest request.PasswordCheckRequest) response.PasswordCheckResponse {
+	args := m.Called(checkRequest)
+	return args.Get(0).(response.PasswordCheckResponse)
+}
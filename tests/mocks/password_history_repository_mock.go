@@ -0,0 +1,28 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.PasswordHistoryRepository = (*MockPasswordHistoryRepository)(nil)
+
+func (m *MockPasswordHistoryRepository) Create(historyEntity entities.PasswordHistoryEntity) entities.PasswordHistoryEntity {
+	args := m.Called(historyEntity)
+	return args.Get(0).(entities.PasswordHistoryEntity)
+}
+
+func (m *MockPasswordHistoryRepository) GetRecentByUserID(userID int, limit int) []entities.PasswordHistoryEntity {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]entities.PasswordHistoryEntity)
+}
+
+func (m *MockPasswordHistoryRepository) DeleteAllButRecent(userID int, keep int) {
+	m.Called(userID, keep)
+}
@@ -0,0 +1,22 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordHistoryService struct {
+	mock.Mock
+}
+
+var _ interfaces.PasswordHistoryService = (*MockPasswordHistoryService)(nil)
+
+func (m *MockPasswordHistoryService) CheckReuse(userID int, currentHash string, password string, depth int) error {
+	args := m.Called(userID, currentHash, password, depth)
+	return args.Error(0)
+}
+
+func (m *MockPasswordHistoryService) Remember(userID int, replacedHash string, depth int) {
+	m.Called(userID, replacedHash, depth)
+}
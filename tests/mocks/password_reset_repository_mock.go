@@ -0,0 +1,33 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.PasswordResetRepository = (*MockPasswordResetRepository)(nil)
+
+func (m *MockPasswordResetRepository) Create(token entities.PasswordResetTokenEntity) entities.PasswordResetTokenEntity {
+	args := m.Called(token)
+	return args.Get(0).(entities.PasswordResetTokenEntity)
+}
+
+func (m *MockPasswordResetRepository) GetByTokenHash(tokenHash string) entities.PasswordResetTokenEntity {
+	args := m.Called(tokenHash)
+	return args.Get(0).(entities.PasswordResetTokenEntity)
+}
+
+func (m *MockPasswordResetRepository) MarkAsUsed(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+func (m *MockPasswordResetRepository) InvalidateAllForUser(userID int) {
+	m.Called(userID)
+}
@@ -0,0 +1,23 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordResetService struct {
+	mock.Mock
+}
+
+var _ interfaces.PasswordResetService = (*MockPasswordResetService)(nil)
+
+func (m *MockPasswordResetService) Forgot(forgotRequest request.ForgotPasswordRequest) {
+	m.Called(forgotRequest)
+}
+
+func (m *MockPasswordResetService) Reset(resetRequest request.ResetPasswordRequest) error {
+	args := m.Called(resetRequest)
+	return args.Error(0)
+}
@@ -0,0 +1,41 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.RefreshTokenRepository = (*MockRefreshTokenRepository)(nil)
+
+func (m *MockRefreshTokenRepository) Create(refreshToken entities.RefreshTokenEntity) entities.RefreshTokenEntity {
+	args := m.Called(refreshToken)
+	return args.Get(0).(entities.RefreshTokenEntity)
+}
+
+func (m *MockRefreshTokenRepository) GetByTokenHash(tokenHash string) entities.RefreshTokenEntity {
+	args := m.Called(tokenHash)
+	return args.Get(0).(entities.RefreshTokenEntity)
+}
+
+func (m *MockRefreshTokenRepository) MarkAsUsed(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string) {
+	m.Called(familyID)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(userID int) {
+	m.Called(userID)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUserExceptFamily(userID int, familyID string) {
+	m.Called(userID, familyID)
+}
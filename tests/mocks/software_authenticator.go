@@ -0,0 +1,192 @@
+package mock_repositories
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+)
+
+// Software WebAuthn authenticator with an ES256 passkey, so the ceremonies can be tested without hardware
+type SoftwareAuthenticator struct {
+	RPID              string
+	Origin            string
+	CredentialID      []byte
+	SignCount         uint32 // Increased before every assertion when CountSignatures is set
+	CountSignatures   bool
+	UserVerified      bool
+	AttestationFormat string // "none" or "packed" (self attestation)
+	privateKey        *ecdsa.PrivateKey
+}
+
+func NewSoftwareAuthenticator(rpID string, origin string) *SoftwareAuthenticator {
+	privateKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	credentialID := make([]byte, 16)
+	rand.Read(credentialID)
+
+	return &SoftwareAuthenticator{
+		RPID:              rpID,
+		Origin:            origin,
+		CredentialID:      credentialID,
+		UserVerified:      true,
+		AttestationFormat: "none",
+		privateKey:        privateKey,
+	}
+}
+
+func (authenticator *SoftwareAuthenticator) EncodedCredentialID() string {
+	return base64.RawURLEncoding.EncodeToString(authenticator.CredentialID)
+}
+
+// Public key of the passkey as COSE_Key, the way it is stored after registration
+func (authenticator *SoftwareAuthenticator) COSEPublicKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	authenticator.privateKey.X.FillBytes(x)
+	authenticator.privateKey.Y.FillBytes(y)
+
+	return encodeCBOR(cborMap{
+		{int64(1), int64(2)},  // kty: EC2
+		{int64(3), int64(-7)}, // alg: ES256
+		{int64(-1), int64(1)}, // crv: P-256
+		{int64(-2), x},        // x coordinate
+		{int64(-3), y},        // y coordinate
+	})
+}
+
+// Response of navigator.credentials.create() for the given challenge
+func (authenticator *SoftwareAuthenticator) Register(challenge string) request.PasskeyRegistrationRequest {
+	clientDataJSON := authenticator.clientDataJSON("webauthn.create", challenge)
+
+	credentialIDLength := make([]byte, 2)
+	binary.BigEndian.PutUint16(credentialIDLength, uint16(len(authenticator.CredentialID)))
+	attestedCredentialData := append(make([]byte, 16), credentialIDLength...) // Empty AAGUID
+	attestedCredentialData = append(attestedCredentialData, authenticator.CredentialID...)
+	attestedCredentialData = append(attestedCredentialData, authenticator.COSEPublicKey()...)
+	authData := append(authenticator.authenticatorData(0x40), attestedCredentialData...)
+
+	statement := cborMap{}
+	if authenticator.AttestationFormat == "packed" {
+		clientDataHash := sha256.Sum256(clientDataJSON)
+		statement = cborMap{
+			{"alg", int64(-7)},
+			{"sig", authenticator.sign(append(append([]byte{}, authData...), clientDataHash[:]...))},
+		}
+	}
+
+	attestationObject := encodeCBOR(cborMap{
+		{"fmt", authenticator.AttestationFormat},
+		{"attStmt", statement},
+		{"authData", authData},
+	})
+
+	return request.PasskeyRegistrationRequest{
+		ID:   authenticator.EncodedCredentialID(),
+		Type: "public-key",
+		Response: request.PasskeyAttestationResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			AttestationObject: base64.RawURLEncoding.EncodeToString(attestationObject),
+		},
+	}
+}
+
+// Response of navigator.credentials.get() for the given challenge
+func (authenticator *SoftwareAuthenticator) Login(challenge string, userHandle string) request.PasskeyLoginRequest {
+	if authenticator.CountSignatures {
+		authenticator.SignCount++
+	}
+
+	clientDataJSON := authenticator.clientDataJSON("webauthn.get", challenge)
+	authData := authenticator.authenticatorData(0)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signature := authenticator.sign(append(append([]byte{}, authData...), clientDataHash[:]...))
+
+	return request.PasskeyLoginRequest{
+		ID:   authenticator.EncodedCredentialID(),
+		Type: "public-key",
+		Response: request.PasskeyAssertionResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+			Signature:         base64.RawURLEncoding.EncodeToString(signature),
+			UserHandle:        userHandle,
+		},
+	}
+}
+
+func (authenticator *SoftwareAuthenticator) clientDataJSON(clientDataType string, challenge string) []byte {
+	clientDataJSON, _ := json.Marshal(map[string]interface{}{
+		"type":        clientDataType,
+		"challenge":   challenge,
+		"origin":      authenticator.Origin,
+		"crossOrigin": false,
+	})
+	return clientDataJSON
+}
+
+func (authenticator *SoftwareAuthenticator) authenticatorData(extraFlags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(authenticator.RPID))
+	flags := byte(0x01) | extraFlags // User present
+	if authenticator.UserVerified {
+		flags |= 0x04
+	}
+
+	signCount := make([]byte, 4)
+	binary.BigEndian.PutUint32(signCount, authenticator.SignCount)
+
+	return append(append(rpIDHash[:], flags), signCount...)
+}
+
+func (authenticator *SoftwareAuthenticator) sign(data []byte) []byte {
+	hash := sha256.Sum256(data)
+	signature, _ := ecdsa.SignASN1(rand.Reader, authenticator.privateKey, hash[:])
+	return signature
+}
+
+// CBOR map that keeps the order of its entries
+type cborMap []cborEntry
+
+type cborEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// Encodes the CBOR types authenticators use: integers, byte and text strings and maps
+func encodeCBOR(value interface{}) []byte {
+	switch typed := value.(type) {
+	case int64:
+		if typed < 0 {
+			return cborHeader(1, uint64(-1-typed))
+		}
+		return cborHeader(0, uint64(typed))
+	case []byte:
+		return append(cborHeader(2, uint64(len(typed))), typed...)
+	case string:
+		return append(cborHeader(3, uint64(len(typed))), typed...)
+	case cborMap:
+		encoded := cborHeader(5, uint64(len(typed)))
+		for _, entry := range typed {
+			encoded = append(encoded, encodeCBOR(entry.Key)...)
+			encoded = append(encoded, encodeCBOR(entry.Value)...)
+		}
+		return encoded
+	default:
+		panic("cbor: unsupported type")
+	}
+}
+
+func cborHeader(majorType byte, argument uint64) []byte {
+	switch {
+	case argument < 24:
+		return []byte{majorType<<5 | byte(argument)}
+	case argument <= 0xff:
+		return []byte{majorType<<5 | 24, byte(argument)}
+	case argument <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{majorType<<5 | 25}, uint16(argument))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{majorType<<5 | 26}, uint32(argument))
+	}
+}
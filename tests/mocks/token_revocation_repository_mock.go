@@ -0,0 +1,36 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.TokenRevocationRepository = (*MockTokenRevocationRepository)(nil)
+
+func (m *MockTokenRevocationRepository) RevokeToken(revokedToken entities.RevokedTokenEntity) {
+	m.Called(revokedToken)
+}
+
+func (m *MockTokenRevocationRepository) IsTokenRevoked(jti string) bool {
+	args := m.Called(jti)
+	return args.Bool(0)
+}
+
+func (m *MockTokenRevocationRepository) RevokeSessions(sessionRevocation entities.SessionRevocationEntity) {
+	m.Called(sessionRevocation)
+}
+
+func (m *MockTokenRevocationRepository) GetSessionRevocation(userID int) entities.SessionRevocationEntity {
+	args := m.Called(userID)
+	return args.Get(0).(entities.SessionRevocationEntity)
+}
+
+func (m *MockTokenRevocationRepository) DeleteExpired() {
+	m.Called()
+}
@@ -0,0 +1,32 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTokenRevocationService struct {
+	mock.Mock
+}
+
+var _ interfaces.TokenRevocationService = (*MockTokenRevocationService)(nil)
+
+func (m *MockTokenRevocationService) Logout(logoutRequest request.LogoutRequest, jti string, userID int, expiresAt time.Time) {
+	m.Called(logoutRequest, jti, userID)
+}
+
+func (m *MockTokenRevocationService) RevokeAllSessions(userID int) {
+	m.Called(userID)
+}
+
+func (m *MockTokenRevocationService) RevokeOtherSessions(userID int, refreshToken string) {
+	m.Called(userID, refreshToken)
+}
+
+func (m *MockTokenRevocationService) IsRevoked(jti string, userID int, issuedAt time.Time) bool {
+	args := m.Called(jti, userID)
+	return args.Bool(0)
+}
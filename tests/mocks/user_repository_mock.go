@@ -33,16 +33,31 @@ func (m *MockUserRepository) Create(user entities.UserEntity) entities.UserEntit
 	return args.Get(0).(entities.UserEntity)
 }
 
-func (m *MockUserRepository) DeleteByID(id int) bool {
-	args := m.Called(id)
+func (m *MockUserRepository) DeleteByID(id int, version int) bool {
+	args := m.Called(id, version)
 	return args.Bool(0)
 }
 
-func (m *MockUserRepository) Update(user entities.UserEntity) entities.UserEntity {
-	args := m.Called(user)
-	return args.Get(0).(entities.UserEntity)
+func (m *MockUserRepository) UpdateColumns(userID int, version int, columns map[string]interface{}) bool {
+	args := m.Called(userID, version, columns)
+	return args.Bool(0)
 }
 
 func (m *MockUserRepository) SaveLastLoginTime(id int) {
 	m.Called(id)
 }
+
+func (m *MockUserRepository) UpdatePassword(id int, passwordHash string) bool {
+	args := m.Called(id, passwordHash)
+	return args.Bool(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
+
+func (m *MockUserRepository) UpdateEmail(id int, email string) bool {
+	args := m.Called(id, email)
+	return args.Bool(0)
+}
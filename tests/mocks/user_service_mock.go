@@ -1,7 +1,9 @@
 package mock_repositories
 
 import (
-	"flyhorizons-userservice/models"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
 	"flyhorizons-userservice/services/interfaces"
 
 	"github.com/stretchr/testify/mock"
@@ -13,17 +15,17 @@ type MockUserService struct {
 
 var _ interfaces.UserService = (*MockUserService)(nil)
 
-func (m *MockUserService) GetAll() []models.User {
+func (m *MockUserService) GetAll() []response.AdminUserResponse {
 	args := m.Called()
-	return args.Get(0).([]models.User)
+	return args.Get(0).([]response.AdminUserResponse)
 }
 
-func (m *MockUserService) GetByID(userID int) (*models.User, error) {
+func (m *MockUserService) GetByID(userID int) (*response.UserProfileResponse, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.User), args.Error(1)
+	return args.Get(0).(*response.UserProfileResponse), args.Error(1)
 }
 
 func (m *MockUserService) UserExists(id int) bool {
@@ -31,23 +33,31 @@ func (m *MockUserService) UserExists(id int) bool {
 	return args.Bool(0)
 }
 
-func (m *MockUserService) Create(user models.User) (*models.User, error) {
-	args := m.Called(user)
+func (m *MockUserService) Create(createRequest request.CreateUserRequest) (*response.UserProfileResponse, error) {
+	args := m.Called(createRequest)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.User), args.Error(1)
+	return args.Get(0).(*response.UserProfileResponse), args.Error(1)
 }
 
-func (m *MockUserService) DeleteByID(id int) (bool, error) {
-	args := m.Called(id)
+func (m *MockUserService) DeleteByID(id int, version int) (bool, error) {
+	args := m.Called(id, version)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockUserService) Update(user models.User) (*models.User, error) {
-	args := m.Called(user)
+func (m *MockUserService) Update(updateRequest request.UpdateUserRequest, version int) (*response.UserProfileResponse, error) {
+	args := m.Called(updateRequest, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.UserProfileResponse), args.Error(1)
+}
+
+func (m *MockUserService) Patch(id int, changes map[string]json.RawMessage, role string, version int) (*response.UserProfileResponse, error) {
+	args := m.Called(id, changes, role, version)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.User), args.Error(1)
+	return args.Get(0).(*response.UserProfileResponse), args.Error(1)
 }
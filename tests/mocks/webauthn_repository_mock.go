@@ -0,0 +1,54 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebAuthnRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.WebAuthnRepository = (*MockWebAuthnRepository)(nil)
+
+func (m *MockWebAuthnRepository) CreateCredential(credential entities.WebAuthnCredentialEntity) entities.WebAuthnCredentialEntity {
+	args := m.Called(credential)
+	return args.Get(0).(entities.WebAuthnCredentialEntity)
+}
+
+func (m *MockWebAuthnRepository) GetCredentialByCredentialID(credentialID string) entities.WebAuthnCredentialEntity {
+	args := m.Called(credentialID)
+	return args.Get(0).(entities.WebAuthnCredentialEntity)
+}
+
+func (m *MockWebAuthnRepository) GetCredentialsByUserID(userID int) []entities.WebAuthnCredentialEntity {
+	args := m.Called(userID)
+	return args.Get(0).([]entities.WebAuthnCredentialEntity)
+}
+
+func (m *MockWebAuthnRepository) UpdateSignCount(id int, previousSignCount int64, signCount int64) bool {
+	args := m.Called(id, previousSignCount, signCount)
+	return args.Bool(0)
+}
+
+func (m *MockWebAuthnRepository) DeleteCredential(userID int, credentialID string) bool {
+	args := m.Called(userID, credentialID)
+	return args.Bool(0)
+}
+
+func (m *MockWebAuthnRepository) CreateChallenge(challenge entities.WebAuthnChallengeEntity) entities.WebAuthnChallengeEntity {
+	args := m.Called(challenge)
+	return args.Get(0).(entities.WebAuthnChallengeEntity)
+}
+
+func (m *MockWebAuthnRepository) GetChallengeByHash(challengeHash string) entities.WebAuthnChallengeEntity {
+	args := m.Called(challengeHash)
+	return args.Get(0).(entities.WebAuthnChallengeEntity)
+}
+
+func (m *MockWebAuthnRepository) MarkChallengeAsUsed(id int) bool {
+	args := m.Called(id)
+	return args.Bool(0)
+}
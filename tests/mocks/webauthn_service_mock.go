@@ -0,0 +1,54 @@
+package mock_repositories
+
+import (
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebAuthnService struct {
+	mock.Mock
+}
+
+var _ interfaces.WebAuthnService = (*MockWebAuthnService)(nil)
+
+func (m *MockWebAuthnService) BeginRegistration(userID int) (*response.PasskeyRegistrationOptionsResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.PasskeyRegistrationOptionsResponse), args.Error(1)
+}
+
+func (m *MockWebAuthnService) FinishRegistration(userID int, registrationRequest request.PasskeyRegistrationRequest) (*response.PasskeyResponse, error) {
+	args := m.Called(userID, registrationRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.PasskeyResponse), args.Error(1)
+}
+
+func (m *MockWebAuthnService) BeginLogin() (*response.PasskeyLoginOptionsResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*response.PasskeyLoginOptionsResponse), args.Error(1)
+}
+
+func (m *MockWebAuthnService) FinishLogin(loginRequest request.PasskeyLoginRequest) (int, error) {
+	args := m.Called(loginRequest)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebAuthnService) Credentials(userID int) []response.PasskeyResponse {
+	args := m.Called(userID)
+	return args.Get(0).([]response.PasskeyResponse)
+}
+
+func (m *MockWebAuthnService) DeleteCredential(userID int, credentialID string) error {
+	args := m.Called(userID, credentialID)
+	return args.Error(0)
+}
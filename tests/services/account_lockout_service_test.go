@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestAccountLockoutService struct {
+}
+
+// Setup
+func getLockoutPolicy() services.LockoutPolicy {
+	return services.LockoutPolicy{
+		DelayThreshold: 3,
+		BaseDelay:      time.Second,
+		MaxDelay:       time.Minute,
+		LockThreshold:  10,
+		LockDuration:   15 * time.Minute,
+	}
+}
+
+func setupAccountLockoutService() (*mock_repositories.MockUserRepository, *mock_repositories.MockAccountLockoutRepository, *services.AccountLockoutService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockLockoutRepo := new(mock_repositories.MockAccountLockoutRepository)
+	lockoutService := services.NewAccountLockoutService(mockLockoutRepo, mockRepo, getLockoutPolicy())
+	return mockRepo, mockLockoutRepo, lockoutService
+}
+
+// Service Unit Tests
+func TestLockoutPolicyDelayGrowsExponentiallyUpToMaximum(t *testing.T) {
+	// Arrange
+	policy := getLockoutPolicy()
+
+	// Act & Assert
+	assert.Equal(t, time.Duration(0), policy.Delay(2))
+	assert.Equal(t, time.Second, policy.Delay(3))
+	assert.Equal(t, 2*time.Second, policy.Delay(4))
+	assert.Equal(t, 32*time.Second, policy.Delay(8))
+	assert.Equal(t, time.Minute, policy.Delay(9))
+	assert.Equal(t, time.Minute, policy.Delay(1000))
+}
+
+func TestCheckLockedAccountThrowsExceptionUntilLockExpires(t *testing.T) {
+	// Arrange
+	_, mockLockoutRepo, lockoutService := setupAccountLockoutService()
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	mockLockoutRepo.On("GetByUserID", 1).Return(entities.AccountLockoutEntity{UserID: 1, LockedUntil: &lockedUntil})
+
+	// Act
+	err := lockoutService.Check(1)
+
+	// Assert
+	lockedErr, ok := err.(*errors.AccountLockedError)
+	assert.True(t, ok)
+	assert.Equal(t, 423, lockedErr.ErrorCode)
+	assert.InDelta(t, 600, lockedErr.RetryAfterSeconds(), 1)
+}
+
+func TestCheckAfterLockExpiredAllowsLogin(t *testing.T) {
+	// Arrange
+	_, mockLockoutRepo, lockoutService := setupAccountLockoutService()
+	lockedUntil := time.Now().Add(-time.Second)
+	mockLockoutRepo.On("GetByUserID", 1).Return(entities.AccountLockoutEntity{UserID: 1, LockedUntil: &lockedUntil})
+
+	// Act
+	err := lockoutService.Check(1)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestCheckWithinBackOffThrowsException(t *testing.T) {
+	// Arrange
+	_, mockLockoutRepo, lockoutService := setupAccountLockoutService()
+	lastFailedAt := time.Now()
+	mockLockoutRepo.On("GetByUserID", 1).Return(entities.AccountLockoutEntity{UserID: 1, FailedAttempts: 5, LastFailedAt: &lastFailedAt})
+
+	// Act
+	err := lockoutService.Check(1)
+
+	// Assert
+	lockedErr, ok := err.(*errors.AccountLockedError)
+	assert.True(t, ok)
+	assert.Equal(t, 4, lockedErr.RetryAfterSeconds())
+}
+
+func TestRecordFailureAtThresholdLocksAccount(t *testing.T) {
+	// Arrange
+	_, mockLockoutRepo, lockoutService := setupAccountLockoutService()
+	mockLockoutRepo.On("IncrementFailedAttempts", 1).Return(entities.AccountLockoutEntity{UserID: 1, FailedAttempts: 10})
+	mockLockoutRepo.On("Lock", 1, mock.MatchedBy(func(lockedUntil time.Time) bool {
+		return time.Until(lockedUntil) > 14*time.Minute && time.Until(lockedUntil) <= 15*time.Minute
+	})).Return()
+
+	// Act
+	lockoutService.RecordFailure(1)
+
+	// Assert
+	mockLockoutRepo.AssertExpectations(t)
+}
+
+func TestRecordFailureBelowThresholdDoesNotLockAccount(t *testing.T) {
+	// Arrange
+	_, mockLockoutRepo, lockoutService := setupAccountLockoutService()
+	mockLockoutRepo.On("IncrementFailedAttempts", 1).Return(entities.AccountLockoutEntity{UserID: 1, FailedAttempts: 9})
+
+	// Act
+	lockoutService.RecordFailure(1)
+
+	// Assert
+	mockLockoutRepo.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+}
+
+func TestUnlockUnknownAccountThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockLockoutRepo, lockoutService := setupAccountLockoutService()
+	mockRepo.On("GetByID", 99).Return(entities.UserEntity{})
+
+	// Act
+	err := lockoutService.Unlock(99)
+
+	// Assert
+	assert.Equal(t, errors.NewUserNotFoundError(99, 404), err)
+	mockLockoutRepo.AssertNotCalled(t, "Reset", mock.Anything)
+}
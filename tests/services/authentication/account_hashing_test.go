@@ -0,0 +1,151 @@
+package authentication_test
+
+import (
+	"flyhorizons-userservice/services/authentication"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestAccountHashing struct {
+}
+
+// Setup
+func setupBcryptAccountHashing(cost int) *authentication.AccountHashing {
+	policy := authentication.DefaultHashingPolicy()
+	policy.Algorithm = authentication.HashingAlgorithmBcrypt
+	policy.BcryptCost = cost
+	return authentication.NewAccountHashing(policy)
+}
+
+// Account Hashing Tests
+func TestHashPasswordUsesArgon2idByDefault(t *testing.T) {
+	// Arrange
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
+
+	// Act
+	passwordHash, err := accountHashing.HashPassword("Sup3r$ecurePassw0rd")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(passwordHash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+	assert.True(t, accountHashing.ComparePassword(passwordHash, "Sup3r$ecurePassw0rd"))
+	assert.False(t, accountHashing.ComparePassword(passwordHash, "Sup3r$ecurePassw0rd!"))
+	assert.False(t, accountHashing.NeedsRehash(passwordHash))
+}
+
+func TestComparePasswordRecognizesBcryptHash(t *testing.T) {
+	// Arrange
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
+	bcryptHash := "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2" // 1234!
+
+	// Act
+	matches := accountHashing.ComparePassword(bcryptHash, "1234!")
+
+	// Assert
+	assert.True(t, matches)
+	assert.True(t, accountHashing.NeedsRehash(bcryptHash))
+}
+
+func TestNeedsRehashUsingOutdatedBcryptCostReturnsTrue(t *testing.T) {
+	// Arrange
+	oldHash, _ := setupBcryptAccountHashing(4).HashPassword("1234!")
+	accountHashing := setupBcryptAccountHashing(5)
+
+	// Act
+	needsRehash := accountHashing.NeedsRehash(oldHash)
+
+	// Assert
+	assert.True(t, accountHashing.ComparePassword(oldHash, "1234!"))
+	assert.True(t, needsRehash)
+}
+
+func TestNeedsRehashUsingOutdatedArgon2idParametersReturnsTrue(t *testing.T) {
+	// Arrange
+	oldPolicy := authentication.DefaultHashingPolicy()
+	oldPolicy.Argon2id.Iterations = 1
+	oldHash, _ := authentication.NewAccountHashing(oldPolicy).HashPassword("1234!")
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
+
+	// Act
+	needsRehash := accountHashing.NeedsRehash(oldHash)
+
+	// Assert
+	assert.True(t, accountHashing.ComparePassword(oldHash, "1234!"))
+	assert.True(t, needsRehash)
+}
+
+func TestComparePasswordUsingUnknownHashReturnsFalse(t *testing.T) {
+	// Arrange
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
+
+	// Act
+	matches := accountHashing.ComparePassword("1234!", "1234!")
+
+	// Assert
+	assert.False(t, matches)
+}
+
+func TestHashPasswordUsingPepperStoresPepperVersion(t *testing.T) {
+	// Arrange
+	pepper, _ := authentication.NewPasswordPepper("2025-01", []byte("a-pepper-of-at-least-thirty-two-bytes"))
+	policy := authentication.DefaultHashingPolicy()
+	policy.Pepper = pepper
+	accountHashing := authentication.NewAccountHashing(policy)
+
+	// Act
+	passwordHash, err := accountHashing.HashPassword("1234!")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(passwordHash, "$pepper$2025-01$argon2id$"))
+	assert.True(t, accountHashing.ComparePassword(passwordHash, "1234!"))
+	assert.False(t, accountHashing.NeedsRehash(passwordHash))
+	// The hash can't be verified without the pepper
+	assert.False(t, authentication.NewAccountHashing(authentication.DefaultHashingPolicy()).ComparePassword(passwordHash, "1234!"))
+}
+
+func TestComparePasswordUsingRetiredPepperRequiresRehash(t *testing.T) {
+	// Arrange
+	oldPepper, _ := authentication.NewPasswordPepper("2024-01", []byte("the-retired-pepper-of-thirty-two-bytes"))
+	newPepper, _ := authentication.NewPasswordPepper("2025-01", []byte("a-pepper-of-at-least-thirty-two-bytes"))
+	oldPolicy := authentication.DefaultHashingPolicy()
+	oldPolicy.Pepper = oldPepper
+	oldHash, _ := authentication.NewAccountHashing(oldPolicy).HashPassword("1234!")
+	policy := authentication.DefaultHashingPolicy()
+	policy.Pepper = newPepper
+	policy.RetiredPeppers = []*authentication.PasswordPepper{oldPepper}
+	accountHashing := authentication.NewAccountHashing(policy)
+
+	// Act
+	matches := accountHashing.ComparePassword(oldHash, "1234!")
+
+	// Assert
+	assert.True(t, matches)
+	assert.True(t, accountHashing.NeedsRehash(oldHash))
+}
+
+func TestComparePasswordWithoutPepperRequiresRehashOncePepperIsConfigured(t *testing.T) {
+	// Arrange
+	oldHash, _ := authentication.NewAccountHashing(authentication.DefaultHashingPolicy()).HashPassword("1234!")
+	pepper, _ := authentication.NewPasswordPepper("2025-01", []byte("a-pepper-of-at-least-thirty-two-bytes"))
+	policy := authentication.DefaultHashingPolicy()
+	policy.Pepper = pepper
+	accountHashing := authentication.NewAccountHashing(policy)
+
+	// Act
+	matches := accountHashing.ComparePassword(oldHash, "1234!")
+
+	// Assert
+	assert.True(t, matches)
+	assert.True(t, accountHashing.NeedsRehash(oldHash))
+}
+
+func TestNewPasswordPepperUsingShortSecretThrowsException(t *testing.T) {
+	// Act
+	_, err := authentication.NewPasswordPepper("2025-01", []byte("too-short"))
+
+	// Assert
+	assert.Error(t, err)
+}
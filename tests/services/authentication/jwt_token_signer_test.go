@@ -0,0 +1,188 @@
+package authentication_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flyhorizons-userservice/services/authentication"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestJwtTokenSigner struct {
+}
+
+// Setup
+func writePrivateKey(t *testing.T, privateKey interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "private_key.pem")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	assert.NoError(t, err)
+
+	return path
+}
+
+func getClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   1,
+		"email": "john@doe.it",
+		"role":  "user",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+func newGatewayAuthMiddleware(signer *authentication.JwtTokenSigner, revoked bool) *authentication.GatewayAuthMiddlewareHandler {
+	mockRevocationService := new(mock_repositories.MockTokenRevocationService)
+	mockRevocationService.On("IsRevoked", mock.Anything, mock.Anything).Return(revoked)
+	return authentication.NewGatewayAuthMiddleware(signer, mockRevocationService)
+}
+
+func setupProtectedRouter(middleware *authentication.GatewayAuthMiddlewareHandler) *gin.Engine {
+	router := gin.Default()
+	router.GET("/protected", middleware.GatewayAuthMiddleware(), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"user_id": ctx.GetInt("user_id")})
+	})
+	return router
+}
+
+func callProtectedRoute(router *gin.Engine, token string) int {
+	httpRequest, _ := http.NewRequest("GET", "/protected", nil)
+	httpRequest.Header.Set("Authorization", "Bearer "+token)
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httpRequest)
+	return responseRecorder.Code
+}
+
+// Signer Tests
+func TestSignTokenWithAsymmetricKeysIsAcceptedByMiddleware(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+
+	testCases := map[string]interface{}{
+		authentication.AlgorithmRS256: rsaKey,
+		authentication.AlgorithmES256: ecdsaKey,
+		authentication.AlgorithmEdDSA: ed25519Key,
+	}
+
+	for algorithm, privateKey := range testCases {
+		t.Run(algorithm, func(t *testing.T) {
+			// Arrange
+			signingKey, err := authentication.LoadSigningKey(algorithm, "", writePrivateKey(t, privateKey))
+			assert.NoError(t, err)
+			signer := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+			router := setupProtectedRouter(newGatewayAuthMiddleware(signer, false))
+
+			// Act
+			token, err := signer.SignToken(getClaims())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, callProtectedRoute(router, token))
+		})
+	}
+}
+
+func TestSignTokenAddsKeyIDHeader(t *testing.T) {
+	// Arrange
+	signingKey, _ := authentication.NewHMACSigningKey("key-1", []byte("secret"))
+	signer := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+
+	// Act
+	token, err := signer.SignToken(getClaims())
+	parsedToken, _, parseErr := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, "key-1", parsedToken.Header["kid"])
+}
+
+func TestMiddlewareRejectsTokenSignedWithDifferentAlgorithm(t *testing.T) {
+	// Arrange
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+	signingKey, _ := authentication.NewSigningKey(authentication.AlgorithmEdDSA, "key-1", ed25519Key)
+	router := setupProtectedRouter(newGatewayAuthMiddleware(authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour)), false))
+	// HS256 token using the same key ID, signed with the public key as secret
+	hmacKey, _ := authentication.NewHMACSigningKey("key-1", signingKey.PublicKey.(ed25519.PublicKey))
+	token, _ := authentication.NewJwtTokenSigner(authentication.NewKeyRing(hmacKey, time.Hour)).SignToken(getClaims())
+
+	// Act
+	code := callProtectedRoute(router, token)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestLoadSigningKeyWithMismatchingAlgorithmThrowsException(t *testing.T) {
+	// Arrange
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	// Act
+	signingKey, err := authentication.LoadSigningKey(authentication.AlgorithmRS256, "", writePrivateKey(t, ecdsaKey))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, signingKey)
+}
+
+func TestJWKSContainsPublicKeyOnly(t *testing.T) {
+	// Arrange
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	signingKey, _ := authentication.NewSigningKey(authentication.AlgorithmES256, "key-1", ecdsaKey)
+	signer := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+
+	// Act
+	keySet := signer.JWKS()
+
+	// Assert
+	assert.Len(t, keySet.Keys, 1)
+	assert.Equal(t, "EC", keySet.Keys[0].KeyType)
+	assert.Equal(t, "P-256", keySet.Keys[0].Curve)
+	assert.Equal(t, "key-1", keySet.Keys[0].KeyID)
+	assert.NotEmpty(t, keySet.Keys[0].X)
+	assert.NotEmpty(t, keySet.Keys[0].Y)
+}
+
+func TestJWKSDoesNotPublishSymmetricKeys(t *testing.T) {
+	// Arrange
+	signingKey, _ := authentication.NewHMACSigningKey("key-1", []byte("secret"))
+	signer := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+
+	// Act
+	keySet := signer.JWKS()
+
+	// Assert
+	assert.Empty(t, keySet.Keys)
+}
+
+func TestMiddlewareRejectsRevokedToken(t *testing.T) {
+	// Arrange
+	signingKey, _ := authentication.NewHMACSigningKey("key-1", []byte("secret"))
+	signer := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+	router := setupProtectedRouter(newGatewayAuthMiddleware(signer, true))
+	token, _ := signer.SignToken(getClaims())
+
+	// Act
+	code := callProtectedRoute(router, token)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
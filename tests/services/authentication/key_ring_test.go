@@ -0,0 +1,236 @@
+package authentication_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestKeyRing struct {
+}
+
+// Setup
+func setupKeyRing(overlap time.Duration) (*authentication.KeyRing, *authentication.JwtTokenSigner) {
+	signingKey, _ := authentication.GenerateSigningKey(authentication.AlgorithmES256)
+	keyRing := authentication.NewKeyRing(signingKey, overlap)
+	return keyRing, authentication.NewJwtTokenSigner(keyRing)
+}
+
+func newSigningKeyCipher() *authentication.SigningKeyCipher {
+	signingKeyCipher, _ := authentication.NewSigningKeyCipher([]byte("an-encryption-secret-of-32-bytes"))
+	return signingKeyCipher
+}
+
+// Stands in for the database table the instances share
+type sharedSigningKeyStore struct {
+	keys []entities.SigningKeyEntity
+	err  error
+}
+
+func (store *sharedSigningKeyStore) Create(keyEntity entities.SigningKeyEntity) error {
+	if store.err != nil {
+		return store.err
+	}
+	store.keys = append(store.keys, keyEntity)
+	return nil
+}
+
+func (store *sharedSigningKeyStore) GetAll() []entities.SigningKeyEntity {
+	return store.keys
+}
+
+// Key Ring Tests
+func TestRotateKeepsRetiredKeyVerifiable(t *testing.T) {
+	// Arrange
+	keyRing, signer := setupKeyRing(time.Hour)
+	router := setupProtectedRouter(newGatewayAuthMiddleware(signer, false))
+	oldToken, _ := signer.SignToken(getClaims())
+
+	// Act
+	newKey, err := keyRing.RotateGenerated()
+	newToken, _ := signer.SignToken(getClaims())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, newKey.ID, keyRing.Active().ID)
+	assert.Equal(t, http.StatusOK, callProtectedRoute(router, oldToken))
+	assert.Equal(t, http.StatusOK, callProtectedRoute(router, newToken))
+	assert.Len(t, signer.JWKS().Keys, 2)
+}
+
+func TestRotateRejectsRetiredKeyAfterOverlap(t *testing.T) {
+	// Arrange
+	keyRing, signer := setupKeyRing(0)
+	router := setupProtectedRouter(newGatewayAuthMiddleware(signer, false))
+	oldToken, _ := signer.SignToken(getClaims())
+
+	// Act
+	_, err := keyRing.RotateGenerated()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, callProtectedRoute(router, oldToken))
+	assert.Len(t, signer.JWKS().Keys, 1)
+}
+
+func TestLookupUnknownKeyIDThrowsException(t *testing.T) {
+	// Arrange
+	keyRing, _ := setupKeyRing(time.Hour)
+
+	// Act
+	signingKey, err := keyRing.Lookup("unknown-kid")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, signingKey)
+}
+
+func TestKeysListsActiveAndRetiredKeys(t *testing.T) {
+	// Arrange
+	keyRing, signer := setupKeyRing(time.Hour)
+	oldKeyID := keyRing.Active().ID
+
+	// Act
+	rotatedKey, err := signer.Rotate()
+	keys := signer.Keys()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, rotatedKey.KeyID, keys[0].KeyID)
+	assert.Equal(t, "active", keys[0].Status)
+	assert.Equal(t, oldKeyID, keys[1].KeyID)
+	assert.Equal(t, "retired", keys[1].Status)
+	assert.NotNil(t, keys[1].VerifiableUntil)
+}
+
+func TestRotateGeneratedUsingHMACKeyThrowsException(t *testing.T) {
+	// Arrange
+	signingKey, _ := authentication.NewHMACSigningKey("default", []byte("shared-secret"))
+	keyRing := authentication.NewKeyRing(signingKey, time.Hour)
+
+	// Act
+	newKey, err := keyRing.RotateGenerated()
+	scheduleErr := keyRing.StartRotationSchedule(time.Hour)
+
+	// Assert
+	assert.Equal(t, errors.NewKeyRotationNotSupportedError(authentication.AlgorithmHS256, 409), err)
+	assert.Equal(t, errors.NewKeyRotationNotSupportedError(authentication.AlgorithmHS256, 409), scheduleErr)
+	assert.Nil(t, newKey)
+	assert.Equal(t, "default", keyRing.Active().ID)
+}
+
+func TestRotatedKeyIsSharedWithOtherInstances(t *testing.T) {
+	// Arrange
+	store := &sharedSigningKeyStore{}
+	configuredKey, _ := authentication.GenerateSigningKey(authentication.AlgorithmES256)
+	firstRing := authentication.NewKeyRing(configuredKey, time.Hour)
+	secondRing := authentication.NewKeyRing(configuredKey, time.Hour)
+	firstRing.UseStore(store, newSigningKeyCipher())
+	secondRing.UseStore(store, newSigningKeyCipher())
+	firstSigner := authentication.NewJwtTokenSigner(firstRing)
+	secondRouter := setupProtectedRouter(newGatewayAuthMiddleware(authentication.NewJwtTokenSigner(secondRing), false))
+
+	// Act
+	newKey, err := firstRing.RotateGenerated()
+	token, _ := firstSigner.SignToken(getClaims())
+
+	// Assert
+	assert.NoError(t, err)
+	// The other instance loads the key it has not seen yet when verifying
+	assert.Equal(t, http.StatusOK, callProtectedRoute(secondRouter, token))
+	assert.Equal(t, newKey.ID, secondRing.Active().ID)
+}
+
+func TestRestartedInstanceKeepsSharedKeys(t *testing.T) {
+	// Arrange
+	store := &sharedSigningKeyStore{}
+	configuredKey, _ := authentication.GenerateSigningKey(authentication.AlgorithmEdDSA)
+	keyRing := authentication.NewKeyRing(configuredKey, time.Hour)
+	keyRing.UseStore(store, newSigningKeyCipher())
+	oldKeyID := keyRing.Active().ID
+	newKey, _ := keyRing.RotateGenerated()
+
+	// Act
+	restartedRing := authentication.NewKeyRing(configuredKey, time.Hour)
+	err := restartedRing.UseStore(store, newSigningKeyCipher())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, newKey.ID, restartedRing.Active().ID)
+	retiredKey, lookupErr := restartedRing.Lookup(oldKeyID)
+	assert.NoError(t, lookupErr)
+	assert.Equal(t, oldKeyID, retiredKey.ID)
+}
+
+func TestRotatedKeyIsStoredEncrypted(t *testing.T) {
+	// Arrange
+	store := &sharedSigningKeyStore{}
+	configuredKey, _ := authentication.GenerateSigningKey(authentication.AlgorithmRS256)
+	keyRing := authentication.NewKeyRing(configuredKey, time.Hour)
+	keyRing.UseStore(store, newSigningKeyCipher())
+	keyRing.RotateGenerated()
+	otherCipher, _ := authentication.NewSigningKeyCipher([]byte("another-encryption-secret-32-byt"))
+
+	// Act
+	err := authentication.NewKeyRing(configuredKey, time.Hour).UseStore(store, otherCipher)
+
+	// Assert
+	assert.Len(t, store.keys, 1)
+	assert.NotContains(t, store.keys[0].PrivateKey, "PRIVATE KEY")
+	// Without the secret the stored key can't be used
+	assert.Error(t, err)
+}
+
+func TestRotateGeneratedWhenStoreFailsKeepsActiveKey(t *testing.T) {
+	// Arrange
+	store := &sharedSigningKeyStore{err: fmt.Errorf("connection refused")}
+	configuredKey, _ := authentication.GenerateSigningKey(authentication.AlgorithmES256)
+	keyRing := authentication.NewKeyRing(configuredKey, time.Hour)
+	keyRing.UseStore(store, newSigningKeyCipher())
+
+	// Act
+	newKey, err := keyRing.RotateGenerated()
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, newKey)
+	assert.Equal(t, configuredKey.ID, keyRing.Active().ID)
+}
+
+func TestNewlyConfiguredKeyTakesOverFromStoredKeys(t *testing.T) {
+	// Arrange
+	store := &sharedSigningKeyStore{}
+	oldKey, _ := authentication.GenerateSigningKey(authentication.AlgorithmES256)
+	oldRing := authentication.NewKeyRing(oldKey, time.Hour)
+	oldRing.UseStore(store, newSigningKeyCipher())
+	generatedKey, _ := oldRing.RotateGenerated()
+	store.keys[0].ActivatedAt = time.Now().Add(-time.Hour)
+
+	configuredKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	t.Setenv("JWT_SIGNING_ALGORITHM", authentication.AlgorithmES256)
+	t.Setenv("JWT_KEY_ID", "configured-key")
+	t.Setenv("JWT_PRIVATE_KEY_PATH", writePrivateKey(t, configuredKey))
+	keyRing, loadErr := authentication.LoadKeyRingFromEnv()
+	assert.NoError(t, loadErr)
+
+	// Act
+	err := keyRing.UseStore(store, newSigningKeyCipher())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "configured-key", keyRing.Active().ID)
+	// The generated key keeps verifying the tokens it signed
+	retiredKey, lookupErr := keyRing.Lookup(generatedKey.ID)
+	assert.NoError(t, lookupErr)
+	assert.Equal(t, generatedKey.ID, retiredKey.ID)
+}
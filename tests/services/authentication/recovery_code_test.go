@@ -0,0 +1,39 @@
+package authentication_test
+
+import (
+	"flyhorizons-userservice/services/authentication"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestRecoveryCode struct {
+}
+
+// Recovery Code Tests
+func TestGenerateRecoveryCodeReturnsFormattedCode(t *testing.T) {
+	// Act
+	first, err := authentication.GenerateRecoveryCode()
+	second, _ := authentication.GenerateRecoveryCode()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`), first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashRecoveryCodeIgnoresCaseDashesAndSpaces(t *testing.T) {
+	// Arrange
+	expectedHash := authentication.HashRecoveryCode("abcd-efgh-ijkl-mnop")
+
+	// Act
+	upperCaseHash := authentication.HashRecoveryCode("ABCD-EFGH-IJKL-MNOP")
+	spacedHash := authentication.HashRecoveryCode("abcd efgh ijkl mnop")
+	otherHash := authentication.HashRecoveryCode("abcd-efgh-ijkl-mnoq")
+
+	// Assert
+	assert.Equal(t, expectedHash, upperCaseHash)
+	assert.Equal(t, expectedHash, spacedHash)
+	assert.NotEqual(t, expectedHash, otherHash)
+}
@@ -0,0 +1,74 @@
+package authentication_test
+
+import (
+	"flyhorizons-userservice/services/authentication"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestTOTP struct {
+}
+
+// Base32 encoding of the RFC 6238 test secret "12345678901234567890"
+const rfcTestSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TOTP Tests
+func TestTOTPCodeMatchesRFCTestVectors(t *testing.T) {
+	testCases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unixTime, expectedCode := range testCases {
+		// Act
+		code, err := authentication.TOTPCode(rfcTestSecret, time.Unix(unixTime, 0))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCode, code)
+	}
+}
+
+func TestValidateTOTPAllowsOneStepOfClockDrift(t *testing.T) {
+	// Arrange
+	now := time.Unix(1111111109, 0)
+	previousCode, _ := authentication.TOTPCode(rfcTestSecret, now.Add(-30*time.Second))
+	expiredCode, _ := authentication.TOTPCode(rfcTestSecret, now.Add(-90*time.Second))
+
+	// Act
+	step, valid := authentication.ValidateTOTP(rfcTestSecret, previousCode, now)
+	_, expiredValid := authentication.ValidateTOTP(rfcTestSecret, expiredCode, now)
+
+	// Assert
+	assert.True(t, valid)
+	assert.Equal(t, now.Unix()/30-1, step)
+	assert.False(t, expiredValid)
+}
+
+func TestGenerateTOTPSecretReturnsUsableSecret(t *testing.T) {
+	// Act
+	secret, err := authentication.GenerateTOTPSecret()
+	code, codeErr := authentication.TOTPCode(secret, time.Now())
+	_, valid := authentication.ValidateTOTP(secret, code, time.Now())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, codeErr)
+	assert.Len(t, secret, 32)
+	assert.True(t, valid)
+}
+
+func TestTOTPURIContainsIssuerAndSecret(t *testing.T) {
+	// Act
+	uri := authentication.TOTPURI("FlyHorizons", "john@doe.it", rfcTestSecret)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/FlyHorizons:john@doe.it?"))
+	assert.Contains(t, uri, "secret="+rfcTestSecret)
+	assert.Contains(t, uri, "issuer=FlyHorizons")
+}
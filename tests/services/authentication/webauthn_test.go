@@ -0,0 +1,104 @@
+package authentication_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"flyhorizons-userservice/services/authentication"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestWebAuthn struct {
+}
+
+func decodeBase64URL(value string) []byte {
+	decoded, _ := base64.RawURLEncoding.DecodeString(value)
+	return decoded
+}
+
+// WebAuthn Tests
+func TestParseAttestationObjectOfSoftwareAuthenticatorReturnsCredential(t *testing.T) {
+	// Arrange
+	authenticator := mock_repositories.NewSoftwareAuthenticator("flyhorizons.test", "https://flyhorizons.test")
+	registration := authenticator.Register("challenge")
+
+	// Act
+	attestation, err := authentication.ParseAttestationObject(decodeBase64URL(registration.Response.AttestationObject))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "none", attestation.Format)
+	assert.Equal(t, authenticator.CredentialID, attestation.AuthData.CredentialID)
+	assert.Equal(t, authenticator.COSEPublicKey(), attestation.AuthData.CredentialPublicKey)
+	assert.True(t, attestation.AuthData.UserPresent())
+	assert.True(t, attestation.AuthData.UserVerified())
+	assert.True(t, authentication.MatchesRPIDHash(attestation.AuthData, "flyhorizons.test"))
+	assert.False(t, authentication.MatchesRPIDHash(attestation.AuthData, "evil.test"))
+}
+
+func TestVerifyPackedSelfAttestationRejectsTamperedClientData(t *testing.T) {
+	// Arrange
+	authenticator := mock_repositories.NewSoftwareAuthenticator("flyhorizons.test", "https://flyhorizons.test")
+	authenticator.AttestationFormat = "packed"
+	registration := authenticator.Register("challenge")
+	attestation, _ := authentication.ParseAttestationObject(decodeBase64URL(registration.Response.AttestationObject))
+	clientDataHash := sha256.Sum256(decodeBase64URL(registration.Response.ClientDataJSON))
+	tamperedHash := sha256.Sum256([]byte("tampered"))
+
+	// Act
+	validErr := attestation.Verify(clientDataHash[:])
+	tamperedErr := attestation.Verify(tamperedHash[:])
+
+	// Assert
+	assert.NoError(t, validErr)
+	assert.Error(t, tamperedErr)
+}
+
+func TestVerifyAssertionSignatureOfSoftwareAuthenticator(t *testing.T) {
+	// Arrange
+	authenticator := mock_repositories.NewSoftwareAuthenticator("flyhorizons.test", "https://flyhorizons.test")
+	other := mock_repositories.NewSoftwareAuthenticator("flyhorizons.test", "https://flyhorizons.test")
+	assertion := authenticator.Login("challenge", "")
+	authData := decodeBase64URL(assertion.Response.AuthenticatorData)
+	clientDataHash := sha256.Sum256(decodeBase64URL(assertion.Response.ClientDataJSON))
+	signature := decodeBase64URL(assertion.Response.Signature)
+
+	// Act
+	validErr := authentication.VerifyAssertionSignature(authenticator.COSEPublicKey(), authData, clientDataHash[:], signature)
+	otherKeyErr := authentication.VerifyAssertionSignature(other.COSEPublicKey(), authData, clientDataHash[:], signature)
+
+	// Assert
+	assert.NoError(t, validErr)
+	assert.Error(t, otherKeyErr)
+}
+
+func TestParseAuthenticatorDataRejectsMalformedData(t *testing.T) {
+	// Arrange
+	authenticator := mock_repositories.NewSoftwareAuthenticator("flyhorizons.test", "https://flyhorizons.test")
+	authData := decodeBase64URL(authenticator.Login("challenge", "").Response.AuthenticatorData)
+
+	// Act
+	_, validErr := authentication.ParseAuthenticatorData(authData)
+	_, shortErr := authentication.ParseAuthenticatorData(authData[:36])
+	_, trailingErr := authentication.ParseAuthenticatorData(append(authData, 0x00))
+
+	// Assert
+	assert.NoError(t, validErr)
+	assert.Error(t, shortErr)
+	assert.Error(t, trailingErr)
+}
+
+func TestParseCOSEPublicKeyRejectsPointNotOnCurve(t *testing.T) {
+	// Arrange
+	coseKey := mock_repositories.NewSoftwareAuthenticator("flyhorizons.test", "https://flyhorizons.test").COSEPublicKey()
+	// Flip a bit of the y coordinate, which is the last field of the key
+	coseKey[len(coseKey)-1] ^= 0x01
+
+	// Act
+	_, _, err := authentication.ParseCOSEPublicKey(coseKey)
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,341 @@
+package services_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/converter"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestAuthorizationService struct {
+}
+
+const codeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+// Setup
+func setupAuthorizationService() (*mock_repositories.MockUserRepository, *mock_repositories.MockOAuthClientRepository, *mock_repositories.MockAuthorizationCodeRepository, *mock_repositories.MockTokenRevocationRepository, *mock_repositories.MockOAuthClientService, *mock_repositories.MockJwtTokenSigner, *services.AuthorizationService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockClientRepo := new(mock_repositories.MockOAuthClientRepository)
+	mockCodeRepo := new(mock_repositories.MockAuthorizationCodeRepository)
+	mockRevocationRepo := new(mock_repositories.MockTokenRevocationRepository)
+	mockClientService := new(mock_repositories.MockOAuthClientService)
+	mockJwtTokenSigner := new(mock_repositories.MockJwtTokenSigner)
+	signingKey, _ := authentication.NewHMACSigningKey("key-1", []byte("secret"))
+	keyProvider := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+	authorizationService := services.NewAuthorizationService(mockRepo, mockClientRepo, mockCodeRepo, mockRevocationRepo, mockClientService, converter.UserConverter{}, mockJwtTokenSigner, keyProvider, "https://auth.flyhorizons.example/", "https://flyhorizons.example/oauth/consent")
+	return mockRepo, mockClientRepo, mockCodeRepo, mockRevocationRepo, mockClientService, mockJwtTokenSigner, authorizationService
+}
+
+func getPublicClientEntity() entities.OAuthClientEntity {
+	return entities.OAuthClientEntity{
+		ID:           2,
+		ClientID:     "partner-app",
+		Name:         "Partner App",
+		RedirectURIs: "https://partner.example/callback com.partner.app:/callback",
+		Public:       true,
+	}
+}
+
+func getCodeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func getAuthorizeRequest() request.AuthorizeRequest {
+	return request.AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            "partner-app",
+		RedirectURI:         "https://partner.example/callback",
+		Scope:               "openid email",
+		State:               "xyz",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       getCodeChallenge(codeVerifier),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+func getAuthorizationCodeEntity(code string) entities.AuthorizationCodeEntity {
+	return entities.AuthorizationCodeEntity{
+		ID:            1,
+		CodeHash:      authentication.HashOpaqueToken(code),
+		ClientID:      "partner-app",
+		UserID:        1,
+		RedirectURI:   "https://partner.example/callback",
+		Scope:         "openid email",
+		CodeChallenge: getCodeChallenge(codeVerifier),
+		Nonce:         "n-0S6_WzA2Mj",
+		AuthTime:      time.Now(),
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+}
+
+// Service Unit Tests
+func TestAuthorizeUsingValidRequestRedirectsToConsentPage(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, _, _, _, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+
+	// Act
+	redirect, err := authorizationService.Authorize(getAuthorizeRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	redirectURL, _ := url.Parse(redirect)
+	assert.Equal(t, "flyhorizons.example", redirectURL.Host)
+	assert.Equal(t, "/oauth/consent", redirectURL.Path)
+	assert.Equal(t, "partner-app", redirectURL.Query().Get("client_id"))
+	assert.Equal(t, "Partner App", redirectURL.Query().Get("client_name"))
+	assert.Equal(t, "openid email", redirectURL.Query().Get("scope"))
+	assert.Equal(t, "xyz", redirectURL.Query().Get("state"))
+	assert.Empty(t, redirectURL.Query().Get("code"))
+	mockCodeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestGrantConsentUsingApprovedRequestRedirectsWithCode(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, _, _, _, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+	var storedCode entities.AuthorizationCodeEntity
+	mockCodeRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		storedCode = args.Get(0).(entities.AuthorizationCodeEntity)
+	}).Return(entities.AuthorizationCodeEntity{})
+
+	// Act
+	redirect, err := authorizationService.GrantConsent(request.ConsentRequest{AuthorizeRequest: getAuthorizeRequest(), Approved: true}, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	redirectURL, _ := url.Parse(redirect)
+	code := redirectURL.Query().Get("code")
+	assert.Equal(t, "partner.example", redirectURL.Host)
+	assert.Equal(t, "xyz", redirectURL.Query().Get("state"))
+	assert.NotEmpty(t, code)
+	assert.Equal(t, authentication.HashOpaqueToken(code), storedCode.CodeHash)
+	assert.Equal(t, 1, storedCode.UserID)
+	assert.Equal(t, "openid email", storedCode.Scope)
+}
+
+func TestGrantConsentUsingDeniedRequestRedirectsWithAccessDenied(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, _, _, _, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+
+	// Act
+	redirect, err := authorizationService.GrantConsent(request.ConsentRequest{AuthorizeRequest: getAuthorizeRequest(), Approved: false}, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	redirectURL, _ := url.Parse(redirect)
+	assert.Equal(t, "partner.example", redirectURL.Host)
+	assert.Equal(t, "access_denied", redirectURL.Query().Get("error"))
+	assert.Empty(t, redirectURL.Query().Get("code"))
+	mockCodeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAuthorizeUsingUnregisteredRedirectURIThrowsException(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, _, _, _, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+	authorizeRequest := getAuthorizeRequest()
+	authorizeRequest.RedirectURI = "https://partner.example/callback/../attacker"
+
+	// Act
+	redirect, err := authorizationService.Authorize(authorizeRequest)
+
+	// Assert
+	assert.Empty(t, redirect)
+	assert.IsType(t, &errors.InvalidRedirectURIError{}, err)
+	mockCodeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAuthorizeWithoutCodeChallengeRedirectsWithError(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, _, _, _, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+	authorizeRequest := getAuthorizeRequest()
+	authorizeRequest.CodeChallenge = ""
+	authorizeRequest.CodeChallengeMethod = ""
+
+	// Act
+	redirect, err := authorizationService.Authorize(authorizeRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	redirectURL, _ := url.Parse(redirect)
+	assert.Equal(t, "invalid_request", redirectURL.Query().Get("error"))
+	assert.Empty(t, redirectURL.Query().Get("code"))
+	mockCodeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestExchangeAuthorizationCodeUsingValidVerifierReturnsAccessAndIDToken(t *testing.T) {
+	// Arrange
+	mockRepo, mockClientRepo, mockCodeRepo, _, _, mockJwtTokenSigner, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+	mockCodeRepo.On("GetByCodeHash", authentication.HashOpaqueToken("code-1")).Return(getAuthorizationCodeEntity("code-1"))
+	mockCodeRepo.On("MarkAsUsed", 1, mock.Anything).Return(true)
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	// The access token is limited to the granted scope
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		return claims["aud"] == "flyhorizons-api" && claims["scope"] == "openid email" && claims["azp"] == "partner-app" && claims["role"] == nil
+	})).Return("Mock Access Token", nil)
+	// The ID token is issued to the client
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		return claims["aud"] == "partner-app" && claims["sub"] == "1" && claims["nonce"] == "n-0S6_WzA2Mj" &&
+			claims["email"] == "john@doe.it" && claims["name"] == nil && claims["iss"] == "https://auth.flyhorizons.example"
+	})).Return("Mock ID Token", nil)
+
+	// Act
+	tokenResponse, err := authorizationService.ExchangeAuthorizationCode(request.AuthorizationCodeRequest{
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example/callback",
+		ClientID:     "partner-app",
+		CodeVerifier: codeVerifier,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Mock Access Token", tokenResponse.AccessToken)
+	assert.Equal(t, "Mock ID Token", tokenResponse.IDToken)
+	assert.Equal(t, "openid email", tokenResponse.Scope)
+}
+
+func TestExchangeAuthorizationCodeUsingWrongVerifierThrowsException(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, _, _, _, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+	mockCodeRepo.On("GetByCodeHash", authentication.HashOpaqueToken("code-1")).Return(getAuthorizationCodeEntity("code-1"))
+
+	// Act
+	tokenResponse, err := authorizationService.ExchangeAuthorizationCode(request.AuthorizationCodeRequest{
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example/callback",
+		ClientID:     "partner-app",
+		CodeVerifier: "an-intercepted-code-without-the-matching-verifier-value",
+	})
+
+	// Assert
+	assert.Nil(t, tokenResponse)
+	assert.IsType(t, &errors.InvalidGrantError{}, err)
+	mockCodeRepo.AssertNotCalled(t, "MarkAsUsed", mock.Anything)
+}
+
+func TestExchangeAuthorizationCodeUsingUsedCodeThrowsException(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, mockRevocationRepo, _, mockJwtTokenSigner, authorizationService := setupAuthorizationService()
+	usedAt := time.Now()
+	usedCode := getAuthorizationCodeEntity("code-1")
+	usedCode.UsedAt = &usedAt
+	usedCode.AccessTokenID = "jti-1"
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+	mockCodeRepo.On("GetByCodeHash", authentication.HashOpaqueToken("code-1")).Return(usedCode)
+	mockRevocationRepo.On("RevokeToken", mock.Anything).Return()
+
+	// Act
+	tokenResponse, err := authorizationService.ExchangeAuthorizationCode(request.AuthorizationCodeRequest{
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example/callback",
+		ClientID:     "partner-app",
+		CodeVerifier: codeVerifier,
+	})
+
+	// Assert
+	assert.Nil(t, tokenResponse)
+	assert.IsType(t, &errors.InvalidGrantError{}, err)
+	mockJwtTokenSigner.AssertNotCalled(t, "SignToken", mock.Anything)
+	// The access token issued for the first exchange is revoked
+	mockRevocationRepo.AssertCalled(t, "RevokeToken", mock.MatchedBy(func(revokedToken entities.RevokedTokenEntity) bool {
+		return revokedToken.JTI == "jti-1" && revokedToken.UserID == 1
+	}))
+}
+
+func TestExchangeAuthorizationCodeStoresIDOfIssuedAccessToken(t *testing.T) {
+	// Arrange
+	mockRepo, mockClientRepo, mockCodeRepo, _, _, mockJwtTokenSigner, authorizationService := setupAuthorizationService()
+	mockClientRepo.On("GetByClientID", "partner-app").Return(getPublicClientEntity())
+	mockCodeRepo.On("GetByCodeHash", authentication.HashOpaqueToken("code-1")).Return(getAuthorizationCodeEntity("code-1"))
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	var accessTokenID string
+	mockCodeRepo.On("MarkAsUsed", 1, mock.Anything).Run(func(args mock.Arguments) {
+		accessTokenID = args.String(1)
+	}).Return(true)
+	var signedClaims []jwt.MapClaims
+	mockJwtTokenSigner.On("SignToken", mock.Anything).Run(func(args mock.Arguments) {
+		signedClaims = append(signedClaims, args.Get(0).(jwt.MapClaims))
+	}).Return("Mock Token", nil)
+
+	// Act
+	_, err := authorizationService.ExchangeAuthorizationCode(request.AuthorizationCodeRequest{
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example/callback",
+		ClientID:     "partner-app",
+		CodeVerifier: codeVerifier,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, accessTokenID)
+	assert.Equal(t, accessTokenID, signedClaims[0]["jti"])
+}
+
+func TestExchangeAuthorizationCodeAsConfidentialClientWithoutSecretThrowsException(t *testing.T) {
+	// Arrange
+	_, mockClientRepo, mockCodeRepo, _, mockClientService, _, authorizationService := setupAuthorizationService()
+	confidentialClient := getPublicClientEntity()
+	confidentialClient.Public = false
+	mockClientRepo.On("GetByClientID", "partner-app").Return(confidentialClient)
+	mockClientService.On("Authenticate", "partner-app", "").Return(false)
+
+	// Act
+	tokenResponse, err := authorizationService.ExchangeAuthorizationCode(request.AuthorizationCodeRequest{
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example/callback",
+		ClientID:     "partner-app",
+		CodeVerifier: codeVerifier,
+	})
+
+	// Assert
+	assert.Nil(t, tokenResponse)
+	assert.IsType(t, &errors.InvalidClientError{}, err)
+	mockCodeRepo.AssertNotCalled(t, "GetByCodeHash", mock.Anything)
+}
+
+func TestUserInfoOnlyReturnsClaimsOfGrantedScope(t *testing.T) {
+	// Arrange
+	mockRepo, _, _, _, _, _, authorizationService := setupAuthorizationService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+
+	// Act
+	userInfo, err := authorizationService.UserInfo(1, "openid email")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "1", userInfo.Subject)
+	assert.Equal(t, "john@doe.it", userInfo.Email)
+	assert.Empty(t, userInfo.Name)
+}
+
+func TestDiscoveryUsesIssuerAndActiveSigningAlgorithm(t *testing.T) {
+	// Arrange
+	_, _, _, _, _, _, authorizationService := setupAuthorizationService()
+
+	// Act
+	discovery := authorizationService.Discovery()
+
+	// Assert
+	assert.Equal(t, "https://auth.flyhorizons.example", discovery.Issuer)
+	assert.Equal(t, "https://auth.flyhorizons.example/oauth/authorize", discovery.AuthorizationEndpoint)
+	assert.Equal(t, []string{authentication.AlgorithmHS256}, discovery.IDTokenSigningAlgValuesSupported)
+	assert.Equal(t, []string{"S256"}, discovery.CodeChallengeMethodsSupported)
+}
@@ -1,7 +1,10 @@
 package converter_test
 
 import (
+	"encoding/json"
 	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
 	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/services/converter"
 	"testing"
@@ -49,11 +52,14 @@ func TestConvertUserToUserEntityReturnsUserEntity(t *testing.T) {
 	userConverter := setup()
 	user := getUser()
 
+	expected := getUserEntity()
+	expected.CreatedAt = time.Time{}
+
 	// Act
 	userEntity := userConverter.ConvertUserToUserEntity(user)
 
 	// Assert
-	assert.Equal(t, userEntity, getUserEntity())
+	assert.Equal(t, expected, userEntity)
 }
 
 func TestConvertUserEntityToUserReturnsUser(t *testing.T) {
@@ -67,3 +73,55 @@ func TestConvertUserEntityToUserReturnsUser(t *testing.T) {
 	// Assert
 	assert.Equal(t, user, getUser())
 }
+
+func TestConvertCreateUserRequestToUserEntityStoresHashAndStartsUnverifiedUser(t *testing.T) {
+	// Arrange
+	userConverter := setup()
+	createRequest := request.CreateUserRequest{FullName: "Jane Doe", Email: "jane@doe.nl", Password: "4321!"}
+	passwordHash := getUserEntity().Password
+
+	// Act
+	userEntity := userConverter.ConvertCreateUserRequestToUserEntity(createRequest, passwordHash)
+
+	// Assert
+	assert.Equal(t, passwordHash, userEntity.Password)
+	assert.False(t, userEntity.EmailVerified)
+	assert.Equal(t, int(enums.User), userEntity.AccountType)
+	assert.Zero(t, userEntity.ID)
+	assert.False(t, userEntity.CreatedAt.IsZero())
+	assert.Equal(t, 1, userEntity.Version)
+}
+
+func TestConvertUpdateUserRequestToUserEntityKeepsStoredColumns(t *testing.T) {
+	// Arrange
+	userConverter := setup()
+	storedEntity := getUserEntity()
+	storedEntity.EmailVerified = true
+
+	// Act
+	userEntity := userConverter.ConvertUpdateUserRequestToUserEntity(request.UpdateUserRequest{ID: 2, FullName: "Jane Smith"}, storedEntity)
+
+	// Assert
+	assert.Equal(t, "Jane Smith", userEntity.FullName)
+	assert.Equal(t, storedEntity.Email, userEntity.Email)
+	assert.Equal(t, storedEntity.Password, userEntity.Password)
+	assert.Equal(t, storedEntity.CreatedAt, userEntity.CreatedAt)
+	assert.True(t, userEntity.EmailVerified)
+}
+
+func TestConvertUserEntityToResponsesDoesNotExposePasswordHash(t *testing.T) {
+	// Arrange
+	userConverter := setup()
+	userEntity := getUserEntity()
+
+	// Act
+	profileJSON, _ := json.Marshal(userConverter.ConvertUserEntityToUserProfileResponse(userEntity))
+	adminJSON, _ := json.Marshal(userConverter.ConvertUserEntityToAdminUserResponse(userEntity))
+	userJSON, _ := json.Marshal(userConverter.ConvertUserEntityToUser(userEntity))
+
+	// Assert
+	for _, body := range []string{string(profileJSON), string(adminJSON), string(userJSON)} {
+		assert.NotContains(t, body, userEntity.Password)
+		assert.NotContains(t, body, "password")
+	}
+}
@@ -0,0 +1,206 @@
+package services_test
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestEmailChangeService struct {
+}
+
+// Setup
+func setupEmailChangeService() (*mock_repositories.MockUserRepository, *mock_repositories.MockEmailChangeRepository, *mock_repositories.MockTokenRevocationService, *mock_repositories.MockMailer, *mock_repositories.MockEventPublisher, *services.EmailChangeService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockChangeRepo := new(mock_repositories.MockEmailChangeRepository)
+	mockRevocationService := new(mock_repositories.MockTokenRevocationService)
+	mockMailer := new(mock_repositories.MockMailer)
+	mockEventPublisher := new(mock_repositories.MockEventPublisher)
+	emailChangeService := services.NewEmailChangeService(mockRepo, mockChangeRepo, mockRevocationService, mockMailer, mockEventPublisher, "https://flyhorizons.test/users/email/confirm", "https://flyhorizons.test/users/email/revert")
+	return mockRepo, mockChangeRepo, mockRevocationService, mockMailer, mockEventPublisher, emailChangeService
+}
+
+func getEmailChangeRequestEntity(confirmToken string, revertToken string) entities.EmailChangeRequestEntity {
+	return entities.EmailChangeRequestEntity{
+		ID:               1,
+		UserID:           1,
+		OldEmail:         "john@doe.it",
+		NewEmail:         "john@doe.com",
+		ConfirmTokenHash: authentication.HashOpaqueToken(confirmToken),
+		RevertTokenHash:  authentication.HashOpaqueToken(revertToken),
+		ExpiresAt:        time.Now().Add(time.Hour),
+		RevertExpiresAt:  time.Now().Add(24 * time.Hour),
+		CreatedAt:        time.Now(),
+	}
+}
+
+// Matches the JSON of a published user_email_changed event
+func emailChangedEvent(userID int, oldEmail string, newEmail string, reverted bool) interface{} {
+	return mock.MatchedBy(func(event interface{}) bool {
+		body, _ := json.Marshal(event)
+		var published struct {
+			UserID   int    `json:"userId"`
+			OldEmail string `json:"oldEmail"`
+			NewEmail string `json:"newEmail"`
+			Reverted bool   `json:"reverted"`
+		}
+		return json.Unmarshal(body, &published) == nil &&
+			published.UserID == userID && published.OldEmail == oldEmail && published.NewEmail == newEmail && published.Reverted == reverted
+	})
+}
+
+func tokenFromEmail(body string, link string) string {
+	linkURL, _ := url.Parse(strings.Fields(body[strings.Index(body, link):])[0])
+	return linkURL.Query().Get("token")
+}
+
+// Service Unit Tests
+func TestRequestEmailChangeEmailsBothAddressesWithoutUpdatingAccount(t *testing.T) {
+	// Arrange
+	mockRepo, mockChangeRepo, _, mockMailer, mockEventPublisher, emailChangeService := setupEmailChangeService()
+	var storedChange entities.EmailChangeRequestEntity
+	var confirmBody, noticeBody string
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockRepo.On("GetByEmail", "john@doe.com").Return(entities.UserEntity{})
+	mockChangeRepo.On("CancelPendingForUser", 1).Return()
+	mockChangeRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		storedChange = args.Get(0).(entities.EmailChangeRequestEntity)
+	}).Return(entities.EmailChangeRequestEntity{})
+	mockMailer.On("Send", "john@doe.com", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		confirmBody = args.String(2)
+	}).Return(nil)
+	mockMailer.On("Send", "john@doe.it", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		noticeBody = args.String(2)
+	}).Return(nil)
+
+	// Act
+	err := emailChangeService.RequestChange(1, request.ChangeEmailRequest{NewEmail: "john@doe.com"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "john@doe.it", storedChange.OldEmail)
+	assert.Equal(t, "john@doe.com", storedChange.NewEmail)
+	// Only the hashes of the emailed tokens are stored
+	assert.Equal(t, authentication.HashOpaqueToken(tokenFromEmail(confirmBody, "https://flyhorizons.test/users/email/confirm")), storedChange.ConfirmTokenHash)
+	assert.Equal(t, authentication.HashOpaqueToken(tokenFromEmail(noticeBody, "https://flyhorizons.test/users/email/revert")), storedChange.RevertTokenHash)
+	assert.True(t, storedChange.RevertExpiresAt.After(storedChange.ExpiresAt))
+	mockRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything, mock.Anything)
+	mockEventPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestRequestEmailChangeToAddressInUseThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockChangeRepo, _, mockMailer, _, emailChangeService := setupEmailChangeService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockRepo.On("GetByEmail", "jane@doe.nl").Return(getUserEntities()[1])
+
+	// Act
+	err := emailChangeService.RequestChange(1, request.ChangeEmailRequest{NewEmail: "jane@doe.nl"})
+
+	// Assert
+	assert.Equal(t, errors.NewEmailInUseError(409), err)
+	mockChangeRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmEmailChangeUpdatesAccountAndPublishesEvent(t *testing.T) {
+	// Arrange
+	mockRepo, mockChangeRepo, _, _, mockEventPublisher, emailChangeService := setupEmailChangeService()
+	mockChangeRepo.On("GetByConfirmTokenHash", authentication.HashOpaqueToken("confirm-token")).Return(getEmailChangeRequestEntity("confirm-token", "revert-token"))
+	mockRepo.On("GetByEmail", "john@doe.com").Return(entities.UserEntity{})
+	mockChangeRepo.On("MarkAsConfirmed", 1).Return(true)
+	mockRepo.On("UpdateEmail", 1, "john@doe.com").Return(true)
+	mockEventPublisher.On("Publish", "user_email_changed", emailChangedEvent(1, "john@doe.it", "john@doe.com", false)).Return(nil)
+
+	// Act
+	err := emailChangeService.Confirm("confirm-token")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockEventPublisher.AssertExpectations(t)
+}
+
+func TestConfirmExpiredEmailChangeThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockChangeRepo, _, _, mockEventPublisher, emailChangeService := setupEmailChangeService()
+	change := getEmailChangeRequestEntity("confirm-token", "revert-token")
+	change.ExpiresAt = time.Now().Add(-time.Minute)
+	mockChangeRepo.On("GetByConfirmTokenHash", authentication.HashOpaqueToken("confirm-token")).Return(change)
+
+	// Act
+	err := emailChangeService.Confirm("confirm-token")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidEmailChangeTokenError(400), err)
+	mockRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+	mockEventPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestRevertPendingEmailChangeOnlyCancelsIt(t *testing.T) {
+	// Arrange
+	mockRepo, mockChangeRepo, mockRevocationService, _, mockEventPublisher, emailChangeService := setupEmailChangeService()
+	mockChangeRepo.On("GetByRevertTokenHash", authentication.HashOpaqueToken("revert-token")).Return(getEmailChangeRequestEntity("confirm-token", "revert-token"))
+	mockChangeRepo.On("MarkAsReverted", 1).Return(true)
+
+	// Act
+	err := emailChangeService.Revert("revert-token")
+
+	// Assert
+	assert.NoError(t, err)
+	mockChangeRepo.AssertCalled(t, "MarkAsReverted", 1)
+	mockRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+	mockRevocationService.AssertNotCalled(t, "RevokeAllSessions", mock.Anything)
+	mockEventPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestRevertConfirmedEmailChangeRestoresAddressAndRevokesSessions(t *testing.T) {
+	// Arrange
+	mockRepo, mockChangeRepo, mockRevocationService, _, mockEventPublisher, emailChangeService := setupEmailChangeService()
+	change := getEmailChangeRequestEntity("confirm-token", "revert-token")
+	confirmedAt := time.Now()
+	change.ConfirmedAt = &confirmedAt
+	mockChangeRepo.On("GetByRevertTokenHash", authentication.HashOpaqueToken("revert-token")).Return(change)
+	mockRepo.On("GetByEmail", "john@doe.it").Return(entities.UserEntity{})
+	mockChangeRepo.On("MarkAsReverted", 1).Return(true)
+	mockRepo.On("UpdateEmail", 1, "john@doe.it").Return(true)
+	mockRevocationService.On("RevokeAllSessions", 1).Return()
+	mockEventPublisher.On("Publish", "user_email_changed", emailChangedEvent(1, "john@doe.com", "john@doe.it", true)).Return(nil)
+
+	// Act
+	err := emailChangeService.Revert("revert-token")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRevocationService.AssertCalled(t, "RevokeAllSessions", 1)
+	mockEventPublisher.AssertExpectations(t)
+}
+
+func TestRevertEmailChangeAfterRevertPeriodThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockChangeRepo, _, _, _, emailChangeService := setupEmailChangeService()
+	change := getEmailChangeRequestEntity("confirm-token", "revert-token")
+	change.RevertExpiresAt = time.Now().Add(-time.Minute)
+	mockChangeRepo.On("GetByRevertTokenHash", authentication.HashOpaqueToken("revert-token")).Return(change)
+
+	// Act
+	err := emailChangeService.Revert("revert-token")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidEmailChangeTokenError(400), err)
+	mockChangeRepo.AssertNotCalled(t, "MarkAsReverted", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,179 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestEmailVerificationService struct {
+}
+
+// Setup
+func setupEmailVerificationService() (*mock_repositories.MockUserRepository, *mock_repositories.MockEmailVerificationRepository, *mock_repositories.MockMailer, *services.EmailVerificationService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockVerificationRepo := new(mock_repositories.MockEmailVerificationRepository)
+	mockMailer := new(mock_repositories.MockMailer)
+	emailVerificationService := services.NewEmailVerificationService(mockRepo, mockVerificationRepo, mockMailer, "https://flyhorizons.test/users/verify")
+	return mockRepo, mockVerificationRepo, mockMailer, emailVerificationService
+}
+
+func getEmailVerificationTokenEntity(verificationToken string) entities.EmailVerificationTokenEntity {
+	return entities.EmailVerificationTokenEntity{
+		ID:        1,
+		TokenHash: authentication.HashOpaqueToken(verificationToken),
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Service Unit Tests
+func TestSendVerificationStoresHashAndEmailsLink(t *testing.T) {
+	// Arrange
+	_, mockVerificationRepo, mockMailer, emailVerificationService := setupEmailVerificationService()
+	var storedToken entities.EmailVerificationTokenEntity
+	var emailBody string
+	mockVerificationRepo.On("InvalidateAllForUser", 1).Return()
+	mockVerificationRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		storedToken = args.Get(0).(entities.EmailVerificationTokenEntity)
+	}).Return(entities.EmailVerificationTokenEntity{})
+	mockMailer.On("Send", "john@doe.it", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		emailBody = args.String(2)
+	}).Return(nil)
+
+	// Act
+	err := emailVerificationService.SendVerification(models.User{ID: 1, FullName: "John Doe", Email: "john@doe.it"})
+
+	// Assert
+	assert.NoError(t, err)
+	link := emailBody[strings.Index(emailBody, "https://flyhorizons.test/users/verify"):]
+	verifyURL, err := url.Parse(strings.Fields(link)[0])
+	assert.NoError(t, err)
+	// Only the hash of the emailed token is stored
+	assert.Equal(t, authentication.HashOpaqueToken(verifyURL.Query().Get("token")), storedToken.TokenHash)
+	assert.Equal(t, 1, storedToken.UserID)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), storedToken.ExpiresAt, time.Minute)
+	mockVerificationRepo.AssertCalled(t, "InvalidateAllForUser", 1)
+}
+
+func TestVerifyUsingValidTokenMarksEmailVerified(t *testing.T) {
+	// Arrange
+	mockRepo, mockVerificationRepo, _, emailVerificationService := setupEmailVerificationService()
+	mockVerificationRepo.On("GetByTokenHash", authentication.HashOpaqueToken("verification-token")).Return(getEmailVerificationTokenEntity("verification-token"))
+	mockVerificationRepo.On("MarkAsUsed", 1).Return(true)
+	mockRepo.On("MarkEmailVerified", 1).Return(true)
+
+	// Act
+	err := emailVerificationService.Verify("verification-token")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifyUsingExpiredTokenThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockVerificationRepo, _, emailVerificationService := setupEmailVerificationService()
+	verificationToken := getEmailVerificationTokenEntity("verification-token")
+	verificationToken.ExpiresAt = time.Now().Add(-time.Minute)
+	mockVerificationRepo.On("GetByTokenHash", authentication.HashOpaqueToken("verification-token")).Return(verificationToken)
+
+	// Act
+	err := emailVerificationService.Verify("verification-token")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidEmailVerificationTokenError(400), err)
+	mockVerificationRepo.AssertNotCalled(t, "MarkAsUsed", mock.Anything)
+	mockRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything)
+}
+
+func TestVerifyUsingUsedTokenThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockVerificationRepo, _, emailVerificationService := setupEmailVerificationService()
+	mockVerificationRepo.On("GetByTokenHash", authentication.HashOpaqueToken("verification-token")).Return(getEmailVerificationTokenEntity("verification-token"))
+	mockVerificationRepo.On("MarkAsUsed", 1).Return(false) // Used by a concurrent request
+
+	// Act
+	err := emailVerificationService.Verify("verification-token")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidEmailVerificationTokenError(400), err)
+	mockRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything)
+}
+
+func TestResendVerificationForUnverifiedAccountSendsLink(t *testing.T) {
+	// Arrange
+	mockRepo, mockVerificationRepo, mockMailer, emailVerificationService := setupEmailVerificationService()
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockVerificationRepo.On("CountCreatedSince", 1, mock.Anything).Return(0)
+	mockVerificationRepo.On("InvalidateAllForUser", 1).Return()
+	mockVerificationRepo.On("Create", mock.Anything).Return(entities.EmailVerificationTokenEntity{})
+	mockMailer.On("Send", "john@doe.it", mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	emailVerificationService.Resend(request.ResendVerificationRequest{Email: "john@doe.it"})
+
+	// Assert
+	mockMailer.AssertExpectations(t)
+}
+
+func TestResendVerificationWithinAMinuteIsThrottled(t *testing.T) {
+	// Arrange
+	mockRepo, mockVerificationRepo, mockMailer, emailVerificationService := setupEmailVerificationService()
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockVerificationRepo.On("CountCreatedSince", 1, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) < 2*time.Minute
+	})).Return(1)
+
+	// Act
+	emailVerificationService.Resend(request.ResendVerificationRequest{Email: "john@doe.it"})
+
+	// Assert
+	mockVerificationRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResendVerificationAfterHourlyLimitIsThrottled(t *testing.T) {
+	// Arrange
+	mockRepo, mockVerificationRepo, mockMailer, emailVerificationService := setupEmailVerificationService()
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockVerificationRepo.On("CountCreatedSince", 1, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) < 2*time.Minute
+	})).Return(0)
+	mockVerificationRepo.On("CountCreatedSince", 1, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > 2*time.Minute
+	})).Return(5)
+
+	// Act
+	emailVerificationService.Resend(request.ResendVerificationRequest{Email: "john@doe.it"})
+
+	// Assert
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResendVerificationForVerifiedAccountSendsNothing(t *testing.T) {
+	// Arrange
+	mockRepo, mockVerificationRepo, mockMailer, emailVerificationService := setupEmailVerificationService()
+	accountEntity := getUserEntities()[0]
+	accountEntity.EmailVerified = true
+	mockRepo.On("GetByEmail", "john@doe.it").Return(accountEntity)
+
+	// Act
+	emailVerificationService.Resend(request.ResendVerificationRequest{Email: "john@doe.it"})
+
+	// Assert
+	mockVerificationRepo.AssertNotCalled(t, "CountCreatedSince", mock.Anything, mock.Anything)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models/response"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestIntrospectionService struct {
+}
+
+// Setup
+func setupIntrospectionService(revoked bool) (*authentication.JwtTokenSigner, *services.IntrospectionService) {
+	signingKey, _ := authentication.NewHMACSigningKey("key-1", []byte("secret"))
+	signer := authentication.NewJwtTokenSigner(authentication.NewKeyRing(signingKey, time.Hour))
+	mockRevocationService := new(mock_repositories.MockTokenRevocationService)
+	mockRevocationService.On("IsRevoked", mock.Anything, mock.Anything).Return(revoked)
+	introspectionService := services.NewIntrospectionService(authentication.NewJwtTokenValidator(signer), mockRevocationService)
+	return signer, introspectionService
+}
+
+func getAccessTokenClaims(expiresAt time.Time) jwt.MapClaims {
+	return jwt.MapClaims{
+		"jti":  "jti-1",
+		"sub":  1,
+		"role": "user",
+		"iss":  "flyhorizons-user-service",
+		"aud":  "flyhorizons-api",
+		"iat":  time.Now().Unix(),
+		"exp":  expiresAt.Unix(),
+	}
+}
+
+// Service Unit Tests
+func TestIntrospectValidTokenReturnsActive(t *testing.T) {
+	// Arrange
+	signer, introspectionService := setupIntrospectionService(false)
+	expiresAt := time.Now().Add(time.Minute)
+	token, _ := signer.SignToken(getAccessTokenClaims(expiresAt))
+
+	// Act
+	introspection := introspectionService.Introspect(token)
+
+	// Assert
+	assert.True(t, introspection.Active)
+	assert.False(t, introspection.Revoked)
+	assert.Equal(t, "1", introspection.Subject)
+	assert.Equal(t, "user", introspection.Role)
+	assert.Equal(t, "jti-1", introspection.JTI)
+	assert.Equal(t, expiresAt.Unix(), introspection.Expiry)
+}
+
+func TestIntrospectTokenOfLargeUserIDReturnsDecimalSubject(t *testing.T) {
+	// Arrange
+	signer, introspectionService := setupIntrospectionService(false)
+	claims := getAccessTokenClaims(time.Now().Add(time.Minute))
+	claims["sub"] = 1000000
+	token, _ := signer.SignToken(claims)
+
+	// Act
+	introspection := introspectionService.Introspect(token)
+
+	// Assert
+	assert.True(t, introspection.Active)
+	assert.Equal(t, "1000000", introspection.Subject)
+}
+
+func TestIntrospectExpiredTokenReturnsInactive(t *testing.T) {
+	// Arrange
+	signer, introspectionService := setupIntrospectionService(false)
+	token, _ := signer.SignToken(getAccessTokenClaims(time.Now().Add(-time.Minute)))
+
+	// Act
+	introspection := introspectionService.Introspect(token)
+
+	// Assert
+	assert.Equal(t, response.IntrospectionResponse{Active: false}, introspection)
+}
+
+func TestIntrospectRevokedTokenReturnsRevoked(t *testing.T) {
+	// Arrange
+	signer, introspectionService := setupIntrospectionService(true)
+	token, _ := signer.SignToken(getAccessTokenClaims(time.Now().Add(time.Minute)))
+
+	// Act
+	introspection := introspectionService.Introspect(token)
+
+	// Assert
+	assert.Equal(t, response.IntrospectionResponse{Active: false, Revoked: true}, introspection)
+}
+
+func TestIntrospectMalformedTokenReturnsInactive(t *testing.T) {
+	// Arrange
+	_, introspectionService := setupIntrospectionService(false)
+
+	// Act
+	introspection := introspectionService.Introspect("not-a-token")
+
+	// Assert
+	assert.False(t, introspection.Active)
+}
@@ -1,14 +1,21 @@
 package services_test
 
 import (
+	"flyhorizons-userservice/models"
 	"flyhorizons-userservice/models/request"
 	"flyhorizons-userservice/models/response"
+	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
 	"flyhorizons-userservice/services/converter"
 	"flyhorizons-userservice/services/errors"
 	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -17,12 +24,80 @@ type TestLoginService struct {
 }
 
 // Setup
-func setupLoginService() (*mock_repositories.MockUserRepository, *mock_repositories.MockJwtTokenSigner, *services.LoginService) {
+func setupLoginService() (*mock_repositories.MockUserRepository, *mock_repositories.MockRefreshTokenRepository, *mock_repositories.MockMFAService, *mock_repositories.MockJwtTokenSigner, *services.LoginService) {
 	mockRepo := new(mock_repositories.MockUserRepository)
+	mockRefreshTokenRepo := new(mock_repositories.MockRefreshTokenRepository)
+	mockMFAService := new(mock_repositories.MockMFAService)
 	userConverter := new(converter.UserConverter)
 	mockJwtTokenSigner := new(mock_repositories.MockJwtTokenSigner)
-	loginService := services.NewLoginService(mockRepo, *userConverter, mockJwtTokenSigner)
-	return mockRepo, mockJwtTokenSigner, loginService
+	loginService := services.NewLoginService(mockRepo, mockRefreshTokenRepo, newFixtureAccountHashing(), mockMFAService, new(mock_repositories.MockWebAuthnService), newPermissiveLockoutService(), *userConverter, mockJwtTokenSigner, services.EmailVerificationAllow)
+	return mockRepo, mockRefreshTokenRepo, mockMFAService, mockJwtTokenSigner, loginService
+}
+
+func setupPasskeyLoginService() (*mock_repositories.MockUserRepository, *mock_repositories.MockRefreshTokenRepository, *mock_repositories.MockWebAuthnService, *mock_repositories.MockJwtTokenSigner, *services.LoginService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockRefreshTokenRepo := new(mock_repositories.MockRefreshTokenRepository)
+	mockWebAuthnService := new(mock_repositories.MockWebAuthnService)
+	userConverter := new(converter.UserConverter)
+	mockJwtTokenSigner := new(mock_repositories.MockJwtTokenSigner)
+	loginService := services.NewLoginService(mockRepo, mockRefreshTokenRepo, newFixtureAccountHashing(), new(mock_repositories.MockMFAService), mockWebAuthnService, newPermissiveLockoutService(), *userConverter, mockJwtTokenSigner, services.EmailVerificationAllow)
+	return mockRepo, mockRefreshTokenRepo, mockWebAuthnService, mockJwtTokenSigner, loginService
+}
+
+func setupEmailVerificationLoginService(policy services.EmailVerificationPolicy) (*mock_repositories.MockUserRepository, *mock_repositories.MockRefreshTokenRepository, *mock_repositories.MockMFAService, *mock_repositories.MockJwtTokenSigner, *services.LoginService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockRefreshTokenRepo := new(mock_repositories.MockRefreshTokenRepository)
+	mockMFAService := new(mock_repositories.MockMFAService)
+	userConverter := new(converter.UserConverter)
+	mockJwtTokenSigner := new(mock_repositories.MockJwtTokenSigner)
+	loginService := services.NewLoginService(mockRepo, mockRefreshTokenRepo, newFixtureAccountHashing(), mockMFAService, new(mock_repositories.MockWebAuthnService), newPermissiveLockoutService(), *userConverter, mockJwtTokenSigner, policy)
+	return mockRepo, mockRefreshTokenRepo, mockMFAService, mockJwtTokenSigner, loginService
+}
+
+func setupLockoutLoginService() (*mock_repositories.MockUserRepository, *mock_repositories.MockAccountLockoutService, *services.LoginService) {
+	mockRepo, _, _, mockLockoutService, loginService := setupSecondFactorLockoutLoginService()
+	return mockRepo, mockLockoutService, loginService
+}
+
+func setupSecondFactorLockoutLoginService() (*mock_repositories.MockUserRepository, *mock_repositories.MockMFAService, *mock_repositories.MockWebAuthnService, *mock_repositories.MockAccountLockoutService, *services.LoginService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockWebAuthnService := new(mock_repositories.MockWebAuthnService)
+	mockLockoutService := new(mock_repositories.MockAccountLockoutService)
+	loginService := services.NewLoginService(mockRepo, new(mock_repositories.MockRefreshTokenRepository), newFixtureAccountHashing(), mockMFAService, mockWebAuthnService, mockLockoutService, converter.UserConverter{}, new(mock_repositories.MockJwtTokenSigner), services.EmailVerificationAllow)
+	return mockRepo, mockMFAService, mockWebAuthnService, mockLockoutService, loginService
+}
+
+// Prefers argon2id, so the bcrypt fixture passwords are outdated
+func setupRehashLoginService() (*mock_repositories.MockUserRepository, *services.LoginService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockRefreshTokenRepo := new(mock_repositories.MockRefreshTokenRepository)
+	mockMFAService := new(mock_repositories.MockMFAService)
+	mockJwtTokenSigner := new(mock_repositories.MockJwtTokenSigner)
+	mockRepo.On("SaveLastLoginTime", mock.Anything).Return()
+	mockMFAService.On("BeginChallenge", mock.Anything).Return(nil, nil)
+	mockJwtTokenSigner.On("SignToken", mock.Anything).Return("Mock Access Token", nil)
+	mockRefreshTokenRepo.On("Create", mock.Anything).Return(entities.RefreshTokenEntity{})
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
+	loginService := services.NewLoginService(mockRepo, mockRefreshTokenRepo, accountHashing, mockMFAService, new(mock_repositories.MockWebAuthnService), newPermissiveLockoutService(), converter.UserConverter{}, mockJwtTokenSigner, services.EmailVerificationAllow)
+	return mockRepo, loginService
+}
+
+// The fixture passwords are bcrypt hashes with cost 12, so they are not upgraded on login
+func newFixtureAccountHashing() *authentication.AccountHashing {
+	policy := authentication.DefaultHashingPolicy()
+	policy.Algorithm = authentication.HashingAlgorithmBcrypt
+	policy.BcryptCost = 12
+	return authentication.NewAccountHashing(policy)
+}
+
+// Lockout service that never refuses a login, for the tests that are not about lockouts
+func newPermissiveLockoutService() *mock_repositories.MockAccountLockoutService {
+	mockLockoutService := new(mock_repositories.MockAccountLockoutService)
+	mockLockoutService.On("Check", mock.Anything).Return(nil)
+	mockLockoutService.On("RecordFailure", mock.Anything).Return()
+	mockLockoutService.On("RecordSuccess", mock.Anything).Return()
+	return mockLockoutService
 }
 
 func getLoginRequest(email string, password string) request.LoginRequest {
@@ -35,7 +110,7 @@ func getLoginRequest(email string, password string) request.LoginRequest {
 // Service Unit Tests
 func TestLoginUsingCorrectCredentialsReturnsAccessToken(t *testing.T) {
 	// Arrange
-	mockRepo, mockJwtTokenSigner, loginService := setupLoginService()
+	mockRepo, mockRefreshTokenRepo, mockMFAService, mockJwtTokenSigner, loginService := setupLoginService()
 	// User credentials
 	id := 1
 	email := "john@doe.it"
@@ -44,8 +119,13 @@ func TestLoginUsingCorrectCredentialsReturnsAccessToken(t *testing.T) {
 	mockAccessToken := "Mock Access Token"
 	mockRepo.On("GetByEmail", email).Return(getUserEntities()[0])
 	mockRepo.On("SaveLastLoginTime", id).Return()
+	mockMFAService.On("BeginChallenge", mock.Anything).Return(nil, nil)
 	// Mock signing the Jwt auth token
-	mockJwtTokenSigner.On("SignToken", mock.Anything).Return(mockAccessToken, nil)
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		// Every token carries a unique ID for revocation and the authentication methods of the login
+		return claims["jti"] != "" && claims["sub"] == id && slices.Equal(claims["amr"].([]string), []string{"pwd"})
+	})).Return(mockAccessToken, nil)
+	mockRefreshTokenRepo.On("Create", mock.Anything).Return(entities.RefreshTokenEntity{})
 	loginRequest := getLoginRequest(email, password)
 
 	// Act
@@ -53,14 +133,14 @@ func TestLoginUsingCorrectCredentialsReturnsAccessToken(t *testing.T) {
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, accessToken, &response.LoginResponse{
-		AccessToken: mockAccessToken,
-	})
+	assert.Equal(t, mockAccessToken, accessToken.AccessToken)
+	assert.Equal(t, "Bearer", accessToken.TokenType)
+	assert.NotEmpty(t, accessToken.RefreshToken)
 }
 
 func TestLoginUsingIncorrectCredentialsThrowsException(t *testing.T) {
 	// Arrange
-	mockRepo, mockJwtTokenSigner, loginService := setupLoginService()
+	mockRepo, _, _, mockJwtTokenSigner, loginService := setupLoginService()
 	// Incorrect user credentials
 	email := "john@doe.it"
 	password := "4321!"
@@ -77,3 +157,412 @@ func TestLoginUsingIncorrectCredentialsThrowsException(t *testing.T) {
 	assert.Equal(t, errors.NewInvalidCredentialsError(400), err)
 	assert.Nil(t, accessToken)
 }
+
+func getRefreshTokenEntity(refreshToken string) entities.RefreshTokenEntity {
+	return entities.RefreshTokenEntity{
+		ID:        1,
+		UserID:    1,
+		FamilyID:  "family-1",
+		TokenHash: authentication.HashOpaqueToken(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestRefreshUsingValidRefreshTokenRotatesToken(t *testing.T) {
+	// Arrange
+	mockRepo, mockRefreshTokenRepo, _, mockJwtTokenSigner, loginService := setupLoginService()
+	refreshToken := "Mock Refresh Token"
+	ip := "1234.123.12"
+	mockAccessToken := "Mock Access Token"
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(getRefreshTokenEntity(refreshToken))
+	mockRefreshTokenRepo.On("MarkAsUsed", 1).Return(true)
+	mockRefreshTokenRepo.On("Create", mock.MatchedBy(func(r entities.RefreshTokenEntity) bool {
+		return r.FamilyID == "family-1" && r.UserID == 1 // Rotated token stays in the same family
+	})).Return(entities.RefreshTokenEntity{})
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockJwtTokenSigner.On("SignToken", mock.Anything).Return(mockAccessToken, nil)
+
+	// Act
+	refreshResponse, err := loginService.Refresh(request.RefreshTokenRequest{RefreshToken: refreshToken}, ip)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, mockAccessToken, refreshResponse.AccessToken)
+	assert.NotEmpty(t, refreshResponse.RefreshToken)
+	assert.NotEqual(t, refreshToken, refreshResponse.RefreshToken)
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+func TestRefreshUsingReusedRefreshTokenRevokesFamily(t *testing.T) {
+	// Arrange
+	_, mockRefreshTokenRepo, _, _, loginService := setupLoginService()
+	refreshToken := "Mock Refresh Token"
+	ip := "1234.123.12"
+	usedAt := time.Now().Add(-time.Minute)
+	refreshTokenEntity := getRefreshTokenEntity(refreshToken)
+	refreshTokenEntity.UsedAt = &usedAt
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(refreshTokenEntity)
+	mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return()
+
+	// Act
+	refreshResponse, err := loginService.Refresh(request.RefreshTokenRequest{RefreshToken: refreshToken}, ip)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, errors.NewRefreshTokenReusedError(401), err)
+	assert.Nil(t, refreshResponse)
+	mockRefreshTokenRepo.AssertCalled(t, "RevokeFamily", "family-1")
+}
+
+func TestRefreshUsingExpiredRefreshTokenThrowsException(t *testing.T) {
+	// Arrange
+	_, mockRefreshTokenRepo, _, _, loginService := setupLoginService()
+	refreshToken := "Mock Refresh Token"
+	ip := "1234.123.12"
+	refreshTokenEntity := getRefreshTokenEntity(refreshToken)
+	refreshTokenEntity.ExpiresAt = time.Now().Add(-time.Minute)
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(refreshTokenEntity)
+
+	// Act
+	refreshResponse, err := loginService.Refresh(request.RefreshTokenRequest{RefreshToken: refreshToken}, ip)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, errors.NewInvalidRefreshTokenError(401), err)
+	assert.Nil(t, refreshResponse)
+	mockRefreshTokenRepo.AssertNotCalled(t, "MarkAsUsed", mock.Anything)
+}
+
+func TestLoginUsingAccountWithMFAReturnsChallenge(t *testing.T) {
+	// Arrange
+	mockRepo, _, mockMFAService, mockJwtTokenSigner, loginService := setupLoginService()
+	mockChallenge := &response.LoginResponse{MFARequired: true, MFAToken: "Mock MFA Token"}
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockMFAService.On("BeginChallenge", mock.MatchedBy(func(account models.User) bool { return account.ID == 1 })).Return(mockChallenge, nil)
+
+	// Act
+	loginResponse, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, mockChallenge, loginResponse)
+	mockJwtTokenSigner.AssertNotCalled(t, "SignToken", mock.Anything)
+	mockRepo.AssertNotCalled(t, "SaveLastLoginTime", mock.Anything)
+}
+
+func TestVerifyMFAUsingValidCodeReturnsTokensWithAuthenticationMethods(t *testing.T) {
+	// Arrange
+	mockRepo, mockRefreshTokenRepo, mockMFAService, mockJwtTokenSigner, loginService := setupLoginService()
+	mockMFAService.On("VerifyChallenge", "Mock MFA Token", "123456").Return(1, nil)
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockRepo.On("SaveLastLoginTime", 1).Return()
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		return slices.Equal(claims["amr"].([]string), []string{"pwd", "otp"})
+	})).Return("Mock Access Token", nil)
+	// Refreshed tokens keep the authentication methods
+	mockRefreshTokenRepo.On("Create", mock.MatchedBy(func(refreshToken entities.RefreshTokenEntity) bool {
+		return refreshToken.AuthMethods == "pwd otp"
+	})).Return(entities.RefreshTokenEntity{})
+
+	// Act
+	loginResponse, err := loginService.VerifyMFA(request.MFALoginRequest{MFAToken: "Mock MFA Token", Code: "123456"}, "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Mock Access Token", loginResponse.AccessToken)
+	assert.NotEmpty(t, loginResponse.RefreshToken)
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+func TestVerifyMFAUsingInvalidCodeThrowsException(t *testing.T) {
+	// Arrange
+	_, _, mockMFAService, mockJwtTokenSigner, loginService := setupLoginService()
+	mockMFAService.On("VerifyChallenge", "Mock MFA Token", "000000").Return(0, errors.NewInvalidMFACodeError(401))
+
+	// Act
+	loginResponse, err := loginService.VerifyMFA(request.MFALoginRequest{MFAToken: "Mock MFA Token", Code: "000000"}, "1234.123.12")
+
+	// Assert
+	assert.Nil(t, loginResponse)
+	assert.Equal(t, errors.NewInvalidMFACodeError(401), err)
+	mockJwtTokenSigner.AssertNotCalled(t, "SignToken", mock.Anything)
+}
+
+func TestLoginWithPasskeyReturnsTokensWithSameClaimsAsPasswordLogin(t *testing.T) {
+	// Arrange
+	mockRepo, mockRefreshTokenRepo, mockWebAuthnService, mockJwtTokenSigner, loginService := setupPasskeyLoginService()
+	passkeyRequest := request.PasskeyLoginRequest{ID: "credential-id", Type: "public-key"}
+	mockWebAuthnService.On("FinishLogin", passkeyRequest).Return(1, nil)
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockRepo.On("SaveLastLoginTime", 1).Return()
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		return claims["jti"] != "" && claims["sub"] == 1 && claims["email"] == "john@doe.it" && claims["role"] == "user" &&
+			claims["aud"] == "flyhorizons-api" && slices.Equal(claims["amr"].([]string), []string{"hwk", "user"})
+	})).Return("Mock Access Token", nil)
+	mockRefreshTokenRepo.On("Create", mock.MatchedBy(func(refreshToken entities.RefreshTokenEntity) bool {
+		return refreshToken.UserID == 1 && refreshToken.AuthMethods == "hwk user"
+	})).Return(entities.RefreshTokenEntity{})
+
+	// Act
+	loginResponse, err := loginService.LoginWithPasskey(passkeyRequest, "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Mock Access Token", loginResponse.AccessToken)
+	assert.NotEmpty(t, loginResponse.RefreshToken)
+	mockRepo.AssertCalled(t, "SaveLastLoginTime", 1)
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+func TestLoginWithInvalidPasskeyThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, _, mockWebAuthnService, mockJwtTokenSigner, loginService := setupPasskeyLoginService()
+	passkeyRequest := request.PasskeyLoginRequest{ID: "credential-id", Type: "public-key"}
+	mockWebAuthnService.On("FinishLogin", passkeyRequest).Return(0, errors.NewInvalidPasskeyError(401))
+
+	// Act
+	loginResponse, err := loginService.LoginWithPasskey(passkeyRequest, "1234.123.12")
+
+	// Assert
+	assert.Nil(t, loginResponse)
+	assert.Equal(t, errors.NewInvalidPasskeyError(401), err)
+	mockRepo.AssertNotCalled(t, "SaveLastLoginTime", mock.Anything)
+	mockJwtTokenSigner.AssertNotCalled(t, "SignToken", mock.Anything)
+}
+
+func TestLoginUsingUnverifiedEmailWithBlockPolicyThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockRefreshTokenRepo, mockMFAService, mockJwtTokenSigner, loginService := setupEmailVerificationLoginService(services.EmailVerificationBlock)
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockMFAService.On("BeginChallenge", mock.Anything).Return(nil, nil)
+
+	// Act
+	loginResponse, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.Equal(t, errors.NewEmailNotVerifiedError(403), err)
+	assert.Nil(t, loginResponse)
+	mockJwtTokenSigner.AssertNotCalled(t, "SignToken", mock.Anything)
+	mockRefreshTokenRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "SaveLastLoginTime", mock.Anything)
+}
+
+func TestLoginUsingUnverifiedEmailWithLimitPolicyReturnsOnlyAccessToken(t *testing.T) {
+	// Arrange
+	mockRepo, mockRefreshTokenRepo, mockMFAService, mockJwtTokenSigner, loginService := setupEmailVerificationLoginService(services.EmailVerificationLimit)
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockRepo.On("SaveLastLoginTime", 1).Return()
+	mockMFAService.On("BeginChallenge", mock.Anything).Return(nil, nil)
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		return claims["email_verified"] == false
+	})).Return("Mock Access Token", nil)
+
+	// Act
+	loginResponse, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Mock Access Token", loginResponse.AccessToken)
+	assert.Empty(t, loginResponse.RefreshToken)
+	assert.True(t, loginResponse.EmailVerificationRequired)
+	mockRefreshTokenRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLoginUsingVerifiedEmailWithBlockPolicyReturnsTokens(t *testing.T) {
+	// Arrange
+	mockRepo, mockRefreshTokenRepo, mockMFAService, mockJwtTokenSigner, loginService := setupEmailVerificationLoginService(services.EmailVerificationBlock)
+	accountEntity := getUserEntities()[0]
+	accountEntity.EmailVerified = true
+	mockRepo.On("GetByEmail", "john@doe.it").Return(accountEntity)
+	mockRepo.On("SaveLastLoginTime", 1).Return()
+	mockMFAService.On("BeginChallenge", mock.Anything).Return(nil, nil)
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		return claims["email_verified"] == true
+	})).Return("Mock Access Token", nil)
+	mockRefreshTokenRepo.On("Create", mock.Anything).Return(entities.RefreshTokenEntity{})
+
+	// Act
+	loginResponse, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loginResponse.RefreshToken)
+	assert.False(t, loginResponse.EmailVerificationRequired)
+}
+
+func TestRefreshUsingUnverifiedEmailWithLimitPolicyRevokesFamily(t *testing.T) {
+	// Arrange
+	mockRepo, mockRefreshTokenRepo, _, mockJwtTokenSigner, loginService := setupEmailVerificationLoginService(services.EmailVerificationLimit)
+	refreshToken := "Mock Refresh Token"
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(getRefreshTokenEntity(refreshToken))
+	mockRefreshTokenRepo.On("MarkAsUsed", 1).Return(true)
+	mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+
+	// Act
+	refreshResponse, err := loginService.Refresh(request.RefreshTokenRequest{RefreshToken: refreshToken}, "1234.123.12")
+
+	// Assert
+	assert.Equal(t, errors.NewEmailNotVerifiedError(403), err)
+	assert.Nil(t, refreshResponse)
+	mockRefreshTokenRepo.AssertCalled(t, "RevokeFamily", "family-1")
+	mockJwtTokenSigner.AssertNotCalled(t, "SignToken", mock.Anything)
+}
+
+func TestLoginUsingLockedAccountThrowsExceptionWithoutCheckingPassword(t *testing.T) {
+	// Arrange
+	mockRepo, mockLockoutService, loginService := setupLockoutLoginService()
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockLockoutService.On("Check", 1).Return(errors.NewAccountLockedError(time.Minute, 423))
+
+	// Act
+	loginResponse, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.Equal(t, errors.NewAccountLockedError(time.Minute, 423), err)
+	assert.Nil(t, loginResponse)
+	mockLockoutService.AssertNotCalled(t, "RecordFailure", mock.Anything)
+	mockLockoutService.AssertNotCalled(t, "RecordSuccess", mock.Anything)
+}
+
+func TestLoginUsingIncorrectPasswordRecordsFailedAttempt(t *testing.T) {
+	// Arrange
+	mockRepo, mockLockoutService, loginService := setupLockoutLoginService()
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockLockoutService.On("Check", 1).Return(nil)
+	mockLockoutService.On("RecordFailure", 1).Return()
+
+	// Act
+	_, err := loginService.Login(getLoginRequest("john@doe.it", "4321!"), "1234.123.12")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidCredentialsError(400), err)
+	mockLockoutService.AssertCalled(t, "RecordFailure", 1)
+	mockLockoutService.AssertNotCalled(t, "RecordSuccess", mock.Anything)
+}
+
+func TestLoginWithMFAChallengeDoesNotResetFailedAttempts(t *testing.T) {
+	// Arrange
+	mockRepo, mockMFAService, _, mockLockoutService, loginService := setupSecondFactorLockoutLoginService()
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockLockoutService.On("Check", 1).Return(nil)
+	mockMFAService.On("BeginChallenge", mock.Anything).Return(&response.LoginResponse{MFARequired: true, MFAToken: "Mock MFA Token"}, nil)
+
+	// Act
+	loginResponse, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, loginResponse.MFARequired)
+	mockLockoutService.AssertNotCalled(t, "RecordSuccess", mock.Anything)
+}
+
+func TestVerifyMFAUsingInvalidCodeRecordsFailedAttempt(t *testing.T) {
+	// Arrange
+	_, mockMFAService, _, mockLockoutService, loginService := setupSecondFactorLockoutLoginService()
+	mockMFAService.On("VerifyChallenge", "Mock MFA Token", "ABCDEFGHIJKLMNOP").Return(1, errors.NewInvalidMFACodeError(401))
+	mockLockoutService.On("RecordFailure", 1).Return()
+
+	// Act
+	_, err := loginService.VerifyMFA(request.MFALoginRequest{MFAToken: "Mock MFA Token", Code: "ABCDEFGHIJKLMNOP"}, "1234.123.12")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidMFACodeError(401), err)
+	mockLockoutService.AssertCalled(t, "RecordFailure", 1)
+}
+
+func TestVerifyMFAUsingLockedAccountThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockMFAService, _, mockLockoutService, loginService := setupSecondFactorLockoutLoginService()
+	mockMFAService.On("VerifyChallenge", "Mock MFA Token", "123456").Return(1, nil)
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockLockoutService.On("Check", 1).Return(errors.NewAccountLockedError(time.Minute, 423))
+
+	// Act
+	loginResponse, err := loginService.VerifyMFA(request.MFALoginRequest{MFAToken: "Mock MFA Token", Code: "123456"}, "1234.123.12")
+
+	// Assert
+	assert.Nil(t, loginResponse)
+	assert.Equal(t, errors.NewAccountLockedError(time.Minute, 423), err)
+	mockLockoutService.AssertNotCalled(t, "RecordSuccess", mock.Anything)
+}
+
+func TestLoginWithPasskeyUsingLockedAccountThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, _, mockWebAuthnService, mockLockoutService, loginService := setupSecondFactorLockoutLoginService()
+	passkeyRequest := request.PasskeyLoginRequest{ID: "credential-id", Type: "public-key"}
+	mockWebAuthnService.On("FinishLogin", passkeyRequest).Return(1, nil)
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockLockoutService.On("Check", 1).Return(errors.NewAccountLockedError(time.Minute, 423))
+
+	// Act
+	loginResponse, err := loginService.LoginWithPasskey(passkeyRequest, "1234.123.12")
+
+	// Assert
+	assert.Nil(t, loginResponse)
+	assert.Equal(t, errors.NewAccountLockedError(time.Minute, 423), err)
+	mockLockoutService.AssertNotCalled(t, "RecordSuccess", mock.Anything)
+}
+
+func TestLoginUsingUnknownEmailDoesNotRecordFailedAttempt(t *testing.T) {
+	// Arrange
+	mockRepo, mockLockoutService, loginService := setupLockoutLoginService()
+	mockRepo.On("GetByEmail", "unknown@doe.it").Return(entities.UserEntity{})
+
+	// Act
+	_, err := loginService.Login(getLoginRequest("unknown@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidCredentialsError(400), err)
+	mockLockoutService.AssertNotCalled(t, "Check", mock.Anything)
+	mockLockoutService.AssertNotCalled(t, "RecordFailure", mock.Anything)
+}
+
+func TestLoginUsingOutdatedHashUpgradesPasswordHash(t *testing.T) {
+	// Arrange
+	mockRepo, loginService := setupRehashLoginService()
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
+	var upgradedHash string
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockRepo.On("UpdatePassword", 1, mock.Anything).Run(func(args mock.Arguments) {
+		upgradedHash = args.String(1)
+	}).Return(true)
+
+	// Act
+	_, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(upgradedHash, "$argon2id$"))
+	assert.True(t, accountHashing.ComparePassword(upgradedHash, "1234!"))
+}
+
+func TestLoginUsingCurrentHashKeepsPasswordHash(t *testing.T) {
+	// Arrange
+	mockRepo, loginService := setupRehashLoginService()
+	passwordHash, _ := authentication.NewAccountHashing(authentication.DefaultHashingPolicy()).HashPassword("1234!")
+	user := getUserEntities()[0]
+	user.Password = passwordHash
+	mockRepo.On("GetByEmail", "john@doe.it").Return(user)
+
+	// Act
+	_, err := loginService.Login(getLoginRequest("john@doe.it", "1234!"), "1234.123.12")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestLoginUsingIncorrectPasswordKeepsOutdatedHash(t *testing.T) {
+	// Arrange
+	mockRepo, loginService := setupRehashLoginService()
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+
+	// Act
+	_, err := loginService.Login(getLoginRequest("john@doe.it", "4321!"), "1234.123.12")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidCredentialsError(400), err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
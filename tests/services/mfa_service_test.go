@@ -0,0 +1,333 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/enums"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestMFAService struct {
+}
+
+// Setup
+func setupMFAService(policy services.MFAPolicy) (*mock_repositories.MockMFARepository, *mock_repositories.MockUserRepository, *services.MFAService) {
+	mockMFARepo := new(mock_repositories.MockMFARepository)
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mfaService := services.NewMFAService(mockMFARepo, mockRepo, policy)
+	return mockMFARepo, mockRepo, mfaService
+}
+
+func getMFAFactorEntity(enabled bool) entities.MFAFactorEntity {
+	secret, _ := authentication.GenerateTOTPSecret()
+	return entities.MFAFactorEntity{
+		UserID:    1,
+		Secret:    secret,
+		Enabled:   enabled,
+		CreatedAt: time.Now(),
+	}
+}
+
+func getMFAChallengeEntity(mfaToken string) entities.MFAChallengeEntity {
+	return entities.MFAChallengeEntity{
+		ID:        1,
+		TokenHash: authentication.HashOpaqueToken(mfaToken),
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Minute),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Service Unit Tests
+func TestEnrollMFAReturnsSecretAndOTPAuthURI(t *testing.T) {
+	// Arrange
+	mockMFARepo, mockRepo, mfaService := setupMFAService(services.MFAPolicy{})
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockMFARepo.On("GetFactor", 1).Return(entities.MFAFactorEntity{})
+	mockMFARepo.On("SaveFactor", mock.MatchedBy(func(factor entities.MFAFactorEntity) bool {
+		return factor.UserID == 1 && !factor.Enabled
+	})).Return()
+	var storedRecoveryCodes []entities.MFARecoveryCodeEntity
+	mockMFARepo.On("ReplaceRecoveryCodes", 1, mock.Anything).Run(func(args mock.Arguments) {
+		storedRecoveryCodes = args.Get(1).([]entities.MFARecoveryCodeEntity)
+	}).Return()
+
+	// Act
+	enrollment, err := mfaService.Enroll(1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.Contains(t, enrollment.OTPAuthURI, "otpauth://totp/FlyHorizons:john@doe.it")
+	// Only hashes of the recovery codes are stored
+	assert.Len(t, enrollment.RecoveryCodes, 10)
+	assert.Len(t, storedRecoveryCodes, 10)
+	assert.Equal(t, authentication.HashRecoveryCode(enrollment.RecoveryCodes[0]), storedRecoveryCodes[0].CodeHash)
+	assert.NotEqual(t, enrollment.RecoveryCodes[0], storedRecoveryCodes[0].CodeHash)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestEnrollMFAWhenAlreadyEnabledThrowsException(t *testing.T) {
+	// Arrange
+	mockMFARepo, mockRepo, mfaService := setupMFAService(services.MFAPolicy{})
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(true))
+
+	// Act
+	enrollment, err := mfaService.Enroll(1)
+
+	// Assert
+	assert.Nil(t, enrollment)
+	assert.Equal(t, errors.NewMFAAlreadyEnabledError(409), err)
+	mockMFARepo.AssertNotCalled(t, "SaveFactor", mock.Anything)
+}
+
+func TestActivateMFAUsingValidCodeEnablesFactor(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	factor := getMFAFactorEntity(false)
+	code, _ := authentication.TOTPCode(factor.Secret, time.Now())
+	mockMFARepo.On("GetFactor", 1).Return(factor)
+	mockMFARepo.On("UseTimeStep", 1, mock.Anything).Return(true)
+	mockMFARepo.On("SaveFactor", mock.MatchedBy(func(factor entities.MFAFactorEntity) bool {
+		return factor.Enabled && factor.EnabledAt != nil && factor.LastUsedStep > 0
+	})).Return()
+
+	// Act
+	err := mfaService.Activate(1, code)
+
+	// Assert
+	assert.NoError(t, err)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestActivateMFAUsingReplayedCodeThrowsException(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	factor := getMFAFactorEntity(false)
+	code, _ := authentication.TOTPCode(factor.Secret, time.Now())
+	// The time step of the code was already used
+	factor.LastUsedStep = time.Now().Unix()/30 + 1
+	mockMFARepo.On("GetFactor", 1).Return(factor)
+
+	// Act
+	err := mfaService.Activate(1, code)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidMFACodeError(400), err)
+	mockMFARepo.AssertNotCalled(t, "SaveFactor", mock.Anything)
+}
+
+func TestDisableMFAAsAdminRequiredByPolicyThrowsException(t *testing.T) {
+	// Arrange
+	mockMFARepo, mockRepo, mfaService := setupMFAService(services.MFAPolicy{RequiredAccountTypes: []enums.AccountType{enums.Admin}})
+	adminEntity := getUserEntities()[0]
+	adminEntity.AccountType = int(enums.Admin)
+	mockRepo.On("GetByID", 1).Return(adminEntity)
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(true))
+
+	// Act
+	err := mfaService.Disable(1, "123456")
+
+	// Assert
+	assert.Equal(t, errors.NewMFARequiredByPolicyError(403), err)
+	mockMFARepo.AssertNotCalled(t, "DeleteFactor", mock.Anything)
+}
+
+func TestBeginChallengeWithoutFactorReturnsNoChallenge(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{RequiredAccountTypes: []enums.AccountType{enums.Admin}})
+	mockMFARepo.On("GetFactor", 1).Return(entities.MFAFactorEntity{})
+
+	// Act
+	challenge, err := mfaService.BeginChallenge(models.User{ID: 1, AccountType: enums.User})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, challenge)
+	mockMFARepo.AssertNotCalled(t, "CreateChallenge", mock.Anything)
+}
+
+func TestBeginChallengeAsAdminRequiredByPolicyRequiresEnrollment(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{RequiredAccountTypes: []enums.AccountType{enums.Admin}})
+	var storedChallenge entities.MFAChallengeEntity
+	mockMFARepo.On("GetFactor", 1).Return(entities.MFAFactorEntity{})
+	mockMFARepo.On("CountChallengeFailures", 1, mock.Anything).Return(0)
+	mockMFARepo.On("CreateChallenge", mock.Anything).Run(func(args mock.Arguments) {
+		storedChallenge = args.Get(0).(entities.MFAChallengeEntity)
+	}).Return(entities.MFAChallengeEntity{})
+
+	// Act
+	challenge, err := mfaService.BeginChallenge(models.User{ID: 1, AccountType: enums.Admin})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, challenge.MFARequired)
+	assert.True(t, challenge.MFAEnrollmentRequired)
+	assert.Empty(t, challenge.AccessToken)
+	assert.Equal(t, authentication.HashOpaqueToken(challenge.MFAToken), storedChallenge.TokenHash)
+}
+
+func TestVerifyChallengeUsingValidCodeReturnsUser(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	factor := getMFAFactorEntity(true)
+	code, _ := authentication.TOTPCode(factor.Secret, time.Now())
+	mockMFARepo.On("GetChallengeByTokenHash", authentication.HashOpaqueToken("mfa-token")).Return(getMFAChallengeEntity("mfa-token"))
+	mockMFARepo.On("GetFactor", 1).Return(factor)
+	mockMFARepo.On("UseTimeStep", 1, mock.Anything).Return(true)
+	mockMFARepo.On("MarkChallengeAsUsed", 1).Return(true)
+
+	// Act
+	userID, err := mfaService.VerifyChallenge("mfa-token", code)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userID)
+	mockMFARepo.AssertNotCalled(t, "SaveFactor", mock.Anything)
+}
+
+func TestVerifyChallengeUsingInvalidCodeCountsAttempt(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	mockMFARepo.On("GetChallengeByTokenHash", authentication.HashOpaqueToken("mfa-token")).Return(getMFAChallengeEntity("mfa-token"))
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(true))
+	mockMFARepo.On("UseRecoveryCode", 1, mock.Anything).Return(false)
+	mockMFARepo.On("IncrementChallengeAttempts", 1).Return()
+	mockMFARepo.On("CountChallengeFailures", 1, mock.Anything).Return(1)
+
+	// Act
+	userID, err := mfaService.VerifyChallenge("mfa-token", "abcdef")
+
+	// Assert
+	assert.Equal(t, 1, userID)
+	assert.Equal(t, errors.NewInvalidMFACodeError(401), err)
+	mockMFARepo.AssertCalled(t, "IncrementChallengeAttempts", 1)
+	mockMFARepo.AssertNotCalled(t, "MarkChallengeAsUsed", mock.Anything)
+}
+
+func TestVerifyChallengeAfterTooManyAttemptsThrowsException(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	challenge := getMFAChallengeEntity("mfa-token")
+	challenge.Attempts = 5
+	mockMFARepo.On("GetChallengeByTokenHash", authentication.HashOpaqueToken("mfa-token")).Return(challenge)
+
+	// Act
+	_, err := mfaService.VerifyChallenge("mfa-token", "123456")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidMFATokenError(401), err)
+	mockMFARepo.AssertNotCalled(t, "GetFactor", mock.Anything)
+}
+
+func TestVerifyChallengeReachingUserFailureLimitRevokesOpenChallenges(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	mockMFARepo.On("GetChallengeByTokenHash", authentication.HashOpaqueToken("mfa-token")).Return(getMFAChallengeEntity("mfa-token"))
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(true))
+	mockMFARepo.On("UseRecoveryCode", 1, mock.Anything).Return(false)
+	mockMFARepo.On("IncrementChallengeAttempts", 1).Return()
+	mockMFARepo.On("CountChallengeFailures", 1, mock.Anything).Return(10)
+	mockMFARepo.On("RevokeChallenges", 1).Return()
+
+	// Act
+	_, err := mfaService.VerifyChallenge("mfa-token", "abcdef")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidMFACodeError(401), err)
+	mockMFARepo.AssertCalled(t, "RevokeChallenges", 1)
+}
+
+func TestBeginChallengeAfterUserFailureLimitThrowsException(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(true))
+	mockMFARepo.On("CountChallengeFailures", 1, mock.Anything).Return(10)
+
+	// Act
+	challenge, err := mfaService.BeginChallenge(models.User{ID: 1, AccountType: enums.User})
+
+	// Assert
+	assert.Nil(t, challenge)
+	assert.IsType(t, &errors.AccountLockedError{}, err)
+	mockMFARepo.AssertNotCalled(t, "CreateChallenge", mock.Anything)
+}
+
+func TestVerifyChallengeUsingRecoveryCodeReturnsUser(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	mockMFARepo.On("GetChallengeByTokenHash", authentication.HashOpaqueToken("mfa-token")).Return(getMFAChallengeEntity("mfa-token"))
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(true))
+	// Recovery codes are accepted regardless of case and dashes
+	mockMFARepo.On("UseRecoveryCode", 1, authentication.HashRecoveryCode("abcd-efgh-ijkl-mnop")).Return(true)
+	mockMFARepo.On("CountRecoveryCodes", 1).Return(9)
+	mockMFARepo.On("MarkChallengeAsUsed", 1).Return(true)
+
+	// Act
+	userID, err := mfaService.VerifyChallenge("mfa-token", "ABCDEFGHIJKLMNOP")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userID)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestVerifyChallengeUsingRecoveryCodeBeforeActivationThrowsException(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{RequiredAccountTypes: []enums.AccountType{enums.Admin}})
+	mockMFARepo.On("GetChallengeByTokenHash", authentication.HashOpaqueToken("mfa-token")).Return(getMFAChallengeEntity("mfa-token"))
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(false))
+	mockMFARepo.On("IncrementChallengeAttempts", 1).Return()
+	mockMFARepo.On("CountChallengeFailures", 1, mock.Anything).Return(1)
+
+	// Act
+	_, err := mfaService.VerifyChallenge("mfa-token", "abcd-efgh-ijkl-mnop")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidMFACodeError(401), err)
+	mockMFARepo.AssertNotCalled(t, "UseRecoveryCode", mock.Anything, mock.Anything)
+}
+
+func TestRegenerateRecoveryCodesUsingValidCodeReplacesCodes(t *testing.T) {
+	// Arrange
+	mockMFARepo, _, mfaService := setupMFAService(services.MFAPolicy{})
+	factor := getMFAFactorEntity(true)
+	code, _ := authentication.TOTPCode(factor.Secret, time.Now())
+	mockMFARepo.On("GetFactor", 1).Return(factor)
+	mockMFARepo.On("UseTimeStep", 1, mock.Anything).Return(true)
+	mockMFARepo.On("ReplaceRecoveryCodes", 1, mock.Anything).Return()
+
+	// Act
+	recoveryCodes, err := mfaService.RegenerateRecoveryCodes(1, code)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes.RecoveryCodes, 10)
+	mockMFARepo.AssertCalled(t, "ReplaceRecoveryCodes", 1, mock.Anything)
+}
+
+func TestStatusReturnsRemainingRecoveryCodes(t *testing.T) {
+	// Arrange
+	mockMFARepo, mockRepo, mfaService := setupMFAService(services.MFAPolicy{})
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockMFARepo.On("GetFactor", 1).Return(getMFAFactorEntity(true))
+	mockMFARepo.On("CountRecoveryCodes", 1).Return(7)
+
+	// Act
+	status := mfaService.Status(1)
+
+	// Assert
+	assert.True(t, status.Enabled)
+	assert.False(t, status.Required)
+	assert.Equal(t, 7, status.RecoveryCodesRemaining)
+}
@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestOAuthClientService struct {
+}
+
+// Setup
+func setupOAuthClientService() (*mock_repositories.MockOAuthClientRepository, *mock_repositories.MockJwtTokenSigner, *services.OAuthClientService) {
+	mockClientRepo := new(mock_repositories.MockOAuthClientRepository)
+	mockJwtTokenSigner := new(mock_repositories.MockJwtTokenSigner)
+	clientService := services.NewOAuthClientService(mockClientRepo, authentication.NewAccountHashing(authentication.DefaultHashingPolicy()), mockJwtTokenSigner)
+	return mockClientRepo, mockJwtTokenSigner, clientService
+}
+
+func getOAuthClientEntity() entities.OAuthClientEntity {
+	return entities.OAuthClientEntity{
+		ID:         1,
+		ClientID:   "booking-service",
+		Name:       "Booking Service",
+		SecretHash: "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", // 1234!
+		Scopes:     "users:read bookings:write",
+	}
+}
+
+// Service Unit Tests
+func TestRegisterClientReturnsSecretAndStoresHash(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	var storedClient entities.OAuthClientEntity
+	mockClientRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		storedClient = args.Get(0).(entities.OAuthClientEntity)
+	}).Return(entities.OAuthClientEntity{ID: 1, ClientID: "client-1", Name: "Booking Service", Scopes: "users:read"})
+
+	// Act
+	client, err := clientService.Register(request.RegisterClientRequest{Name: "Booking Service", Scopes: []string{"users:read"}})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, client.ClientSecret)
+	assert.NotEqual(t, client.ClientSecret, storedClient.SecretHash)
+	assert.Equal(t, []string{"users:read"}, client.Scopes)
+}
+
+func TestIssueClientCredentialsTokenUsingValidCredentialsReturnsToken(t *testing.T) {
+	// Arrange
+	mockClientRepo, mockJwtTokenSigner, clientService := setupOAuthClientService()
+	mockClientRepo.On("GetByClientID", "booking-service").Return(getOAuthClientEntity())
+	mockJwtTokenSigner.On("SignToken", mock.MatchedBy(func(claims jwt.MapClaims) bool {
+		return claims["sub"] == "booking-service" && claims["role"] == "service" && claims["scope"] == "users:read"
+	})).Return("Mock Access Token", nil)
+
+	// Act
+	tokenResponse, err := clientService.IssueClientCredentialsToken("booking-service", "1234!", "users:read")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Mock Access Token", tokenResponse.AccessToken)
+	assert.Equal(t, "users:read", tokenResponse.Scope)
+}
+
+func TestIssueClientCredentialsTokenWithoutScopeGrantsAllowedScopes(t *testing.T) {
+	// Arrange
+	mockClientRepo, mockJwtTokenSigner, clientService := setupOAuthClientService()
+	mockClientRepo.On("GetByClientID", "booking-service").Return(getOAuthClientEntity())
+	mockJwtTokenSigner.On("SignToken", mock.Anything).Return("Mock Access Token", nil)
+
+	// Act
+	tokenResponse, err := clientService.IssueClientCredentialsToken("booking-service", "1234!", "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "users:read bookings:write", tokenResponse.Scope)
+}
+
+func TestIssueClientCredentialsTokenUsingInvalidSecretThrowsException(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	mockClientRepo.On("GetByClientID", "booking-service").Return(getOAuthClientEntity())
+
+	// Act
+	tokenResponse, err := clientService.IssueClientCredentialsToken("booking-service", "4321!", "")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, errors.NewInvalidClientError(401), err)
+	assert.Nil(t, tokenResponse)
+}
+
+func TestIssueClientCredentialsTokenUsingDisallowedScopeThrowsException(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	mockClientRepo.On("GetByClientID", "booking-service").Return(getOAuthClientEntity())
+
+	// Act
+	tokenResponse, err := clientService.IssueClientCredentialsToken("booking-service", "1234!", "users:write")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, errors.NewInvalidScopeError("users:write", 400), err)
+	assert.Nil(t, tokenResponse)
+}
+
+func TestRegisterPublicClientDoesNotIssueSecret(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	var storedClient entities.OAuthClientEntity
+	mockClientRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		storedClient = args.Get(0).(entities.OAuthClientEntity)
+	}).Return(entities.OAuthClientEntity{ID: 2, ClientID: "client-2", Name: "Partner App", RedirectURIs: "https://partner.example/callback", Public: true})
+
+	// Act
+	client, err := clientService.Register(request.RegisterClientRequest{Name: "Partner App", RedirectURIs: []string{"https://partner.example/callback"}, Public: true})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, client.ClientSecret)
+	assert.Empty(t, storedClient.SecretHash)
+	assert.Equal(t, []string{"https://partner.example/callback"}, client.RedirectURIs)
+	assert.False(t, clientService.Authenticate("client-2", ""))
+}
+
+func TestAuthenticateUsingValidCredentialsReturnsTrue(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	mockClientRepo.On("GetByClientID", "booking-service").Return(getOAuthClientEntity())
+
+	// Act
+	isAuthenticated := clientService.Authenticate("booking-service", "1234!")
+
+	// Assert
+	assert.True(t, isAuthenticated)
+}
+
+func TestAuthenticateUsingInvalidCredentialsReturnsFalse(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	mockClientRepo.On("GetByClientID", "booking-service").Return(getOAuthClientEntity())
+	mockClientRepo.On("GetByClientID", "unknown-service").Return(entities.OAuthClientEntity{})
+
+	// Act
+	invalidSecret := clientService.Authenticate("booking-service", "4321!")
+	unknownClient := clientService.Authenticate("unknown-service", "1234!")
+	missingSecret := clientService.Authenticate("booking-service", "")
+
+	// Assert
+	assert.False(t, invalidSecret)
+	assert.False(t, unknownClient)
+	assert.False(t, missingSecret)
+}
+
+func TestAuthorizeIntrospectionUsingClientWithIntrospectScopeReturnsNoError(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	client := getOAuthClientEntity()
+	client.Scopes = "users:read introspect"
+	mockClientRepo.On("GetByClientID", "booking-service").Return(client)
+
+	// Act
+	err := clientService.AuthorizeIntrospection("booking-service", "1234!")
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestAuthorizeIntrospectionUsingClientWithoutIntrospectScopeThrowsException(t *testing.T) {
+	// Arrange
+	mockClientRepo, _, clientService := setupOAuthClientService()
+	mockClientRepo.On("GetByClientID", "booking-service").Return(getOAuthClientEntity())
+
+	// Act
+	validSecret := clientService.AuthorizeIntrospection("booking-service", "1234!")
+	invalidSecret := clientService.AuthorizeIntrospection("booking-service", "4321!")
+
+	// Assert
+	assert.Equal(t, errors.NewInsufficientScopeError("introspect", 403), validSecret)
+	assert.Equal(t, errors.NewInvalidClientError(401), invalidSecret)
+}
@@ -0,0 +1,161 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/validation"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestPasswordChangeService struct {
+}
+
+// Setup
+func setupPasswordChangeService(passwordHistoryService *mock_repositories.MockPasswordHistoryService, lockoutService *mock_repositories.MockAccountLockoutService) (*mock_repositories.MockUserRepository, *mock_repositories.MockTokenRevocationService, *mock_repositories.MockEventPublisher, *services.PasswordChangeService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockRevocationService := new(mock_repositories.MockTokenRevocationService)
+	mockEventPublisher := new(mock_repositories.MockEventPublisher)
+	passwordChangeService := services.NewPasswordChangeService(mockRepo, mockRevocationService, lockoutService, mockEventPublisher, newFixtureAccountHashing(), validation.PasswordValidator{}, passwordHistoryService)
+	return mockRepo, mockRevocationService, mockEventPublisher, passwordChangeService
+}
+
+// Service Unit Tests
+func TestChangePasswordUsingCurrentPasswordChangesPassword(t *testing.T) {
+	// Arrange
+	mockRepo, mockRevocationService, mockEventPublisher, passwordChangeService := setupPasswordChangeService(newPermissivePasswordHistoryService(), newPermissiveLockoutService())
+	changeRequest := request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd", RefreshToken: "Mock Refresh Token"}
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockRepo.On("UpdatePassword", 1, mock.MatchedBy(func(passwordHash string) bool {
+		return passwordHash != "" && passwordHash != "Sup3r$ecurePassw0rd"
+	})).Return(true)
+	mockRevocationService.On("RevokeOtherSessions", 1, "Mock Refresh Token").Return()
+	mockEventPublisher.On("Publish", "user_password_changed", mock.Anything).Return(nil)
+
+	// Act
+	err := passwordChangeService.ChangePassword(1, changeRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRevocationService.AssertExpectations(t)
+	mockEventPublisher.AssertExpectations(t)
+}
+
+func TestChangePasswordUsingWrongCurrentPasswordThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockRevocationService, mockEventPublisher, passwordChangeService := setupPasswordChangeService(newPermissivePasswordHistoryService(), newPermissiveLockoutService())
+	changeRequest := request.ChangePasswordRequest{CurrentPassword: "4321!", NewPassword: "Sup3r$ecurePassw0rd"}
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+
+	// Act
+	err := passwordChangeService.ChangePassword(1, changeRequest)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidCredentialsError(403), err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	mockRevocationService.AssertNotCalled(t, "RevokeOtherSessions", mock.Anything, mock.Anything)
+	mockEventPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestChangePasswordUsingWrongCurrentPasswordRecordsFailedAttempt(t *testing.T) {
+	// Arrange
+	mockLockoutService := new(mock_repositories.MockAccountLockoutService)
+	mockRepo, _, _, passwordChangeService := setupPasswordChangeService(newPermissivePasswordHistoryService(), mockLockoutService)
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockLockoutService.On("Check", 1).Return(nil)
+	mockLockoutService.On("RecordFailure", 1).Return()
+
+	// Act
+	err := passwordChangeService.ChangePassword(1, request.ChangePasswordRequest{CurrentPassword: "4321!", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidCredentialsError(403), err)
+	mockLockoutService.AssertExpectations(t)
+}
+
+func TestChangePasswordOfLockedAccountThrowsException(t *testing.T) {
+	// Arrange
+	mockLockoutService := new(mock_repositories.MockAccountLockoutService)
+	mockRepo, _, _, passwordChangeService := setupPasswordChangeService(newPermissivePasswordHistoryService(), mockLockoutService)
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockLockoutService.On("Check", 1).Return(errors.NewAccountLockedError(15*time.Minute, 423))
+
+	// Act
+	err := passwordChangeService.ChangePassword(1, request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.IsType(t, &errors.AccountLockedError{}, err)
+	// The password is not even compared while the account is locked
+	mockLockoutService.AssertNotCalled(t, "RecordFailure", mock.Anything)
+	mockLockoutService.AssertNotCalled(t, "RecordSuccess", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestChangePasswordOfNonExistingUserThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, _, _, passwordChangeService := setupPasswordChangeService(newPermissivePasswordHistoryService(), newPermissiveLockoutService())
+	mockRepo.On("GetByID", 3).Return(entities.UserEntity{})
+
+	// Act
+	err := passwordChangeService.ChangePassword(3, request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.Equal(t, errors.NewUserNotFoundError(3, 404), err)
+}
+
+func TestChangePasswordToWeakPasswordThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, _, _, passwordChangeService := setupPasswordChangeService(newPermissivePasswordHistoryService(), newPermissiveLockoutService())
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+
+	// Act
+	err := passwordChangeService.ChangePassword(1, request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "short"})
+
+	// Assert
+	assert.IsType(t, &errors.InsufficientPasswordLengthError{}, err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestChangePasswordUsingRecentPasswordThrowsException(t *testing.T) {
+	// Arrange
+	mockPasswordHistoryService := new(mock_repositories.MockPasswordHistoryService)
+	mockRepo, _, _, passwordChangeService := setupPasswordChangeService(mockPasswordHistoryService, newPermissiveLockoutService())
+	userEntity := getUserEntities()[0]
+	mockRepo.On("GetByID", 1).Return(userEntity)
+	mockPasswordHistoryService.On("CheckReuse", 1, userEntity.Password, "Sup3r$ecurePassw0rd", 5).Return(errors.NewPasswordReusedError(400))
+
+	// Act
+	err := passwordChangeService.ChangePassword(1, request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.Equal(t, errors.NewPasswordReusedError(400), err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	mockPasswordHistoryService.AssertNotCalled(t, "Remember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangePasswordRemembersReplacedPasswordHash(t *testing.T) {
+	// Arrange
+	mockPasswordHistoryService := new(mock_repositories.MockPasswordHistoryService)
+	mockRepo, mockRevocationService, mockEventPublisher, passwordChangeService := setupPasswordChangeService(mockPasswordHistoryService, newPermissiveLockoutService())
+	userEntity := getUserEntities()[0]
+	mockRepo.On("GetByID", 1).Return(userEntity)
+	mockRepo.On("UpdatePassword", 1, mock.Anything).Return(true)
+	mockRevocationService.On("RevokeOtherSessions", 1, "").Return()
+	mockEventPublisher.On("Publish", mock.Anything, mock.Anything).Return(nil)
+	mockPasswordHistoryService.On("CheckReuse", 1, userEntity.Password, "Sup3r$ecurePassw0rd", 5).Return(nil)
+	mockPasswordHistoryService.On("Remember", 1, userEntity.Password, 5).Return()
+
+	// Act
+	err := passwordChangeService.ChangePassword(1, request.ChangePasswordRequest{CurrentPassword: "1234!", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.NoError(t, err)
+	mockPasswordHistoryService.AssertExpectations(t)
+}
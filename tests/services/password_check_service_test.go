@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/validation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestPasswordCheckService struct {
+}
+
+// Setup
+func setupPasswordCheckService() *services.PasswordCheckService {
+	passwordValidator := validation.PasswordValidator{}
+	passwordValidator.SetPolicy(enums.Admin, validation.DefaultAdminPasswordPolicy())
+	return services.NewPasswordCheckService(passwordValidator, validation.NewStrengthEstimator())
+}
+
+// Service Unit Tests
+func TestCheckWeakPasswordReturnsEveryViolation(t *testing.T) {
+	// Arrange
+	passwordCheckService := setupPasswordCheckService()
+
+	// Act
+	checkResponse := passwordCheckService.Check(request.PasswordCheckRequest{Password: "john1990", FullName: "John Doe", Email: "john@doe.it"})
+
+	// Assert
+	assert.False(t, checkResponse.Valid)
+	assert.Equal(t, 0, checkResponse.Score)
+	assert.Equal(t, []string{
+		errors.NewInsufficientPasswordLengthError(13, 400).Error(),
+		errors.NewInsufficientPasswordComplexityError(validation.DefaultPasswordPolicy().RequiredClasses(), 400).Error(),
+	}, checkResponse.Violations)
+	assert.Contains(t, checkResponse.Suggestions, validation.SuggestionPersonal)
+}
+
+func TestCheckWithoutAccountTypeUsesPolicyOfUsers(t *testing.T) {
+	// Arrange
+	passwordCheckService := setupPasswordCheckService()
+
+	// Act
+	checkResponse := passwordCheckService.Check(request.PasswordCheckRequest{Password: "xK#9vL2!qP7@mZ4"})
+
+	// Assert
+	assert.True(t, checkResponse.Valid)
+}
+
+func TestCheckUsesPolicyOfAccountType(t *testing.T) {
+	// Arrange
+	passwordCheckService := setupPasswordCheckService()
+	password := "xK#9vL2!qP7@mZ4"
+	user, admin := enums.User, enums.Admin
+
+	// Act
+	userResponse := passwordCheckService.Check(request.PasswordCheckRequest{Password: password, AccountType: &user})
+	adminResponse := passwordCheckService.Check(request.PasswordCheckRequest{Password: password, AccountType: &admin})
+
+	// Assert
+	assert.True(t, userResponse.Valid)
+	assert.Empty(t, userResponse.Violations)
+	assert.False(t, adminResponse.Valid)
+	assert.Equal(t, []string{errors.NewInsufficientPasswordLengthError(16, 400).Error()}, adminResponse.Violations)
+}
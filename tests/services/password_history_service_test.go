@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestPasswordHistoryService struct {
+}
+
+// Setup
+func setupPasswordHistoryService() (*mock_repositories.MockPasswordHistoryRepository, *services.PasswordHistoryService) {
+	mockHistoryRepo := new(mock_repositories.MockPasswordHistoryRepository)
+	passwordHistoryService := services.NewPasswordHistoryService(mockHistoryRepo, newFixtureAccountHashing())
+	return mockHistoryRepo, passwordHistoryService
+}
+
+// Password history that never refuses a password, for the tests that are not about reuse
+func newPermissivePasswordHistoryService() *mock_repositories.MockPasswordHistoryService {
+	mockPasswordHistoryService := new(mock_repositories.MockPasswordHistoryService)
+	mockPasswordHistoryService.On("CheckReuse", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockPasswordHistoryService.On("Remember", mock.Anything, mock.Anything, mock.Anything).Return()
+	return mockPasswordHistoryService
+}
+
+const (
+	currentPasswordHash  = "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2" // 1234!
+	previousPasswordHash = "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO" // 4321!
+)
+
+// Service Unit Tests
+func TestCheckReuseOfCurrentPasswordThrowsException(t *testing.T) {
+	// Arrange
+	mockHistoryRepo, passwordHistoryService := setupPasswordHistoryService()
+
+	// Act
+	err := passwordHistoryService.CheckReuse(1, currentPasswordHash, "1234!", 5)
+
+	// Assert
+	assert.Equal(t, errors.NewPasswordReusedError(400), err)
+	mockHistoryRepo.AssertNotCalled(t, "GetRecentByUserID", mock.Anything, mock.Anything)
+}
+
+func TestCheckReuseOfPreviousPasswordThrowsException(t *testing.T) {
+	// Arrange
+	mockHistoryRepo, passwordHistoryService := setupPasswordHistoryService()
+	// The current password counts towards the depth
+	mockHistoryRepo.On("GetRecentByUserID", 1, 4).Return([]entities.PasswordHistoryEntity{{UserID: 1, PasswordHash: previousPasswordHash}})
+
+	// Act
+	err := passwordHistoryService.CheckReuse(1, currentPasswordHash, "4321!", 5)
+
+	// Assert
+	assert.Equal(t, errors.NewPasswordReusedError(400), err)
+}
+
+func TestCheckReuseOfNewPasswordReturnsNil(t *testing.T) {
+	// Arrange
+	mockHistoryRepo, passwordHistoryService := setupPasswordHistoryService()
+	mockHistoryRepo.On("GetRecentByUserID", 1, 4).Return([]entities.PasswordHistoryEntity{{UserID: 1, PasswordHash: previousPasswordHash}})
+
+	// Act
+	err := passwordHistoryService.CheckReuse(1, currentPasswordHash, "Sup3r$ecurePassw0rd", 5)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestCheckReuseWithoutHistoryDepthAllowsCurrentPassword(t *testing.T) {
+	// Arrange
+	_, passwordHistoryService := setupPasswordHistoryService()
+
+	// Act
+	err := passwordHistoryService.CheckReuse(1, currentPasswordHash, "1234!", 0)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestRememberStoresReplacedHashAndPrunesHistory(t *testing.T) {
+	// Arrange
+	mockHistoryRepo, passwordHistoryService := setupPasswordHistoryService()
+	mockHistoryRepo.On("Create", mock.MatchedBy(func(historyEntity entities.PasswordHistoryEntity) bool {
+		return historyEntity.UserID == 1 && historyEntity.PasswordHash == currentPasswordHash
+	})).Return(entities.PasswordHistoryEntity{})
+	mockHistoryRepo.On("DeleteAllButRecent", 1, 4).Return()
+
+	// Act
+	passwordHistoryService.Remember(1, currentPasswordHash, 5)
+
+	// Assert
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestRememberWithHistoryDepthOfOneOnlyPrunesHistory(t *testing.T) {
+	// Arrange
+	mockHistoryRepo, passwordHistoryService := setupPasswordHistoryService()
+	mockHistoryRepo.On("DeleteAllButRecent", 1, 0).Return()
+
+	// Act
+	passwordHistoryService.Remember(1, currentPasswordHash, 1)
+
+	// Assert
+	mockHistoryRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockHistoryRepo.AssertExpectations(t)
+}
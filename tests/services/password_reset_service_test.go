@@ -0,0 +1,211 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/validation"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestPasswordResetService struct {
+}
+
+// Setup
+func setupPasswordResetService() (*mock_repositories.MockUserRepository, *mock_repositories.MockPasswordResetRepository, *mock_repositories.MockTokenRevocationService, *mock_repositories.MockMailer, *services.PasswordResetService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockResetRepo := new(mock_repositories.MockPasswordResetRepository)
+	mockRevocationService := new(mock_repositories.MockTokenRevocationService)
+	mockMailer := new(mock_repositories.MockMailer)
+	passwordResetService := services.NewPasswordResetService(mockRepo, mockResetRepo, mockRevocationService, mockMailer, authentication.NewAccountHashing(authentication.DefaultHashingPolicy()), validation.PasswordValidator{}, newPermissivePasswordHistoryService(), "https://flyhorizons.test/reset-password")
+	return mockRepo, mockResetRepo, mockRevocationService, mockMailer, passwordResetService
+}
+
+func getPasswordResetTokenEntity(resetToken string) entities.PasswordResetTokenEntity {
+	return entities.PasswordResetTokenEntity{
+		ID:        1,
+		TokenHash: authentication.HashOpaqueToken(resetToken),
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Minute),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Service Unit Tests
+func TestSendResetLinkForExistingEmailSendsResetLink(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, _, mockMailer, passwordResetService := setupPasswordResetService()
+	var storedToken entities.PasswordResetTokenEntity
+	var emailBody string
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockResetRepo.On("InvalidateAllForUser", 1).Return()
+	mockResetRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		storedToken = args.Get(0).(entities.PasswordResetTokenEntity)
+	}).Return(entities.PasswordResetTokenEntity{})
+	mockMailer.On("Send", "john@doe.it", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		emailBody = args.String(2)
+	}).Return(nil)
+
+	// Act
+	passwordResetService.SendResetLink("john@doe.it")
+
+	// Assert
+	link := emailBody[strings.Index(emailBody, "https://flyhorizons.test/reset-password"):]
+	resetURL, err := url.Parse(strings.Fields(link)[0])
+	assert.NoError(t, err)
+	// Only the hash of the emailed token is stored
+	assert.Equal(t, authentication.HashOpaqueToken(resetURL.Query().Get("token")), storedToken.TokenHash)
+	assert.Equal(t, 1, storedToken.UserID)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), storedToken.ExpiresAt, time.Minute)
+	mockMailer.AssertExpectations(t)
+}
+
+func TestSendResetLinkForUnknownEmailSendsNothing(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, _, mockMailer, passwordResetService := setupPasswordResetService()
+	mockRepo.On("GetByEmail", "unknown@doe.it").Return(entities.UserEntity{})
+
+	// Act
+	passwordResetService.SendResetLink("unknown@doe.it")
+
+	// Assert
+	mockResetRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestForgotPasswordDoesNotLookUpAccountOnRequestPath(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, _, mockMailer, passwordResetService := setupPasswordResetService()
+
+	// Act
+	passwordResetService.Forgot(request.ForgotPasswordRequest{Email: "john@doe.it"})
+
+	// Assert
+	mockRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	mockResetRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestForgotPasswordSendsQueuedResetLinkInBackground(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, _, mockMailer, passwordResetService := setupPasswordResetService()
+	sent := make(chan string, 1)
+	mockRepo.On("GetByEmail", "john@doe.it").Return(getUserEntities()[0])
+	mockResetRepo.On("InvalidateAllForUser", 1).Return()
+	mockResetRepo.On("Create", mock.Anything).Return(entities.PasswordResetTokenEntity{})
+	mockMailer.On("Send", "john@doe.it", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent <- args.String(0)
+	}).Return(nil)
+	passwordResetService.StartResetQueue()
+
+	// Act
+	passwordResetService.Forgot(request.ForgotPasswordRequest{Email: "john@doe.it"})
+
+	// Assert
+	select {
+	case recipient := <-sent:
+		assert.Equal(t, "john@doe.it", recipient)
+	case <-time.After(time.Second):
+		t.Fatal("password reset link was not sent")
+	}
+}
+
+func TestResetPasswordUsingValidTokenUpdatesPasswordAndRevokesSessions(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, mockRevocationService, _, passwordResetService := setupPasswordResetService()
+	newPassword := "Sup3r$ecurePassw0rd"
+	mockResetRepo.On("GetByTokenHash", authentication.HashOpaqueToken("reset-token")).Return(getPasswordResetTokenEntity("reset-token"))
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockResetRepo.On("MarkAsUsed", 1).Return(true)
+	mockResetRepo.On("InvalidateAllForUser", 1).Return()
+	mockRepo.On("UpdatePassword", 1, mock.MatchedBy(func(passwordHash string) bool {
+		return authentication.NewAccountHashing(authentication.DefaultHashingPolicy()).ComparePassword(passwordHash, newPassword)
+	})).Return(true)
+	mockRevocationService.On("RevokeAllSessions", 1).Return()
+
+	// Act
+	err := passwordResetService.Reset(request.ResetPasswordRequest{Token: "reset-token", NewPassword: newPassword})
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRevocationService.AssertCalled(t, "RevokeAllSessions", 1)
+}
+
+func TestResetPasswordUsingWeakPasswordKeepsToken(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, mockRevocationService, _, passwordResetService := setupPasswordResetService()
+	mockResetRepo.On("GetByTokenHash", authentication.HashOpaqueToken("reset-token")).Return(getPasswordResetTokenEntity("reset-token"))
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+
+	// Act
+	err := passwordResetService.Reset(request.ResetPasswordRequest{Token: "reset-token", NewPassword: "short"})
+
+	// Assert
+	assert.Equal(t, errors.NewInsufficientPasswordLengthError(13, 400), err)
+	mockResetRepo.AssertNotCalled(t, "MarkAsUsed", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	mockRevocationService.AssertNotCalled(t, "RevokeAllSessions", mock.Anything)
+}
+
+func TestResetPasswordUsingExpiredTokenThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, _, _, passwordResetService := setupPasswordResetService()
+	resetToken := getPasswordResetTokenEntity("reset-token")
+	resetToken.ExpiresAt = time.Now().Add(-time.Minute)
+	mockResetRepo.On("GetByTokenHash", authentication.HashOpaqueToken("reset-token")).Return(resetToken)
+
+	// Act
+	err := passwordResetService.Reset(request.ResetPasswordRequest{Token: "reset-token", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidPasswordResetTokenError(400), err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestResetPasswordUsingUsedTokenThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, mockResetRepo, _, _, passwordResetService := setupPasswordResetService()
+	mockResetRepo.On("GetByTokenHash", authentication.HashOpaqueToken("reset-token")).Return(getPasswordResetTokenEntity("reset-token"))
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	// The token was used by a concurrent request
+	mockResetRepo.On("MarkAsUsed", 1).Return(false)
+
+	// Act
+	err := passwordResetService.Reset(request.ResetPasswordRequest{Token: "reset-token", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidPasswordResetTokenError(400), err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestResetPasswordUsingRecentPasswordKeepsToken(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockResetRepo := new(mock_repositories.MockPasswordResetRepository)
+	mockHistoryRepo := new(mock_repositories.MockPasswordHistoryRepository)
+	accountHashing := newFixtureAccountHashing()
+	passwordHistoryService := services.NewPasswordHistoryService(mockHistoryRepo, accountHashing)
+	passwordResetService := services.NewPasswordResetService(mockRepo, mockResetRepo, new(mock_repositories.MockTokenRevocationService), new(mock_repositories.MockMailer), accountHashing, validation.PasswordValidator{}, passwordHistoryService, "https://flyhorizons.test/reset-password")
+	previousHash, _ := accountHashing.HashPassword("Sup3r$ecurePassw0rd")
+	mockResetRepo.On("GetByTokenHash", authentication.HashOpaqueToken("reset-token")).Return(getPasswordResetTokenEntity("reset-token"))
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockHistoryRepo.On("GetRecentByUserID", 1, 4).Return([]entities.PasswordHistoryEntity{{UserID: 1, PasswordHash: previousHash}})
+
+	// Act
+	err := passwordResetService.Reset(request.ResetPasswordRequest{Token: "reset-token", NewPassword: "Sup3r$ecurePassw0rd"})
+
+	// Assert
+	assert.Equal(t, errors.NewPasswordReusedError(400), err)
+	mockResetRepo.AssertNotCalled(t, "MarkAsUsed", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,66 @@
+package ratelimit_test
+
+import (
+	"flyhorizons-userservice/services/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestMemoryStore struct {
+}
+
+// Store Unit Tests
+func TestTakeAllowsRequestsUpToCapacity(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	var results []bool
+
+	// Act
+	for i := 0; i < 4; i++ {
+		allowed, _ := store.Take("POST /login|ip|127.0.0.1", 3, time.Minute)
+		results = append(results, allowed)
+	}
+
+	// Assert
+	assert.Equal(t, []bool{true, true, true, false}, results)
+}
+
+func TestTakeOnEmptyBucketReturnsTimeUntilNextToken(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	store.Take("POST /login|ip|127.0.0.1", 1, time.Minute)
+
+	// Act
+	allowed, retryAfter := store.Take("POST /login|ip|127.0.0.1", 1, time.Minute)
+
+	// Assert
+	assert.False(t, allowed)
+	assert.InDelta(t, time.Minute.Seconds(), retryAfter.Seconds(), 1)
+}
+
+func TestTakeRefillsBucketOverTime(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	store.Take("POST /login|ip|127.0.0.1", 1, 50*time.Millisecond)
+
+	// Act
+	time.Sleep(60 * time.Millisecond)
+	allowed, _ := store.Take("POST /login|ip|127.0.0.1", 1, 50*time.Millisecond)
+
+	// Assert
+	assert.True(t, allowed)
+}
+
+func TestTakeKeepsKeysIndependent(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	store.Take("POST /login|ip|127.0.0.1", 1, time.Minute)
+
+	// Act
+	allowed, _ := store.Take("POST /login|ip|10.0.0.1", 1, time.Minute)
+
+	// Assert
+	assert.True(t, allowed)
+}
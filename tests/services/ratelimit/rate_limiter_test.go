@@ -0,0 +1,181 @@
+package ratelimit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/services/ratelimit"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestRateLimiter struct {
+}
+
+// Setup
+func setupRateLimitedRouter(config ratelimit.Config) *gin.Engine {
+	router := gin.Default()
+	rateLimiter := ratelimit.NewRateLimiter(ratelimit.NewMemoryStore(), config)
+	router.Use(rateLimiter.RateLimitMiddleware())
+
+	router.POST("/login", func(c *gin.Context) {
+		var loginRequest request.LoginRequest
+		if err := c.ShouldBindJSON(&loginRequest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"email": loginRequest.Email})
+	})
+	router.GET("/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	return router
+}
+
+func sendLoginRequest(router *gin.Engine, email string, ipAddress string) *httptest.ResponseRecorder {
+	requestBody, _ := json.Marshal(request.LoginRequest{Email: email, Password: "Sup3r$ecurePassw0rd"})
+	httpRequest, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.RemoteAddr = ipAddress + ":12345"
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httpRequest)
+	return responseRecorder
+}
+
+// Middleware Unit Tests
+func TestParseRateLimitReadsRequestsPerPeriod(t *testing.T) {
+	// Act
+	limit, err := ratelimit.ParseRateLimit("10/1m")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, ratelimit.RateLimit{Requests: 10, Per: time.Minute}, limit)
+}
+
+func TestParseRateLimitUsingInvalidValueThrowsException(t *testing.T) {
+	// Act
+	_, err := ratelimit.ParseRateLimit("10 per minute")
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestLoadConfigFromEnvLimitsTokenAndMFARoutes(t *testing.T) {
+	// Act
+	config := ratelimit.LoadConfigFromEnv()
+
+	// Assert
+	for _, route := range []string{"POST /token/refresh", "POST /oauth/token", "POST /oauth/introspect", "POST /login/mfa/enroll", "POST /login/passkey/options"} {
+		assert.True(t, config[route].PerIP.Enabled(), route)
+	}
+}
+
+func TestLoadConfigFromEnvLimitsPasswordChangeRoute(t *testing.T) {
+	// Act
+	config := ratelimit.LoadConfigFromEnv()
+
+	// Assert
+	assert.True(t, config["POST /users/:userID/password"].PerIP.Enabled())
+}
+
+func TestRateLimitMiddlewareOverIPLimitReturnsTooManyRequests(t *testing.T) {
+	// Arrange
+	router := setupRateLimitedRouter(ratelimit.Config{
+		"POST /login": {PerIP: ratelimit.RateLimit{Requests: 2, Per: time.Minute}},
+	})
+	sendLoginRequest(router, "john@doe.it", "10.0.0.1")
+	sendLoginRequest(router, "jane@doe.it", "10.0.0.1")
+
+	// Act
+	limited := sendLoginRequest(router, "max@doe.it", "10.0.0.1")
+	otherIP := sendLoginRequest(router, "max@doe.it", "10.0.0.2")
+
+	// Assert
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "30", limited.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusCreated, otherIP.Code)
+}
+
+func TestRateLimitMiddlewareOverEmailLimitAppliesAcrossIPs(t *testing.T) {
+	// Arrange
+	router := setupRateLimitedRouter(ratelimit.Config{
+		"POST /login": {PerEmail: ratelimit.RateLimit{Requests: 1, Per: time.Minute}},
+	})
+	sendLoginRequest(router, "john@doe.it", "10.0.0.1")
+
+	// Act
+	limited := sendLoginRequest(router, "John@Doe.it", "10.0.0.2")
+	otherEmail := sendLoginRequest(router, "jane@doe.it", "10.0.0.2")
+
+	// Assert
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "60", limited.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusCreated, otherEmail.Code)
+}
+
+func TestRateLimitMiddlewareKeepsRequestBodyForHandler(t *testing.T) {
+	// Arrange
+	router := setupRateLimitedRouter(ratelimit.Config{
+		"POST /login": {PerEmail: ratelimit.RateLimit{Requests: 5, Per: time.Minute}},
+	})
+
+	// Act
+	responseRecorder := sendLoginRequest(router, "john@doe.it", "10.0.0.1")
+
+	// Assert
+	body, _ := io.ReadAll(responseRecorder.Body)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	assert.JSONEq(t, `{"email":"john@doe.it"}`, string(body))
+}
+
+func TestRateLimitMiddlewareSkipsEmailLimitForOversizedBody(t *testing.T) {
+	// Arrange
+	router := setupRateLimitedRouter(ratelimit.Config{
+		"POST /login": {PerEmail: ratelimit.RateLimit{Requests: 1, Per: time.Minute}},
+	})
+	requestBody, _ := json.Marshal(request.LoginRequest{Email: "john@doe.it", Password: strings.Repeat("a", 8<<10)})
+	var responses []*httptest.ResponseRecorder
+
+	// Act
+	for i := 0; i < 2; i++ {
+		httpRequest, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(requestBody))
+		httpRequest.Header.Set("Content-Type", "application/json")
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, httpRequest)
+		responses = append(responses, responseRecorder)
+	}
+
+	// Assert
+	for _, responseRecorder := range responses {
+		body, _ := io.ReadAll(responseRecorder.Body)
+		assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+		assert.JSONEq(t, `{"email":"john@doe.it"}`, string(body))
+	}
+}
+
+func TestRateLimitMiddlewareIgnoresRoutesWithoutLimit(t *testing.T) {
+	// Arrange
+	router := setupRateLimitedRouter(ratelimit.Config{
+		"POST /login": {PerIP: ratelimit.RateLimit{Requests: 1, Per: time.Minute}},
+	})
+	var codes []int
+
+	// Act
+	for i := 0; i < 3; i++ {
+		httpRequest, _ := http.NewRequest("GET", "/users", nil)
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, httpRequest)
+		codes = append(codes, responseRecorder.Code)
+	}
+
+	// Assert
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, codes)
+}
@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"flyhorizons-userservice/models/request"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestTokenRevocationService struct {
+}
+
+// Setup
+func setupTokenRevocationService() (*mock_repositories.MockTokenRevocationRepository, *mock_repositories.MockRefreshTokenRepository, *services.TokenRevocationService) {
+	mockRevocationRepo := new(mock_repositories.MockTokenRevocationRepository)
+	mockRefreshTokenRepo := new(mock_repositories.MockRefreshTokenRepository)
+	revocationService := services.NewTokenRevocationService(mockRevocationRepo, mockRefreshTokenRepo)
+	return mockRevocationRepo, mockRefreshTokenRepo, revocationService
+}
+
+// Service Unit Tests
+func TestLogoutRevokesTokenAndRefreshTokenFamily(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, mockRefreshTokenRepo, revocationService := setupTokenRevocationService()
+	refreshToken := "Mock Refresh Token"
+	expiresAt := time.Now().Add(time.Minute)
+	mockRevocationRepo.On("RevokeToken", mock.MatchedBy(func(r entities.RevokedTokenEntity) bool {
+		return r.JTI == "jti-1" && r.UserID == 1 && r.ExpiresAt.Equal(expiresAt)
+	})).Return()
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(getRefreshTokenEntity(refreshToken))
+	mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return()
+
+	// Act
+	revocationService.Logout(request.LogoutRequest{RefreshToken: refreshToken}, "jti-1", 1, expiresAt)
+
+	// Assert
+	mockRevocationRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+func TestLogoutDoesNotRevokeRefreshTokenOfAnotherUser(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, mockRefreshTokenRepo, revocationService := setupTokenRevocationService()
+	refreshToken := "Mock Refresh Token"
+	mockRevocationRepo.On("RevokeToken", mock.Anything).Return()
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(getRefreshTokenEntity(refreshToken))
+
+	// Act
+	revocationService.Logout(request.LogoutRequest{RefreshToken: refreshToken}, "jti-2", 2, time.Now())
+
+	// Assert
+	mockRefreshTokenRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything)
+}
+
+func TestLogoutUsingTokenWithoutJTIRevokesAllSessions(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, mockRefreshTokenRepo, revocationService := setupTokenRevocationService()
+	mockRevocationRepo.On("RevokeSessions", mock.MatchedBy(func(r entities.SessionRevocationEntity) bool {
+		return r.UserID == 1
+	})).Return()
+	mockRefreshTokenRepo.On("RevokeAllForUser", 1).Return()
+
+	// Act
+	revocationService.Logout(request.LogoutRequest{}, "", 1, time.Now().Add(time.Minute))
+
+	// Assert
+	mockRevocationRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertExpectations(t)
+	mockRevocationRepo.AssertNotCalled(t, "RevokeToken", mock.Anything)
+}
+
+func TestRevokeAllSessionsRevokesTokensAndRefreshTokens(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, mockRefreshTokenRepo, revocationService := setupTokenRevocationService()
+	mockRevocationRepo.On("RevokeSessions", mock.MatchedBy(func(r entities.SessionRevocationEntity) bool {
+		return r.UserID == 1 && r.ExpiresAt.After(r.RevokedBefore)
+	})).Return()
+	mockRefreshTokenRepo.On("RevokeAllForUser", 1).Return()
+
+	// Act
+	revocationService.RevokeAllSessions(1)
+
+	// Assert
+	mockRevocationRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+func TestRevokeOtherSessionsKeepsRefreshTokenFamilyOfCaller(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, mockRefreshTokenRepo, revocationService := setupTokenRevocationService()
+	refreshToken := "Mock Refresh Token"
+	mockRevocationRepo.On("RevokeSessions", mock.MatchedBy(func(r entities.SessionRevocationEntity) bool {
+		return r.UserID == 1
+	})).Return()
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(getRefreshTokenEntity(refreshToken))
+	mockRefreshTokenRepo.On("RevokeAllForUserExceptFamily", 1, "family-1").Return()
+
+	// Act
+	revocationService.RevokeOtherSessions(1, refreshToken)
+
+	// Assert
+	mockRevocationRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
+}
+
+func TestRevokeOtherSessionsUsingRefreshTokenOfAnotherUserRevokesAllSessions(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, mockRefreshTokenRepo, revocationService := setupTokenRevocationService()
+	refreshToken := "Mock Refresh Token"
+	mockRevocationRepo.On("RevokeSessions", mock.Anything).Return()
+	mockRefreshTokenRepo.On("GetByTokenHash", authentication.HashOpaqueToken(refreshToken)).Return(getRefreshTokenEntity(refreshToken))
+	mockRefreshTokenRepo.On("RevokeAllForUser", 2).Return()
+
+	// Act
+	revocationService.RevokeOtherSessions(2, refreshToken)
+
+	// Assert
+	mockRefreshTokenRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertNotCalled(t, "RevokeAllForUserExceptFamily", mock.Anything, mock.Anything)
+}
+
+func TestIsRevokedUsingRevokedJTIReturnsTrue(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, _, revocationService := setupTokenRevocationService()
+	mockRevocationRepo.On("IsTokenRevoked", "jti-1").Return(true)
+
+	// Act
+	isRevoked := revocationService.IsRevoked("jti-1", 1, time.Now())
+
+	// Assert
+	assert.True(t, isRevoked)
+}
+
+func TestIsRevokedUsingTokenIssuedBeforeSessionRevocationReturnsTrue(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, _, revocationService := setupTokenRevocationService()
+	revokedBefore := time.Now()
+	mockRevocationRepo.On("IsTokenRevoked", "jti-1").Return(false)
+	mockRevocationRepo.On("GetSessionRevocation", 1).Return(entities.SessionRevocationEntity{UserID: 1, RevokedBefore: revokedBefore, ExpiresAt: revokedBefore.Add(time.Hour)})
+
+	// Act
+	isRevokedBefore := revocationService.IsRevoked("jti-1", 1, revokedBefore.Add(-time.Minute))
+	isRevokedAfter := revocationService.IsRevoked("jti-1", 1, revokedBefore.Add(time.Minute))
+
+	// Assert
+	assert.True(t, isRevokedBefore)
+	assert.False(t, isRevokedAfter)
+}
+
+func TestIsRevokedUsingTokenIssuedInSameSecondAsSessionRevocationReturnsTrue(t *testing.T) {
+	// Arrange
+	mockRevocationRepo, mockRefreshTokenRepo, revocationService := setupTokenRevocationService()
+	var sessionRevocation entities.SessionRevocationEntity
+	// Token timestamps are whole seconds, like the iat claim
+	issuedAt := time.Unix(time.Now().Unix(), 0)
+	mockRevocationRepo.On("RevokeSessions", mock.Anything).Run(func(args mock.Arguments) {
+		sessionRevocation = args.Get(0).(entities.SessionRevocationEntity)
+	}).Return()
+	mockRefreshTokenRepo.On("RevokeAllForUser", 1).Return()
+	revocationService.RevokeAllSessions(1)
+	mockRevocationRepo.On("IsTokenRevoked", "jti-1").Return(false)
+	mockRevocationRepo.On("GetSessionRevocation", 1).Return(sessionRevocation)
+
+	// Act
+	isRevoked := revocationService.IsRevoked("jti-1", 1, issuedAt)
+
+	// Assert
+	assert.True(t, isRevoked)
+}
@@ -1,7 +1,11 @@
 package services_test
 
 import (
+	"encoding/json"
 	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/models/enums"
+	"flyhorizons-userservice/models/request"
+	"flyhorizons-userservice/models/response"
 	entities "flyhorizons-userservice/repositories/entity"
 	"flyhorizons-userservice/services"
 	"flyhorizons-userservice/services/authentication"
@@ -22,13 +26,22 @@ type TestUserService struct {
 // Setup
 func setupUserService() (*mock_repositories.MockUserRepository, *services.UserService) {
 	mockRepo := new(mock_repositories.MockUserRepository)
-	accountHashing := new(authentication.AccountHashing)
+	accountHashing := authentication.NewAccountHashing(authentication.DefaultHashingPolicy())
 	userConverter := new(converter.UserConverter)
 	passwordValidator := new(validation.PasswordValidator)
-	userService := services.NewUserService(mockRepo, accountHashing, *passwordValidator, *userConverter)
+	mockEmailVerificationService := new(mock_repositories.MockEmailVerificationService)
+	mockEmailVerificationService.On("SendVerification", mock.Anything).Return(nil)
+	userService := services.NewUserService(mockRepo, accountHashing, *passwordValidator, *userConverter, mockEmailVerificationService)
 	return mockRepo, userService
 }
 
+func setupUserServiceWithEmailVerification() (*mock_repositories.MockUserRepository, *mock_repositories.MockEmailVerificationService, *services.UserService) {
+	mockRepo := new(mock_repositories.MockUserRepository)
+	mockEmailVerificationService := new(mock_repositories.MockEmailVerificationService)
+	userService := services.NewUserService(mockRepo, authentication.NewAccountHashing(authentication.DefaultHashingPolicy()), validation.PasswordValidator{}, converter.UserConverter{}, mockEmailVerificationService)
+	return mockRepo, mockEmailVerificationService, userService
+}
+
 func getCurrentDateTime() time.Time {
 	return time.Now()
 }
@@ -42,6 +55,7 @@ func getUserEntities() []entities.UserEntity {
 			AccountType: 1,
 			Password:    "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", // 1234!
 			CreatedAt:   getCurrentDateTime(),
+			Version:     1,
 		},
 		{
 			ID:          2,
@@ -50,35 +64,61 @@ func getUserEntities() []entities.UserEntity {
 			AccountType: 0,
 			Password:    "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", // 4321!
 			CreatedAt:   getCurrentDateTime(),
+			Version:     1,
 		},
 	}
 }
 
-func getUsers() []models.User {
-	return []models.User{
+func getUserProfiles() []response.UserProfileResponse {
+	return []response.UserProfileResponse{
 		{
 			ID:          1,
 			FullName:    "John Doe",
 			Email:       "john@doe.it",
-			AccountType: 1,
-			Password:    "$2a$12$XbjoIVKp5miCCKU87B83S.Z5/OUMjS7OyQ5pW.UoieAyUeFW2G4q2", // 1234!
+			AccountType: enums.User,
+			Version:     1,
 		},
 		{
 			ID:          2,
 			FullName:    "Jane Doe",
 			Email:       "jane@doe.nl",
-			AccountType: 0,
-			Password:    "$2a$12$7/NMoWfjAzIZhkK/6S4yy.Pnvo1YbF1lxIR2ehNgKfz0xzVyExzZO", // 4321!
+			AccountType: enums.Admin,
+			Version:     1,
+		},
+	}
+}
+
+func getCreateUserRequests() []request.CreateUserRequest {
+	return []request.CreateUserRequest{
+		{
+			FullName: "John Doe",
+			Email:    "john@doe.it",
+			Password: "Sup3r$ecurePassw0rd",
+		},
+		{
+			FullName: "Jane Doe",
+			Email:    "jane@doe.nl",
+			Password: "An0ther$ecurePassw0rd",
 		},
 	}
 }
 
+func getUpdateUserRequests() []request.UpdateUserRequest {
+	return []request.UpdateUserRequest{
+		{ID: 1, FullName: "John Doe"},
+		{ID: 2, FullName: "Jane Doe"},
+	}
+}
+
 // Service Unit Tests
 func TestGetAllUsersReturnsUsers(t *testing.T) {
 	// Arrange
 	mockRepo, userService := setupUserService()
 	userEntities := getUserEntities()
-	expected := getUsers()
+	expected := []response.AdminUserResponse{
+		{ID: 1, FullName: "John Doe", Email: "john@doe.it", AccountType: enums.User, CreatedAt: userEntities[0].CreatedAt},
+		{ID: 2, FullName: "Jane Doe", Email: "jane@doe.nl", AccountType: enums.Admin, CreatedAt: userEntities[1].CreatedAt},
+	}
 	mockRepo.On("GetAll").Return(userEntities)
 
 	// Act
@@ -94,7 +134,7 @@ func TestGetUserByValidIDReturnsUser(t *testing.T) {
 	userEntity := getUserEntities()[0]
 	userID := 1
 	mockRepo.On("GetByID", userID).Return(userEntity)
-	expected := getUsers()[0]
+	expected := getUserProfiles()[0]
 
 	// Act
 	user, err := userService.GetByID(userID)
@@ -122,12 +162,12 @@ func TestGetByInvalidIDThrowsException(t *testing.T) {
 func TestCreateNonExistingUserReturnsCreatedUser(t *testing.T) {
 	// Arrange
 	mockRepo, userService := setupUserService()
-	user := getUsers()[0]
+	user := getCreateUserRequests()[0]
 	userEntity := getUserEntities()[0]
 
-	mockRepo.On("GetAll").Return([]entities.UserEntity{getUserEntities()[1]})
+	mockRepo.On("GetByEmail", user.Email).Return(entities.UserEntity{})
 	mockRepo.On("Create", mock.MatchedBy(func(u entities.UserEntity) bool {
-		return u.ID == userEntity.ID // Ignore password and CreatedAt differences
+		return u.Email == user.Email && u.AccountType == int(enums.User) // Ignore password and CreatedAt differences
 	})).Return(userEntity)
 
 	// Act
@@ -135,30 +175,47 @@ func TestCreateNonExistingUserReturnsCreatedUser(t *testing.T) {
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, user.ID, postUser.ID)
+	assert.Equal(t, userEntity.ID, postUser.ID)
 	assert.Equal(t, user.FullName, postUser.FullName)
 	assert.Equal(t, user.Email, postUser.Email)
-	assert.Equal(t, user.AccountType, postUser.AccountType)
+	assert.Equal(t, enums.User, postUser.AccountType)
+}
+
+func TestCreateUserUsingPasswordBelowUserPolicyThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockUserRepository)
+	passwordValidator := validation.PasswordValidator{}
+	passwordValidator.SetPolicy(enums.User, validation.DefaultAdminPasswordPolicy())
+	userService := services.NewUserService(mockRepo, authentication.NewAccountHashing(authentication.DefaultHashingPolicy()), passwordValidator, converter.UserConverter{}, new(mock_repositories.MockEmailVerificationService))
+	user := getCreateUserRequests()[1]
+	user.Password = "Fontysict1234!"
+	mockRepo.On("GetByEmail", user.Email).Return(entities.UserEntity{})
+
+	// Act
+	postUser, err := userService.Create(user)
+
+	// Assert
+	assert.Equal(t, errors.NewInsufficientPasswordLengthError(16, 400), err)
+	assert.Nil(t, postUser)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
 }
 
 func TestCreateExistingUserThrowsException(t *testing.T) {
 	// Arrange
 	mockRepo, userService := setupUserService()
-	user := getUsers()[0]
+	user := getCreateUserRequests()[0]
 	userEntity := getUserEntities()[0]
 
-	mockRepo.On("GetAll").Return([]entities.UserEntity{getUserEntities()[0]})
-	mockRepo.On("Create", mock.MatchedBy(func(u entities.UserEntity) bool {
-		return u.ID == userEntity.ID // Ignore password and CreatedAt differences
-	})).Return(userEntity)
+	mockRepo.On("GetByEmail", user.Email).Return(userEntity)
 
 	// Act
 	postUser, err := userService.Create(user)
 
 	// Assert
 	assert.Error(t, err)
-	assert.Equal(t, errors.NewUserExistsError(1, 409), err)
+	assert.Equal(t, errors.NewEmailInUseError(409), err)
 	assert.Nil(t, postUser)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
 }
 
 // TODO: Fix
@@ -168,10 +225,10 @@ func TestCreateExistingUserThrowsException(t *testing.T) {
 // 	mockRepo, userService := setupUserService()
 // 	userID := 1
 // 	mockRepo.On("GetAll").Return([]entities.UserEntity{getUserEntities()[0]})
-// 	mockRepo.On("DeleteByID", userID).Return(true)
+// 	mockRepo.On("DeleteByID", userID, 1).Return(true)
 
 // 	// Act
-// 	isDeleted, err := userService.DeleteByID(userID)
+// 	isDeleted, err := userService.DeleteByID(userID, 1)
 
 // 	// Assert
 // 	assert.NoError(t, err)
@@ -183,10 +240,10 @@ func TestDeleteByNonExistingIDThrowsException(t *testing.T) {
 	mockRepo, userService := setupUserService()
 	userID := 999
 	mockRepo.On("GetAll").Return([]entities.UserEntity{getUserEntities()[1]})
-	mockRepo.On("DeleteByID", userID).Return(false)
+	mockRepo.On("DeleteByID", userID, 1).Return(false)
 
 	// Act
-	isDeleted, err := userService.DeleteByID(userID)
+	isDeleted, err := userService.DeleteByID(userID, 1)
 
 	// Assert
 	assert.Error(t, err)
@@ -194,44 +251,303 @@ func TestDeleteByNonExistingIDThrowsException(t *testing.T) {
 	assert.False(t, isDeleted)
 }
 
+func TestDeleteUsingStaleVersionThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	userID := 1
+	mockRepo.On("GetAll").Return([]entities.UserEntity{getUserEntities()[0]})
+	mockRepo.On("DeleteByID", userID, 0).Return(false)
+	mockRepo.On("GetByID", userID).Return(getUserEntities()[0])
+
+	// Act
+	isDeleted, err := userService.DeleteByID(userID, 0)
+
+	// Assert
+	assert.Equal(t, errors.NewConcurrentModificationError(userID, 412), err)
+	assert.False(t, isDeleted)
+}
+
+func TestDeleteFailingWithCurrentVersionIsNotAConflict(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	userEntity := getUserEntities()[0]
+	mockRepo.On("GetAll").Return([]entities.UserEntity{userEntity})
+	mockRepo.On("DeleteByID", userEntity.ID, userEntity.Version).Return(false)
+	mockRepo.On("GetByID", userEntity.ID).Return(userEntity)
+
+	// Act
+	isDeleted, err := userService.DeleteByID(userEntity.ID, userEntity.Version)
+
+	// Assert
+	assert.EqualError(t, err, "failed to delete user 1")
+	assert.False(t, isDeleted)
+}
+
 func TestUpdateByExistingUserReturnsUpdatedUser(t *testing.T) {
 	// Arrange
 	mockRepo, userService := setupUserService()
-	user := getUsers()[0]
+	user := getUpdateUserRequests()[0]
+	user.FullName = "John Smith"
 	userEntity := getUserEntities()[0]
 
 	mockRepo.On("GetAll").Return(getUserEntities())
-	mockRepo.On("Update", mock.MatchedBy(func(u entities.UserEntity) bool {
-		return u.ID == userEntity.ID
-	})).Return(userEntity)
+	mockRepo.On("GetByID", user.ID).Return(userEntity)
+	mockRepo.On("UpdateColumns", user.ID, 1, map[string]interface{}{"FullName": "John Smith"}).Return(true)
 
 	// Act
-	putUser, err := userService.Update(user)
+	putUser, err := userService.Update(user, 1)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, user.ID, putUser.ID)
 	assert.Equal(t, user.FullName, putUser.FullName)
-	assert.Equal(t, user.Email, putUser.Email)
-	assert.Equal(t, user.AccountType, putUser.AccountType)
+	assert.Equal(t, userEntity.Email, putUser.Email)
+	assert.Equal(t, enums.User, putUser.AccountType)
+	assert.Equal(t, userEntity.Version+1, putUser.Version)
+	mockRepo.AssertExpectations(t)
 }
 
-func TestUpdateByNonExistingUserReturnsUpdatedUser(t *testing.T) {
+func TestUpdateWithoutChangesDoesNotWriteColumns(t *testing.T) {
 	// Arrange
 	mockRepo, userService := setupUserService()
-	user := getUsers()[0]
+	user := getUpdateUserRequests()[0]
 	userEntity := getUserEntities()[0]
+	mockRepo.On("GetAll").Return(getUserEntities())
+	mockRepo.On("GetByID", user.ID).Return(userEntity)
+
+	// Act
+	putUser, err := userService.Update(user, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, user.FullName, putUser.FullName)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateUsingStaleVersionThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	user := getUpdateUserRequests()[0]
+	user.FullName = "John Smith"
+	userEntity := getUserEntities()[0]
+	userEntity.Version = 2
+	mockRepo.On("GetAll").Return(getUserEntities())
+	mockRepo.On("GetByID", user.ID).Return(userEntity)
+
+	// Act
+	putUser, err := userService.Update(user, 1)
+
+	// Assert
+	assert.Equal(t, errors.NewConcurrentModificationError(user.ID, 412), err)
+	assert.Nil(t, putUser)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateRacingAnotherUpdateThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	user := getUpdateUserRequests()[0]
+	user.FullName = "John Smith"
+	mockRepo.On("GetAll").Return(getUserEntities())
+	mockRepo.On("GetByID", user.ID).Return(getUserEntities()[0])
+	// Another update raised the version after the account was read
+	mockRepo.On("UpdateColumns", user.ID, 1, map[string]interface{}{"FullName": "John Smith"}).Return(false)
+
+	// Act
+	putUser, err := userService.Update(user, 1)
+
+	// Assert
+	assert.Equal(t, errors.NewConcurrentModificationError(user.ID, 412), err)
+	assert.Nil(t, putUser)
+}
+
+func TestUpdateByNonExistingUserReturnsUpdatedUser(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	user := getUpdateUserRequests()[0]
 
 	mockRepo.On("GetAll").Return([]entities.UserEntity{})
-	mockRepo.On("Update", mock.MatchedBy(func(u entities.UserEntity) bool {
-		return u.ID == userEntity.ID
-	})).Return(userEntity)
 
 	// Act
-	putUser, err := userService.Update(user)
+	putUser, err := userService.Update(user, 1)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, errors.NewUserNotFoundError(user.ID, 404), err)
 	assert.Nil(t, putUser)
 }
+
+func TestCreateUserStartsUnverifiedAndSendsVerification(t *testing.T) {
+	// Arrange
+	mockRepo, mockEmailVerificationService, userService := setupUserServiceWithEmailVerification()
+	user := getCreateUserRequests()[0]
+	user.Password = "Sup3r$ecurePassw0rd"
+	mockRepo.On("GetByEmail", user.Email).Return(entities.UserEntity{})
+	mockRepo.On("Create", mock.MatchedBy(func(u entities.UserEntity) bool {
+		return u.Email == user.Email && !u.EmailVerified
+	})).Return(getUserEntities()[0])
+	mockEmailVerificationService.On("SendVerification", mock.MatchedBy(func(u models.User) bool {
+		return u.ID == getUserEntities()[0].ID && u.Email == user.Email
+	})).Return(nil)
+
+	// Act
+	postUser, err := userService.Create(user)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, postUser.EmailVerified)
+	mockEmailVerificationService.AssertExpectations(t)
+}
+
+func TestUpdateUserDoesNotChangeEmail(t *testing.T) {
+	// Arrange
+	mockRepo, mockEmailVerificationService, userService := setupUserServiceWithEmailVerification()
+	storedUserEntity := getUserEntities()[0]
+	storedUserEntity.EmailVerified = true
+	user := getUpdateUserRequests()[0]
+	user.FullName = "John Smith"
+	mockRepo.On("GetAll").Return(getUserEntities())
+	mockRepo.On("GetByID", user.ID).Return(storedUserEntity)
+	mockRepo.On("UpdateColumns", user.ID, 1, mock.MatchedBy(func(columns map[string]interface{}) bool {
+		_, changesEmail := columns["Email"]
+		_, changesVerification := columns["EmailVerified"]
+		return !changesEmail && !changesVerification
+	})).Return(true)
+
+	// Act
+	putUser, err := userService.Update(user, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "john@doe.it", putUser.Email)
+	mockRepo.AssertExpectations(t)
+	mockEmailVerificationService.AssertNotCalled(t, "SendVerification", mock.Anything)
+}
+
+func TestUpdateUserKeepsVerificationOfUnchangedEmail(t *testing.T) {
+	// Arrange
+	mockRepo, mockEmailVerificationService, userService := setupUserServiceWithEmailVerification()
+	storedUserEntity := getUserEntities()[0]
+	storedUserEntity.EmailVerified = true
+	user := getUpdateUserRequests()[0]
+	mockRepo.On("GetAll").Return(getUserEntities())
+	mockRepo.On("GetByID", user.ID).Return(storedUserEntity)
+
+	// Act
+	putUser, err := userService.Update(user, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, putUser.EmailVerified)
+	mockEmailVerificationService.AssertNotCalled(t, "SendVerification", mock.Anything)
+}
+
+func TestPatchFullNameAsUserWritesChangedColumn(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockRepo.On("UpdateColumns", 1, 1, map[string]interface{}{"FullName": "John Smith"}).Return(true)
+	changes := map[string]json.RawMessage{"full_name": json.RawMessage(`"John Smith"`)}
+
+	// Act
+	patchedUser, err := userService.Patch(1, changes, "user", 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "John Smith", patchedUser.FullName)
+	assert.Equal(t, "john@doe.it", patchedUser.Email)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchAccountTypeAsUserThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	changes := map[string]json.RawMessage{"account_type": json.RawMessage(`0`)}
+
+	// Act
+	patchedUser, err := userService.Patch(1, changes, "user", 1)
+
+	// Assert
+	assert.Equal(t, errors.NewFieldNotPatchableError("account_type", 403), err)
+	assert.Nil(t, patchedUser)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchAccountTypeAsAdminWritesChangedColumn(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockRepo.On("UpdateColumns", 1, 1, map[string]interface{}{"AccountType": int(enums.Admin)}).Return(true)
+	changes := map[string]json.RawMessage{"account_type": json.RawMessage(`0`), "full_name": json.RawMessage(`"John Doe"`)}
+
+	// Act
+	patchedUser, err := userService.Patch(1, changes, "admin", 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, enums.Admin, patchedUser.AccountType)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchPasswordThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	changes := map[string]json.RawMessage{"password": json.RawMessage(`"Sup3r$ecurePassw0rd"`)}
+
+	// Act
+	_, err := userService.Patch(1, changes, "admin", 1)
+
+	// Assert
+	assert.Equal(t, errors.NewFieldNotPatchableError("password", 403), err)
+}
+
+func TestPatchUsingInvalidValueThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+
+	for _, changes := range []map[string]json.RawMessage{
+		{"full_name": json.RawMessage(`null`)},
+		{"full_name": json.RawMessage(`"  "`)},
+		{"account_type": json.RawMessage(`7`)},
+		{"nickname": json.RawMessage(`"Johnny"`)},
+	} {
+		// Act
+		_, err := userService.Patch(1, changes, "admin", 1)
+
+		// Assert
+		assert.IsType(t, &errors.InvalidPatchError{}, err)
+	}
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchNonExistingUserThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	mockRepo.On("GetByID", 999).Return(entities.UserEntity{})
+
+	// Act
+	patchedUser, err := userService.Patch(999, map[string]json.RawMessage{"full_name": json.RawMessage(`"John Smith"`)}, "user", 1)
+
+	// Assert
+	assert.Equal(t, errors.NewUserNotFoundError(999, 404), err)
+	assert.Nil(t, patchedUser)
+}
+
+func TestPatchUsingStaleVersionThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, userService := setupUserService()
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	// The precondition fails before the patch is validated
+	changes := map[string]json.RawMessage{"nickname": json.RawMessage(`"Johnny"`)}
+
+	// Act
+	patchedUser, err := userService.Patch(1, changes, "user", 2)
+
+	// Assert
+	assert.Equal(t, errors.NewConcurrentModificationError(1, 412), err)
+	assert.Nil(t, patchedUser)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything, mock.Anything)
+}
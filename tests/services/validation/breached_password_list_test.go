@@ -0,0 +1,61 @@
+package validation_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"flyhorizons-userservice/services/errors"
+	"flyhorizons-userservice/services/validation"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestBreachedPasswordList struct {
+}
+
+// Setup
+func sha1Hex(password string) string {
+	hash := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+// Breached Password List Tests
+func TestLoadBreachedPasswordListReadsCompressedHashesWithCounts(t *testing.T) {
+	// Arrange
+	content := sha1Hex("Sup3r$ecurePassw0rd") + ":42\n" + sha1Hex("Fontysict1234!") + ":7\n\n" + sha1Hex("Sup3r$ecurePassw0rd") + ":1\n"
+	filepath := writePasswordList(t, "breached.txt.gz", content, true)
+
+	// Act
+	list, err := validation.LoadBreachedPasswordList(filepath)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains("Sup3r$ecurePassw0rd"))
+	assert.False(t, list.Contains("An0ther$ecurePassw0rd"))
+}
+
+func TestLoadBreachedPasswordListUsingInvalidHashThrowsException(t *testing.T) {
+	// Arrange
+	filepath := writePasswordList(t, "breached.txt", sha1Hex("Sup3r$ecurePassw0rd")+"\nnot-a-hash\n", false)
+
+	// Act
+	_, err := validation.LoadBreachedPasswordList(filepath)
+
+	// Assert
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestValidateBreachedPasswordThrowsException(t *testing.T) {
+	// Arrange
+	passwordValidator := setup()
+	filepath := writePasswordList(t, "breached.txt", sha1Hex("Sup3r$ecurePassw0rd")+":3\n", false)
+	assert.NoError(t, passwordValidator.LoadBreachedPasswords(filepath))
+
+	// Act
+	err := passwordValidator.Validate("Sup3r$ecurePassw0rd")
+
+	// Assert
+	assert.Equal(t, errors.NewCommonPasswordError(400), err)
+}
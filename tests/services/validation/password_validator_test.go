@@ -1,8 +1,12 @@
 package validation_test
 
 import (
+	"compress/gzip"
+	"flyhorizons-userservice/models/enums"
 	"flyhorizons-userservice/services/errors"
 	"flyhorizons-userservice/services/validation"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,6 +20,23 @@ func setup() validation.PasswordValidator {
 	return validation.PasswordValidator{}
 }
 
+// Writes a password list to a temporary file, optionally gzip compressed
+func writePasswordList(t *testing.T, name string, content string, compressed bool) string {
+	path := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	if compressed {
+		writer := gzip.NewWriter(file)
+		writer.Write([]byte(content))
+		assert.NoError(t, writer.Close())
+	} else {
+		file.Write([]byte(content))
+	}
+	return path
+}
+
 // Validator Tests
 func TestValidateSecurePasswordReturnsNil(t *testing.T) {
 	// Arrange
@@ -70,3 +91,72 @@ func TestValidateCommonPasswordThrowsException(t *testing.T) {
 	_, ok := err.(*errors.CommonPasswordError)
 	assert.False(t, ok)
 }
+
+func TestValidateForAdminUsesStricterPolicy(t *testing.T) {
+	// Arrange
+	passwordValidator := setup()
+	passwordValidator.SetPolicy(enums.Admin, validation.DefaultAdminPasswordPolicy())
+	password := "Fontysict1234!"
+
+	// Act
+	userErr := passwordValidator.ValidateForAccountType(password, enums.User)
+	adminErr := passwordValidator.ValidateForAccountType(password, enums.Admin)
+
+	// Assert
+	assert.NoError(t, userErr)
+	assert.Equal(t, errors.NewInsufficientPasswordLengthError(16, 400), adminErr)
+}
+
+func TestValidateUsingPolicyWithoutSymbolsAcceptsPasswordWithoutSymbol(t *testing.T) {
+	// Arrange
+	passwordValidator := setup()
+	policy := validation.DefaultPasswordPolicy()
+	policy.RequireSpecial = false
+	passwordValidator.SetPolicy(enums.User, policy)
+
+	// Act
+	err := passwordValidator.Validate("Fontysict12345")
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestValidateInsufficientComplexityNamesRequiredClasses(t *testing.T) {
+	// Arrange
+	passwordValidator := setup()
+	passwordValidator.SetPolicy(enums.User, validation.PasswordPolicy{MinLength: 8, RequireUpper: true, RequireNumber: true})
+
+	// Act
+	err := passwordValidator.Validate("lowercasepassword")
+
+	// Assert
+	assert.Equal(t, errors.NewInsufficientPasswordComplexityError([]string{"an uppercase letter", "a number"}, 400), err)
+	assert.Contains(t, err.Error(), "an uppercase letter and a number")
+}
+
+func TestLoadPasswordValidatorFromEnvWithoutCommonPasswordsFileUsesBundledList(t *testing.T) {
+	// Arrange
+	t.Setenv("COMMON_PASSWORDS_FILE", "")
+	t.Setenv("BREACHED_PASSWORDS_FILE", "")
+	passwordValidator, err := validation.LoadPasswordValidatorFromEnv()
+	assert.NoError(t, err)
+
+	// Act
+	err = passwordValidator.Validate("123456")
+
+	// Assert
+	assert.Equal(t, errors.NewCommonPasswordError(400), err)
+}
+
+func TestValidateUsingLoadedCommonPasswordsThrowsException(t *testing.T) {
+	// Arrange
+	filepath := writePasswordList(t, "common.txt.gz", "Fontysict1234!\nSup3r$ecurePassw0rd\n", true)
+	passwordValidator, err := validation.NewPasswordValidator(filepath)
+	assert.NoError(t, err)
+
+	// Act
+	err = passwordValidator.Validate("Sup3r$ecurePassw0rd")
+
+	// Assert
+	assert.Equal(t, errors.NewCommonPasswordError(400), err)
+}
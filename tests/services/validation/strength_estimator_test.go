@@ -0,0 +1,138 @@
+package validation_test
+
+import (
+	"flyhorizons-userservice/models"
+	"flyhorizons-userservice/services/validation"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestStrengthEstimator struct {
+}
+
+// Setup
+func getStrengthTestUser() models.User {
+	return models.User{FullName: "John Doe", Email: "john.doe@flyhorizons.com"}
+}
+
+// Strength Estimator Tests
+func TestEstimateRandomPasswordReturnsHighScore(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	strength := estimator.Estimate("xK#9vL2!qP7@mZ4$", getStrengthTestUser())
+
+	// Assert
+	assert.Equal(t, 4, strength.Score)
+	assert.Empty(t, strength.Suggestions)
+}
+
+func TestEstimateDictionaryWordsWithSubstitutionsReturnsLowScore(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	strength := estimator.Estimate("Sup3r$ecurePassw0rd", getStrengthTestUser())
+
+	// Assert
+	assert.LessOrEqual(t, strength.Score, 1)
+	assert.Contains(t, strength.Suggestions, validation.SuggestionDictionary)
+}
+
+func TestEstimateKeyboardSequenceReturnsLowScore(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	strength := estimator.Estimate("zxcvbnmasdfg", getStrengthTestUser())
+
+	// Assert
+	assert.Equal(t, 0, strength.Score)
+	assert.Contains(t, strength.Suggestions, validation.SuggestionSequence)
+}
+
+func TestEstimateRepeatedCharactersReturnsLowScore(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	repeatedCharacter := estimator.Estimate("aaaaaaaaaaaaaaaa", getStrengthTestUser())
+	repeatedChunk := estimator.Estimate("Xy7Xy7Xy7Xy7Xy7", getStrengthTestUser())
+
+	// Assert
+	assert.Equal(t, 0, repeatedCharacter.Score)
+	assert.Equal(t, 0, repeatedChunk.Score)
+	assert.Contains(t, repeatedChunk.Suggestions, validation.SuggestionRepeat)
+}
+
+func TestEstimatePersonalInformationAndDateReturnsLowScore(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	strength := estimator.Estimate("JohnDoe12-05-1990", getStrengthTestUser())
+
+	// Assert
+	assert.Equal(t, 0, strength.Score)
+	assert.Contains(t, strength.Suggestions, validation.SuggestionPersonal)
+	assert.Contains(t, strength.Suggestions, validation.SuggestionDate)
+}
+
+func TestEstimateNameOfOtherUserIsNotPersonal(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	strength := estimator.Estimate("JohnDoe12-05-1990", models.User{FullName: "Jane Roe", Email: "jane@roe.nl"})
+
+	// Assert
+	assert.NotContains(t, strength.Suggestions, validation.SuggestionPersonal)
+}
+
+func TestEstimateShortPasswordSuggestsLongerPassword(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	strength := estimator.Estimate("xK#9vL2!", getStrengthTestUser())
+
+	// Assert
+	assert.Contains(t, strength.Suggestions, validation.SuggestionLength)
+}
+
+func TestEstimateLongPasswordsFinishesQuickly(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+	longPasswords := []string{
+		strings.Repeat("a", validation.MaxEstimatedPasswordLength),
+		strings.Repeat("ab1", validation.MaxEstimatedPasswordLength/3),
+		strings.Repeat("password", validation.MaxEstimatedPasswordLength/8),
+		strings.Repeat("a", 1_000_000),
+	}
+
+	// Act
+	started := time.Now()
+	for _, password := range longPasswords {
+		estimator.Estimate(password, getStrengthTestUser())
+	}
+	elapsed := time.Since(started)
+
+	// Assert
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
+
+func TestEstimatePasswordOverMaximumLengthOnlyCountsLength(t *testing.T) {
+	// Arrange
+	estimator := validation.NewStrengthEstimator()
+
+	// Act
+	strength := estimator.Estimate(strings.Repeat("a", validation.MaxEstimatedPasswordLength+1), getStrengthTestUser())
+
+	// Assert
+	assert.Equal(t, 4, strength.Score)
+	assert.Empty(t, strength.Suggestions)
+}
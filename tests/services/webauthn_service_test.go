@@ -0,0 +1,264 @@
+package services_test
+
+import (
+	"encoding/base64"
+	entities "flyhorizons-userservice/repositories/entity"
+	"flyhorizons-userservice/services"
+	"flyhorizons-userservice/services/authentication"
+	"flyhorizons-userservice/services/errors"
+	mock_repositories "flyhorizons-userservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestWebAuthnService struct {
+}
+
+const (
+	testRPID   = "flyhorizons.test"
+	testOrigin = "https://flyhorizons.test"
+)
+
+// Setup
+func setupWebAuthnService() (*mock_repositories.MockWebAuthnRepository, *mock_repositories.MockUserRepository, *services.WebAuthnService) {
+	mockWebAuthnRepo := new(mock_repositories.MockWebAuthnRepository)
+	mockRepo := new(mock_repositories.MockUserRepository)
+	config := services.WebAuthnConfig{RPID: testRPID, RPName: "FlyHorizons", Origins: []string{testOrigin}}
+	webAuthnService := services.NewWebAuthnService(mockWebAuthnRepo, mockRepo, config)
+	return mockWebAuthnRepo, mockRepo, webAuthnService
+}
+
+// Stores the challenge the service hands out, so the repository returns it when the ceremony finishes
+func mockWebAuthnChallenge(mockWebAuthnRepo *mock_repositories.MockWebAuthnRepository, challenge string, userID int, ceremony string) {
+	mockWebAuthnRepo.On("GetChallengeByHash", authentication.HashOpaqueToken(challenge)).Return(entities.WebAuthnChallengeEntity{
+		ID:            1,
+		ChallengeHash: authentication.HashOpaqueToken(challenge),
+		UserID:        userID,
+		Ceremony:      ceremony,
+		ExpiresAt:     time.Now().Add(time.Minute),
+		CreatedAt:     time.Now(),
+	})
+	mockWebAuthnRepo.On("MarkChallengeAsUsed", 1).Return(true)
+}
+
+func getWebAuthnCredentialEntity(authenticator *mock_repositories.SoftwareAuthenticator, signCount int64) entities.WebAuthnCredentialEntity {
+	return entities.WebAuthnCredentialEntity{
+		ID:           1,
+		UserID:       1,
+		CredentialID: authenticator.EncodedCredentialID(),
+		PublicKey:    authenticator.COSEPublicKey(),
+		SignCount:    signCount,
+		Name:         "Passkey",
+		CreatedAt:    time.Now(),
+	}
+}
+
+func beginPasskeyLogin(t *testing.T, mockWebAuthnRepo *mock_repositories.MockWebAuthnRepository, webAuthnService *services.WebAuthnService) string {
+	mockWebAuthnRepo.On("CreateChallenge", mock.Anything).Return(entities.WebAuthnChallengeEntity{})
+	options, err := webAuthnService.BeginLogin()
+	assert.NoError(t, err)
+	mockWebAuthnChallenge(mockWebAuthnRepo, options.Challenge, 0, "login")
+	return options.Challenge
+}
+
+// Service Unit Tests
+func TestBeginPasskeyRegistrationReturnsOptionsExcludingExistingPasskeys(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, mockRepo, webAuthnService := setupWebAuthnService()
+	existing := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	var storedChallenge entities.WebAuthnChallengeEntity
+	mockRepo.On("GetByID", 1).Return(getUserEntities()[0])
+	mockWebAuthnRepo.On("CreateChallenge", mock.Anything).Run(func(args mock.Arguments) {
+		storedChallenge = args.Get(0).(entities.WebAuthnChallengeEntity)
+	}).Return(entities.WebAuthnChallengeEntity{})
+	mockWebAuthnRepo.On("GetCredentialsByUserID", 1).Return([]entities.WebAuthnCredentialEntity{getWebAuthnCredentialEntity(existing, 0)})
+
+	// Act
+	options, err := webAuthnService.BeginRegistration(1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, testRPID, options.RelyingParty.ID)
+	assert.Equal(t, "john@doe.it", options.User.Name)
+	assert.Equal(t, "required", options.AuthenticatorSelection.UserVerification)
+	assert.Equal(t, existing.EncodedCredentialID(), options.ExcludeCredentials[0].ID)
+	// Only the hash of the challenge is stored
+	assert.Equal(t, authentication.HashOpaqueToken(options.Challenge), storedChallenge.ChallengeHash)
+	assert.Equal(t, "registration", storedChallenge.Ceremony)
+}
+
+func TestFinishPasskeyRegistrationUsingSoftwareAuthenticatorStoresCredential(t *testing.T) {
+	for _, attestationFormat := range []string{"none", "packed"} {
+		// Arrange
+		mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+		authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+		authenticator.AttestationFormat = attestationFormat
+		mockWebAuthnChallenge(mockWebAuthnRepo, "registration-challenge", 1, "registration")
+		mockWebAuthnRepo.On("GetCredentialByCredentialID", authenticator.EncodedCredentialID()).Return(entities.WebAuthnCredentialEntity{})
+		mockWebAuthnRepo.On("CreateCredential", mock.MatchedBy(func(credential entities.WebAuthnCredentialEntity) bool {
+			return credential.UserID == 1 && credential.CredentialID == authenticator.EncodedCredentialID() &&
+				string(credential.PublicKey) == string(authenticator.COSEPublicKey()) && credential.Name == "Phone"
+		})).Return(getWebAuthnCredentialEntity(authenticator, 0))
+		registrationRequest := authenticator.Register("registration-challenge")
+		registrationRequest.Name = "Phone"
+
+		// Act
+		passkey, err := webAuthnService.FinishRegistration(1, registrationRequest)
+
+		// Assert
+		assert.NoError(t, err, attestationFormat)
+		assert.Equal(t, authenticator.EncodedCredentialID(), passkey.CredentialID)
+		mockWebAuthnRepo.AssertExpectations(t)
+	}
+}
+
+func TestFinishPasskeyRegistrationFromOtherOriginThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, "https://evil.test")
+	mockWebAuthnChallenge(mockWebAuthnRepo, "registration-challenge", 1, "registration")
+
+	// Act
+	passkey, err := webAuthnService.FinishRegistration(1, authenticator.Register("registration-challenge"))
+
+	// Assert
+	assert.Nil(t, passkey)
+	assert.Equal(t, errors.NewInvalidPasskeyError(400), err)
+	mockWebAuthnRepo.AssertNotCalled(t, "CreateCredential", mock.Anything)
+}
+
+func TestFinishPasskeyRegistrationUsingChallengeOfOtherUserThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	mockWebAuthnChallenge(mockWebAuthnRepo, "registration-challenge", 2, "registration")
+
+	// Act
+	_, err := webAuthnService.FinishRegistration(1, authenticator.Register("registration-challenge"))
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidPasskeyError(400), err)
+	mockWebAuthnRepo.AssertNotCalled(t, "MarkChallengeAsUsed", mock.Anything)
+	mockWebAuthnRepo.AssertNotCalled(t, "CreateCredential", mock.Anything)
+}
+
+func TestFinishPasskeyLoginUsingSoftwareAuthenticatorReturnsUser(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	authenticator.CountSignatures = true
+	authenticator.SignCount = 4
+	challenge := beginPasskeyLogin(t, mockWebAuthnRepo, webAuthnService)
+	mockWebAuthnRepo.On("GetCredentialByCredentialID", authenticator.EncodedCredentialID()).Return(getWebAuthnCredentialEntity(authenticator, 4))
+	mockWebAuthnRepo.On("UpdateSignCount", 1, int64(4), int64(5)).Return(true)
+	userHandle := base64.RawURLEncoding.EncodeToString([]byte("1"))
+
+	// Act
+	userID, err := webAuthnService.FinishLogin(authenticator.Login(challenge, userHandle))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userID)
+	mockWebAuthnRepo.AssertExpectations(t)
+}
+
+func TestFinishPasskeyLoginUsingOtherKeyThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	registered := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	// Same credential ID, but signed with a different private key
+	impostor := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	impostor.CredentialID = registered.CredentialID
+	challenge := beginPasskeyLogin(t, mockWebAuthnRepo, webAuthnService)
+	mockWebAuthnRepo.On("GetCredentialByCredentialID", registered.EncodedCredentialID()).Return(getWebAuthnCredentialEntity(registered, 0))
+
+	// Act
+	userID, err := webAuthnService.FinishLogin(impostor.Login(challenge, ""))
+
+	// Assert
+	assert.Equal(t, 0, userID)
+	assert.Equal(t, errors.NewInvalidPasskeyError(401), err)
+	mockWebAuthnRepo.AssertNotCalled(t, "UpdateSignCount", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFinishPasskeyLoginWithoutIncreasingSignCountThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	// A clone of the authenticator reports a counter that was already used
+	authenticator.SignCount = 7
+	challenge := beginPasskeyLogin(t, mockWebAuthnRepo, webAuthnService)
+	mockWebAuthnRepo.On("GetCredentialByCredentialID", authenticator.EncodedCredentialID()).Return(getWebAuthnCredentialEntity(authenticator, 7))
+
+	// Act
+	_, err := webAuthnService.FinishLogin(authenticator.Login(challenge, ""))
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidPasskeyError(401), err)
+	mockWebAuthnRepo.AssertNotCalled(t, "UpdateSignCount", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFinishPasskeyLoginWithoutUserVerificationThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	authenticator.UserVerified = false
+	challenge := beginPasskeyLogin(t, mockWebAuthnRepo, webAuthnService)
+	mockWebAuthnRepo.On("GetCredentialByCredentialID", authenticator.EncodedCredentialID()).Return(getWebAuthnCredentialEntity(authenticator, 0))
+
+	// Act
+	_, err := webAuthnService.FinishLogin(authenticator.Login(challenge, ""))
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidPasskeyError(401), err)
+}
+
+func TestFinishPasskeyLoginUsingUsedChallengeThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	usedAt := time.Now()
+	mockWebAuthnRepo.On("GetChallengeByHash", authentication.HashOpaqueToken("login-challenge")).Return(entities.WebAuthnChallengeEntity{
+		ID:        1,
+		Ceremony:  "login",
+		ExpiresAt: time.Now().Add(time.Minute),
+		UsedAt:    &usedAt,
+	})
+	mockWebAuthnRepo.On("GetCredentialByCredentialID", authenticator.EncodedCredentialID()).Return(getWebAuthnCredentialEntity(authenticator, 0))
+
+	// Act
+	_, err := webAuthnService.FinishLogin(authenticator.Login("login-challenge", ""))
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidPasskeyError(401), err)
+	mockWebAuthnRepo.AssertNotCalled(t, "UpdateSignCount", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFinishPasskeyLoginUsingRegistrationChallengeThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	authenticator := mock_repositories.NewSoftwareAuthenticator(testRPID, testOrigin)
+	mockWebAuthnChallenge(mockWebAuthnRepo, "registration-challenge", 1, "registration")
+	mockWebAuthnRepo.On("GetCredentialByCredentialID", authenticator.EncodedCredentialID()).Return(getWebAuthnCredentialEntity(authenticator, 0))
+
+	// Act
+	_, err := webAuthnService.FinishLogin(authenticator.Login("registration-challenge", ""))
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidPasskeyError(401), err)
+}
+
+func TestDeleteUnknownPasskeyThrowsException(t *testing.T) {
+	// Arrange
+	mockWebAuthnRepo, _, webAuthnService := setupWebAuthnService()
+	mockWebAuthnRepo.On("DeleteCredential", 1, "unknown").Return(false)
+
+	// Act
+	err := webAuthnService.DeleteCredential(1, "unknown")
+
+	// Assert
+	assert.Equal(t, errors.NewPasskeyNotFoundError(404), err)
+}
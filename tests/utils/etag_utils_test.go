@@ -0,0 +1,38 @@
+package utils_test
+
+import (
+	"flyhorizons-userservice/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestETagUtils struct {
+}
+
+func TestFormatETagQuotesVersion(t *testing.T) {
+	// Act
+	etag := utils.FormatETag(3)
+
+	// Assert
+	assert.Equal(t, `"3"`, etag)
+}
+
+func TestParseIfMatchReturnsVersion(t *testing.T) {
+	// Act
+	version, err := utils.ParseIfMatch(utils.FormatETag(3))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, version)
+}
+
+func TestParseIfMatchUsingWeakOrInvalidETagReturnsError(t *testing.T) {
+	for _, header := range []string{`W/"3"`, `*`, `3`, `"three"`, `"3", "4"`, `"`} {
+		// Act
+		_, err := utils.ParseIfMatch(header)
+
+		// Assert
+		assert.Error(t, err, header)
+	}
+}
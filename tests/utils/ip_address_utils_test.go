@@ -0,0 +1,127 @@
+package utils_test
+
+import (
+	"flyhorizons-userservice/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestIPAddressUtils struct {
+}
+
+// Setup
+func setupIPResolver(t *testing.T, trustedProxies ...string) *utils.IPResolver {
+	resolver, err := utils.NewIPResolver(trustedProxies)
+	assert.NoError(t, err)
+	return resolver
+}
+
+func newForwardedRequest(remoteAddr string, headers map[string]string) *http.Request {
+	httpRequest, _ := http.NewRequest("GET", "/", nil)
+	httpRequest.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		httpRequest.Header.Set(name, value)
+	}
+	return httpRequest
+}
+
+// Utility Unit Tests
+func TestResolveFromUntrustedPeerIgnoresForwardedFor(t *testing.T) {
+	// Arrange
+	resolver := setupIPResolver(t, "10.0.0.0/8")
+	httpRequest := newForwardedRequest("203.0.113.7:51234", map[string]string{"X-Forwarded-For": "1.2.3.4"})
+
+	// Act
+	clientIP := resolver.Resolve(httpRequest)
+
+	// Assert
+	assert.Equal(t, "203.0.113.7", clientIP.Address)
+	assert.Equal(t, []string{"1.2.3.4", "203.0.113.7"}, clientIP.Chain)
+}
+
+func TestResolveStopsAtFirstUntrustedHop(t *testing.T) {
+	// Arrange
+	resolver := setupIPResolver(t, "10.0.0.0/8")
+	// The client prepended a spoofed address before reaching the proxies
+	httpRequest := newForwardedRequest("10.0.0.2:51234", map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.9, 10.0.0.1"})
+
+	// Act
+	clientIP := resolver.Resolve(httpRequest)
+
+	// Assert
+	assert.Equal(t, "198.51.100.9", clientIP.Address)
+	assert.Equal(t, []string{"1.2.3.4", "198.51.100.9", "10.0.0.1", "10.0.0.2"}, clientIP.Chain)
+}
+
+func TestResolveFromIPv6LoopbackProxyUsesForwardedFor(t *testing.T) {
+	// Arrange
+	resolver := setupIPResolver(t, "::1")
+	httpRequest := newForwardedRequest("[::1]:51234", map[string]string{"X-Forwarded-For": "198.51.100.9"})
+
+	// Act
+	clientIP := resolver.Resolve(httpRequest)
+
+	// Assert
+	assert.Equal(t, "198.51.100.9", clientIP.Address)
+	assert.Equal(t, []string{"198.51.100.9", "::1"}, clientIP.Chain)
+}
+
+func TestResolveUsingForwardedHeader(t *testing.T) {
+	// Arrange
+	resolver := setupIPResolver(t, "10.0.0.0/8")
+	httpRequest := newForwardedRequest("10.0.0.2:51234", map[string]string{
+		"Forwarded":       `for="[2001:db8:cafe::17]:4711";proto=https, for=10.0.0.1;by=10.0.0.2`,
+		"X-Forwarded-For": "1.2.3.4",
+	})
+
+	// Act
+	clientIP := resolver.Resolve(httpRequest)
+
+	// Assert
+	assert.Equal(t, "2001:db8:cafe::17", clientIP.Address)
+}
+
+func TestResolveUsingUnknownHopKeepsLastTrustedProxy(t *testing.T) {
+	// Arrange
+	resolver := setupIPResolver(t, "10.0.0.0/8")
+	httpRequest := newForwardedRequest("10.0.0.2:51234", map[string]string{"Forwarded": "for=unknown, for=10.0.0.1"})
+
+	// Act
+	clientIP := resolver.Resolve(httpRequest)
+
+	// Assert
+	assert.Equal(t, "10.0.0.1", clientIP.Address)
+}
+
+func TestNewIPResolverUsingInvalidCIDRThrowsException(t *testing.T) {
+	// Act
+	_, err := utils.NewIPResolver([]string{"10.0.0.0/33"})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestClientIPMiddlewareStoresResolvedIPInContext(t *testing.T) {
+	// Arrange
+	resolver := setupIPResolver(t, "10.0.0.1")
+	router := gin.Default()
+	router.Use(resolver.ClientIPMiddleware())
+	var ipAddress string
+	var chain interface{}
+	router.GET("/", func(c *gin.Context) {
+		ipAddress = utils.GetIPAddress(c.Request)
+		chain, _ = c.Get("forwarded_chain")
+	})
+	httpRequest := newForwardedRequest("10.0.0.1:51234", map[string]string{"X-Forwarded-For": "198.51.100.9"})
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), httpRequest)
+
+	// Assert
+	assert.Equal(t, "198.51.100.9", ipAddress)
+	assert.Equal(t, []string{"198.51.100.9", "10.0.0.1"}, chain)
+}
@@ -0,0 +1,60 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"flyhorizons-userservice/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestJSONPatchUtils struct {
+}
+
+// Merge Patch Tests
+func TestParseMergePatchReturnsTopLevelFields(t *testing.T) {
+	// Act
+	changes, err := utils.ParseMergePatch([]byte(`{"full_name": "John Smith", "email_verified": null}`))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`"John Smith"`), changes["full_name"])
+	assert.Equal(t, json.RawMessage(`null`), changes["email_verified"])
+}
+
+func TestParseMergePatchUsingNonObjectReturnsError(t *testing.T) {
+	// Act
+	_, arrayErr := utils.ParseMergePatch([]byte(`[{"op": "replace"}]`))
+	_, invalidErr := utils.ParseMergePatch([]byte(`{"full_name": `))
+
+	// Assert
+	assert.Error(t, arrayErr)
+	assert.Error(t, invalidErr)
+}
+
+// JSON Patch Tests
+func TestParseJSONPatchAppliesOperationsInOrder(t *testing.T) {
+	// Act
+	changes, err := utils.ParseJSONPatch([]byte(`[
+		{"op": "replace", "path": "/full_name", "value": "John Smith"},
+		{"op": "add", "path": "/full_name", "value": "John Doe"},
+		{"op": "remove", "path": "/email_verified"}
+	]`))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`"John Doe"`), changes["full_name"])
+	assert.Equal(t, json.RawMessage(`null`), changes["email_verified"])
+}
+
+func TestParseJSONPatchUsingUnsupportedOperationReturnsError(t *testing.T) {
+	// Act
+	_, moveErr := utils.ParseJSONPatch([]byte(`[{"op": "move", "from": "/email", "path": "/full_name"}]`))
+	_, nestedErr := utils.ParseJSONPatch([]byte(`[{"op": "replace", "path": "/address/city", "value": "Eindhoven"}]`))
+	_, missingValueErr := utils.ParseJSONPatch([]byte(`[{"op": "replace", "path": "/full_name"}]`))
+
+	// Assert
+	assert.Error(t, moveErr)
+	assert.Error(t, nestedErr)
+	assert.Error(t, missingValueErr)
+}
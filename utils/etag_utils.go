@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formats the version of a resource as a strong entity tag
+func FormatETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// Reads the version from an If-Match header, only a single strong entity tag is accepted
+// because updates need to know exactly which version the caller last read
+func ParseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) || len(header) < 2 {
+		return 0, fmt.Errorf("If-Match must be a single strong entity tag")
+	}
+
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil {
+		return 0, fmt.Errorf("If-Match does not contain a version of this resource")
+	}
+
+	return version, nil
+}
@@ -1,36 +1,189 @@
 package utils
 
 import (
+	"context"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"strings"
+
+	"github.com/gin-gonic/gin"
 )
 
-func GetIPAddress(r *http.Request) string {
-	// Prefer the X-Forwarded-For header if it's present
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		// Handle comma-separated list if behind multiple proxies
-		ips := strings.Split(forwarded, ",")
-		return strings.TrimSpace(ips[0])
+// Client IP resolved from the proxy headers, Chain holds every hop as reported by the
+// Forwarded or X-Forwarded-For header followed by the address of the direct peer
+type ClientIP struct {
+	Address string
+	Chain   []string
+}
+
+type clientIPContextKey struct{}
+
+// Only hops appended by trusted proxies are believed, anything to the left of the first
+// untrusted hop can be set by the client
+type IPResolver struct {
+	trustedProxies []*net.IPNet
+}
+
+func NewIPResolver(trustedProxies []string) (*IPResolver, error) {
+	resolver := &IPResolver{}
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		// Single addresses are accepted as well
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		resolver.trustedProxies = append(resolver.trustedProxies, network)
+	}
+	return resolver, nil
+}
+
+// TRUSTED_PROXIES holds a comma separated list of CIDRs, without it the proxy headers are ignored
+func LoadIPResolverFromEnv() (*IPResolver, error) {
+	return NewIPResolver(strings.Split(os.Getenv("TRUSTED_PROXIES"), ","))
+}
+
+func (resolver *IPResolver) Resolve(r *http.Request) ClientIP {
+	peer := remoteIP(r)
+	hops := forwardedHops(r)
+	clientIP := ClientIP{Address: peer, Chain: append(hops, peer)}
+
+	// Headers sent by the client itself are never trusted
+	if !resolver.isTrusted(peer) {
+		return clientIP
+	}
+
+	if len(hops) == 0 {
+		// Fall back to X-Real-IP header (used by some proxies)
+		if realIP := normalizeIP(r.Header.Get("X-Real-IP")); realIP != "" {
+			clientIP.Address = realIP
+		}
+		return clientIP
+	}
+
+	// Walk from the closest hop to the client and stop at the first hop that is not a trusted proxy
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := normalizeIP(hops[i])
+		if hop == "" {
+			// Unknown or obfuscated hops can't be attributed, keep the last known address
+			break
+		}
+		clientIP.Address = hop
+		if !resolver.isTrusted(hop) {
+			break
+		}
+	}
+	return clientIP
+}
+
+// Stores the resolved client IP in the request context, must be registered before any
+// middleware or route that calls GetIPAddress
+func (resolver *IPResolver) ClientIPMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		clientIP := resolver.Resolve(ctx.Request)
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), clientIPContextKey{}, clientIP))
+		ctx.Set("client_ip", clientIP.Address)
+		ctx.Set("forwarded_chain", clientIP.Chain)
+		ctx.Next()
 	}
+}
 
-	// Fall back to X-Real-IP header (used by some proxies)
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" {
-		return realIP
+func (resolver *IPResolver) isTrusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range resolver.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
 	}
+	return false
+}
+
+// Returns the client IP resolved by the ClientIPMiddleware, without it only the direct peer is known
+func GetIPAddress(r *http.Request) string {
+	return GetClientIP(r).Address
+}
 
-	// Fall back to RemoteAddr
+func GetClientIP(r *http.Request) ClientIP {
+	if clientIP, ok := r.Context().Value(clientIPContextKey{}).(ClientIP); ok {
+		return clientIP
+	}
+	peer := remoteIP(r)
+	return ClientIP{Address: peer, Chain: []string{peer}}
+}
+
+func remoteIP(r *http.Request) string {
 	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
 		ip = r.RemoteAddr
 	}
+	if normalized := normalizeIP(ip); normalized != "" {
+		return normalized
+	}
+	return ip
+}
 
-	// Normalize IPv6 loopback to IPv4 for clarity
-	if ip == "::1" {
-		ip = "127.0.0.1"
+// Prefers the RFC 7239 Forwarded header over X-Forwarded-For, hops are ordered from the client
+// to the last proxy
+func forwardedHops(r *http.Request) []string {
+	var hops []string
+	if forwarded := r.Header.Values("Forwarded"); len(forwarded) > 0 {
+		for _, element := range strings.Split(strings.Join(forwarded, ","), ",") {
+			hops = append(hops, forwardedFor(element))
+		}
+		return hops
 	}
 
-	return ip
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		// Handle comma-separated list if behind multiple proxies
+		for _, hop := range strings.Split(strings.Join(forwarded, ","), ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+// Extracts the for parameter of a Forwarded element, e.g. for="[2001:db8::1]:4711";proto=https
+func forwardedFor(element string) string {
+	for _, pair := range strings.Split(element, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(key), "for") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if strings.HasPrefix(value, "[") {
+			if end := strings.Index(value, "]"); end > 0 {
+				return value[1:end]
+			}
+		}
+		if host, _, err := net.SplitHostPort(value); err == nil {
+			return host
+		}
+		return value
+	}
+	return ""
+}
+
+// Returns an empty string for anything that is not an IP address, such as "unknown" or "_hidden".
+// Loopback addresses keep their family, so a proxy trusted as ::1 is matched as ::1.
+func normalizeIP(address string) string {
+	ip := net.ParseIP(strings.TrimSpace(address))
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
 }
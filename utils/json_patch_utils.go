@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Media types accepted by PATCH endpoints
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// Single operation of a JSON Patch document (RFC 6902)
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Reads a JSON Merge Patch document (RFC 7396) into the top level fields it changes,
+// a field set to null is kept as a raw null so the caller can decide whether it may be removed
+func ParseMergePatch(body []byte) (map[string]json.RawMessage, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return nil, fmt.Errorf("merge patch must be a JSON object")
+	}
+
+	var changes map[string]json.RawMessage
+	if err := json.Unmarshal(body, &changes); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %v", err)
+	}
+
+	return changes, nil
+}
+
+// Reads a JSON Patch document (RFC 6902) into the same shape as a merge patch. Only add, replace
+// and remove on top level fields are supported, later operations on a field win like they would
+// when applied in order.
+func ParseJSONPatch(body []byte) (map[string]json.RawMessage, error) {
+	var operations []JSONPatchOperation
+	if err := json.Unmarshal(body, &operations); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %v", err)
+	}
+
+	changes := make(map[string]json.RawMessage, len(operations))
+	for _, operation := range operations {
+		field, err := topLevelField(operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operation.Op {
+		case "add", "replace":
+			if len(operation.Value) == 0 {
+				return nil, fmt.Errorf("%s operation on %s requires a value", operation.Op, operation.Path)
+			}
+			changes[field] = operation.Value
+		case "remove":
+			changes[field] = json.RawMessage("null")
+		default:
+			return nil, fmt.Errorf("unsupported JSON patch operation %q", operation.Op)
+		}
+	}
+
+	return changes, nil
+}
+
+// Turns a JSON Pointer (RFC 6901) such as /full_name into the field name
+func topLevelField(path string) (string, error) {
+	if !strings.HasPrefix(path, "/") || len(path) == 1 || strings.Contains(path[1:], "/") {
+		return "", fmt.Errorf("unsupported JSON patch path %q, only top level fields can be changed", path)
+	}
+
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(path[1:]), nil
+}